@@ -0,0 +1,271 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+// An archive holds manifest.json, written first so that import can stream
+// the rest, followed by the data of every object under objects/.
+const (
+	archiveManifestName = "manifest.json"
+	archiveObjectsDir   = "objects/"
+	archiveVersion      = 1
+)
+
+// archiveManifest describes the objects of an archive.
+type archiveManifest struct {
+	Version int
+	Source  string
+	Created time.Time
+	Objects []archiveObject
+}
+
+// archiveObject is an object of an archive. Key is relative to the exported
+// prefix.
+type archiveObject struct {
+	Key          string
+	Path         string
+	Size         int64
+	ETag         string
+	LastModified time.Time
+	StorageClass string `json:",omitempty"`
+	cloud.ObjectMetadata
+	Tags map[string]string `json:",omitempty"`
+}
+
+// archiveFormats are the archive formats, by file extension.
+var archiveFormats = []struct{ ext, format string }{
+	{".tar.zst", "tar.zst"},
+	{".tzst", "tar.zst"},
+	{".tar.gz", "tar.gz"},
+	{".tgz", "tar.gz"},
+	{".tar", "tar"},
+	{".zip", "zip"},
+}
+
+// archiveFormat returns the format of an archive from --format or the name
+// of the file.
+func archiveFormat(path, format string) (string, error) {
+	if format != "" {
+		for _, f := range archiveFormats {
+			if f.format == format {
+				return format, nil
+			}
+		}
+		return "", fmt.Errorf("invalid format %q, expected tar, tar.gz, tar.zst or zip", format)
+	}
+	for _, f := range archiveFormats {
+		if strings.HasSuffix(strings.ToLower(path), f.ext) {
+			return f.format, nil
+		}
+	}
+	return "", fmt.Errorf("unable to tell the format of %s, use --format", path)
+}
+
+// archiveWriter adds files to an archive.
+type archiveWriter interface {
+	add(name string, size int64, modTime time.Time, r io.Reader) error
+	Close() error
+}
+
+type tarArchiveWriter struct {
+	tw         *tar.Writer
+	compressor io.WriteCloser
+}
+
+func (w *tarArchiveWriter) add(name string, size int64, modTime time.Time, r io.Reader) error {
+	err := w.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     size,
+		Mode:     0o644,
+		ModTime:  modTime,
+		Format:   tar.FormatPAX,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w.tw, r)
+	return err
+}
+
+func (w *tarArchiveWriter) Close() error {
+	err := w.tw.Close()
+	if w.compressor != nil {
+		err = errors.Join(err, w.compressor.Close())
+	}
+	return err
+}
+
+type zipArchiveWriter struct {
+	zw *zip.Writer
+}
+
+func (w *zipArchiveWriter) add(name string, size int64, modTime time.Time, r io.Reader) error {
+	fw, err := w.zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modTime})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(fw, r)
+	return err
+}
+
+func (w *zipArchiveWriter) Close() error {
+	return w.zw.Close()
+}
+
+// newArchiveWriter writes an archive of the given format to out.
+func newArchiveWriter(out io.Writer, format string) (archiveWriter, error) {
+	switch format {
+	case "zip":
+		return &zipArchiveWriter{zip.NewWriter(out)}, nil
+	case "tar.gz":
+		gz := gzip.NewWriter(out)
+		return &tarArchiveWriter{tar.NewWriter(gz), gz}, nil
+	case "tar.zst":
+		zw, err := zstd.NewWriter(out)
+		if err != nil {
+			return nil, err
+		}
+		return &tarArchiveWriter{tar.NewWriter(zw), zw}, nil
+	}
+	return &tarArchiveWriter{tw: tar.NewWriter(out)}, nil
+}
+
+// archiveReader reads the files of an archive in order. next returns io.EOF
+// after the last file.
+type archiveReader interface {
+	next() (name string, size int64, r io.Reader, err error)
+	Close() error
+}
+
+type tarArchiveReader struct {
+	f   *os.File
+	tr  *tar.Reader
+	dec io.Closer
+}
+
+func (r *tarArchiveReader) next() (string, int64, io.Reader, error) {
+	for {
+		h, err := r.tr.Next()
+		if err != nil {
+			return "", 0, nil, err
+		}
+		if h.Typeflag == tar.TypeReg {
+			return h.Name, h.Size, r.tr, nil
+		}
+	}
+}
+
+func (r *tarArchiveReader) Close() error {
+	if r.dec != nil {
+		r.dec.Close()
+	}
+	return r.f.Close()
+}
+
+type zipArchiveReader struct {
+	zr      *zip.ReadCloser
+	i       int
+	current io.ReadCloser
+}
+
+func (r *zipArchiveReader) next() (string, int64, io.Reader, error) {
+	if r.current != nil {
+		r.current.Close()
+		r.current = nil
+	}
+	for ; r.i < len(r.zr.File); r.i++ {
+		f := r.zr.File[r.i]
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return "", 0, nil, err
+		}
+		r.i++
+		r.current = rc
+		return f.Name, int64(f.UncompressedSize64), rc, nil
+	}
+	return "", 0, nil, io.EOF
+}
+
+func (r *zipArchiveReader) Close() error {
+	if r.current != nil {
+		r.current.Close()
+	}
+	return r.zr.Close()
+}
+
+// openArchive opens an archive of the given format for reading.
+func openArchive(path, format string) (archiveReader, error) {
+	if format == "zip" {
+		zr, err := zip.OpenReader(path)
+		if err != nil {
+			return nil, err
+		}
+		return &zipArchiveReader{zr: zr}, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &tarArchiveReader{f: f}
+	switch format {
+	case "tar.gz":
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		r.tr, r.dec = tar.NewReader(gz), gz
+	case "tar.zst":
+		zr, err := zstd.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		r.tr, r.dec = tar.NewReader(zr), zr.IOReadCloser()
+	default:
+		r.tr = tar.NewReader(f)
+	}
+	return r, nil
+}
+
+// readArchiveManifest reads the manifest, which must be the first file of the
+// archive.
+func readArchiveManifest(r archiveReader) (*archiveManifest, error) {
+	name, _, data, err := r.next()
+	if err == io.EOF || err == nil && name != archiveManifestName {
+		return nil, fmt.Errorf("%s is not the first file, this is not an archive made by export", archiveManifestName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m archiveManifest
+	if err := json.NewDecoder(data).Decode(&m); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if m.Version > archiveVersion {
+		return nil, fmt.Errorf("the archive has version %d, this build reads up to version %d", m.Version, archiveVersion)
+	}
+	return &m, nil
+}
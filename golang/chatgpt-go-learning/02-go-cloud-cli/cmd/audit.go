@@ -0,0 +1,219 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"os/user"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/spf13/cobra"
+)
+
+var auditLog string
+
+// auditCommand is the command line being run, set once cobra has parsed it.
+var auditCommand string
+
+// mutatingOperations are the prefixes of API operations that change state.
+// Every call to one of them is appended to the audit log.
+var mutatingOperations = []string{"Abort", "Complete", "Copy", "Create", "Delete", "Put", "Restore", "Upload"}
+
+// isMutating reports whether an API operation changes state.
+func isMutating(operation string) bool {
+	for _, prefix := range mutatingOperations {
+		if strings.HasPrefix(operation, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sensitiveFlag matches flags whose values must never be written to the
+// audit log.
+var sensitiveFlag = regexp.MustCompile(`(?i)secret|token|password|credential`)
+
+// auditRecord is a line of the audit log.
+type auditRecord struct {
+	Time      time.Time
+	User      string
+	Host      string
+	Profile   string
+	Region    string
+	Command   string
+	Args      []string
+	Service   string
+	Operation string
+	Bucket    string `json:",omitempty"`
+	Key       string `json:",omitempty"`
+	Result    string
+	Error     string `json:",omitempty"`
+	RequestID string `json:",omitempty"`
+}
+
+var auditMu sync.Mutex
+var auditWarned bool
+
+// auditLogPath returns the audit log file.
+func auditLogPath() string {
+	if auditLog != "" {
+		return auditLog
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "go-cloud-cli", "audit.log")
+}
+
+// auditArgs returns the command line arguments with the values of sensitive
+// flags redacted. Credentials come from the environment or shared config and
+// never appear in the arguments, but a flag might still carry a secret.
+func auditArgs(args []string) []string {
+	redacted := make([]string, len(args))
+	redactNext := false
+	for i, a := range args {
+		switch {
+		case redactNext:
+			redacted[i] = "REDACTED"
+			redactNext = false
+		case strings.HasPrefix(a, "-") && sensitiveFlag.MatchString(a):
+			name, _, hasValue := strings.Cut(a, "=")
+			if hasValue {
+				redacted[i] = name + "=REDACTED"
+			} else {
+				redacted[i] = a
+				redactNext = true
+			}
+		default:
+			redacted[i] = a
+		}
+	}
+	return redacted
+}
+
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// inputString returns a string field of an API input, such as Bucket or Key.
+func inputString(params any, field string) string {
+	v := reflect.ValueOf(params)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	f := v.FieldByName(field)
+	if !f.IsValid() || f.Kind() != reflect.Pointer || f.IsNil() || f.Elem().Kind() != reflect.String {
+		return ""
+	}
+	return f.Elem().String()
+}
+
+// appendAudit writes a record to the audit log. A failure to write is reported
+// once and doesn't fail the command.
+func appendAudit(record auditRecord) {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	auditMu.Lock()
+	defer auditMu.Unlock()
+
+	path := auditLogPath()
+	err = os.MkdirAll(filepath.Dir(path), 0o700)
+	if err == nil {
+		var f *os.File
+		f, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+		if err == nil {
+			// A single append write keeps records of concurrent processes
+			// from interleaving.
+			_, err = f.Write(line)
+			f.Close()
+		}
+	}
+	if err != nil && !auditWarned {
+		log.Printf("Warning: unable to write audit log %s: %v", path, err)
+		auditWarned = true
+	}
+}
+
+// auditMiddleware records every mutating API call with its outcome and AWS
+// request ID. It runs before the retry loop, so it records the final outcome
+// rather than every attempt.
+func auditMiddleware(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("AuditLog",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			operation := awsmiddleware.GetOperationName(ctx)
+			out, metadata, err := next.HandleInitialize(ctx, in)
+			if !isMutating(operation) {
+				return out, metadata, err
+			}
+
+			record := auditRecord{
+				Time:      time.Now().UTC(),
+				User:      currentUser(),
+				Profile:   resolvedProfile(),
+				Region:    awsmiddleware.GetRegion(ctx),
+				Command:   auditCommand,
+				Args:      auditArgs(os.Args[1:]),
+				Service:   awsmiddleware.GetServiceID(ctx),
+				Operation: operation,
+				Bucket:    inputString(in.Parameters, "Bucket"),
+				Key:       inputString(in.Parameters, "Key"),
+				Result:    "success",
+			}
+			record.Host, _ = os.Hostname()
+			record.RequestID, _ = awsmiddleware.GetRequestIDMetadata(metadata)
+
+			if err != nil {
+				record.Result = "error"
+				record.Error = err.Error()
+				var apiErr smithy.APIError
+				if errors.As(err, &apiErr) {
+					record.Error = apiErr.ErrorCode() + ": " + apiErr.ErrorMessage()
+				}
+				var reqErr interface{ ServiceRequestID() string }
+				if record.RequestID == "" && errors.As(err, &reqErr) {
+					record.RequestID = reqErr.ServiceRequestID()
+				}
+			}
+
+			appendAudit(record)
+			return out, metadata, err
+		}), middleware.After)
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&auditLog, "audit-log", "", "Audit log of mutating API calls (default $XDG_CONFIG_HOME/go-cloud-cli/audit.log)")
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		auditCommand = cmd.CommandPath()
+		if err := checkRegionFlags(cmd); err != nil {
+			log.Fatal(err)
+		}
+		if explainPermissions {
+			explainCommand(cmd, args)
+			os.Exit(0)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// checkpoint records the keys a bulk run has finished, one JSON string per
+// line, so that a run that failed part way can be resumed.
+type checkpoint struct {
+	path string
+	mu   sync.Mutex
+	f    *os.File
+	done map[string]bool
+}
+
+// checkpointPath returns the default checkpoint of a bulk run. The name is
+// derived from everything that describes the work, so running the same
+// command again picks it up.
+func checkpointPath(parts ...string) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return filepath.Join(dir, "go-cloud-cli", "checkpoints", hex.EncodeToString(sum[:16])+".jsonl")
+}
+
+// openCheckpoint loads the keys done by previous runs and opens the
+// checkpoint for appending. restart discards what previous runs did.
+func openCheckpoint(path string, restart bool) (*checkpoint, error) {
+	c := &checkpoint{path: path, done: map[string]bool{}}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+
+	flags := os.O_WRONLY | os.O_APPEND | os.O_CREATE
+	if restart {
+		flags |= os.O_TRUNC
+	} else if f, err := os.Open(path); err == nil {
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			var key string
+			// A torn last line from an interrupted run is simply redone.
+			if json.Unmarshal(scanner.Bytes(), &key) == nil {
+				c.done[key] = true
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path, flags, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	c.f = f
+	return c, nil
+}
+
+// remaining returns the keys not done yet, in order.
+func (c *checkpoint) remaining(keys []string) []string {
+	var todo []string
+	for _, k := range keys {
+		if !c.done[k] {
+			todo = append(todo, k)
+		}
+	}
+	return todo
+}
+
+// markDone records a finished key.
+func (c *checkpoint) markDone(key string) error {
+	line, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.done[key] = true
+	_, err = c.f.Write(append(line, '\n'))
+	return err
+}
+
+// close closes the checkpoint and removes it once every key is done.
+func (c *checkpoint) close(complete bool) error {
+	err := c.f.Close()
+	if complete {
+		err = errors.Join(err, os.Remove(c.path))
+	}
+	return err
+}
+
+// runBulk runs fn for every key on a pool of concurrency workers and returns
+// how many failed. Keys that succeed are recorded in the checkpoint, if any.
+// Failures are logged and don't stop other keys, but no new key is started
+// once ctx is done.
+func runBulk(ctx context.Context, keys []string, concurrency int, cp *checkpoint, fn func(context.Context, string) error) int {
+	queue := make(chan string)
+	var mu sync.Mutex
+	failed := 0
+
+	var wg sync.WaitGroup
+	for range max(concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range queue {
+				err := fn(ctx, key)
+				if err == nil && cp != nil {
+					err = cp.markDone(key)
+				}
+				if err != nil {
+					log.Printf("Failed on %s: %v", key, err)
+					mu.Lock()
+					failed++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, key := range keys {
+		select {
+		case queue <- key:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	return failed
+}
@@ -0,0 +1,248 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go/middleware"
+)
+
+var (
+	profile         string
+	region          string
+	endpointURL     string
+	outputFormat    string
+	roleArn         string
+	externalID      string
+	mfaSerial       string
+	sessionDuration time.Duration
+)
+
+// loadConfig loads the default SDK config and applies the global flags to it.
+// When --role-arn is set the loaded credentials are only used to assume the
+// role, and every command talks to AWS as that role.
+func loadConfig(ctx context.Context, optFns ...func(*config.LoadOptions) error) (aws.Config, error) {
+	var opts []func(*config.LoadOptions) error
+	if profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(profile))
+	}
+	if region != "" {
+		opts = append(opts, config.WithRegion(region))
+	}
+	if endpointURL != "" {
+		opts = append(opts, config.WithBaseEndpoint(endpointURL))
+	}
+
+	bandwidth, requests, err := limiters()
+	if err != nil {
+		return aws.Config{}, err
+	}
+	apiOptions := []func(*middleware.Stack) error{auditMiddleware}
+	if readOnly {
+		apiOptions = append(apiOptions, readOnlyMiddleware)
+	}
+	if requests != nil {
+		apiOptions = append(apiOptions, requestRateMiddleware(requests))
+	}
+	opts = append(opts, config.WithAPIOptions(apiOptions))
+
+	cfg, err := config.LoadDefaultConfig(ctx, append(opts, optFns...)...)
+	if err != nil {
+		return cfg, err
+	}
+	if bandwidth != nil {
+		cfg.HTTPClient = &throttledHTTPClient{cfg.HTTPClient, bandwidth}
+	}
+
+	if roleArn != "" {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), roleArn, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = "go-cloud-cli-" + fmt.Sprint(time.Now().Unix())
+			o.Duration = sessionDuration
+			if externalID != "" {
+				o.ExternalID = aws.String(externalID)
+			}
+			if mfaSerial != "" {
+				o.SerialNumber = aws.String(mfaSerial)
+				o.TokenProvider = promptMFAToken
+			}
+		})
+		cfg.Credentials = aws.NewCredentialsCache(&fileCacheProvider{
+			path:     sessionCachePath(),
+			provider: provider,
+		})
+	}
+
+	return cfg, nil
+}
+
+// promptMFAToken reads an MFA code from the terminal. The prompt goes to
+// stderr so that it doesn't end up in JSON output.
+func promptMFAToken() (string, error) {
+	fmt.Fprintf(os.Stderr, "MFA token for %s: ", mfaSerial)
+	token, err := stdin.ReadString('\n')
+	if err != nil && token == "" {
+		return "", err
+	}
+	return strings.TrimSpace(token), nil
+}
+
+// sessionCachePath returns the file the assumed role session is cached in. The
+// name is derived from everything that changes which session we would get.
+func sessionCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	key := strings.Join([]string{resolvedProfile(), roleArn, externalID, mfaSerial}, "|")
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(dir, "go-cloud-cli", "sts", hex.EncodeToString(sum[:16])+".json")
+}
+
+// resolvedProfile returns the shared config profile the SDK will use.
+func resolvedProfile() string {
+	if profile != "" {
+		return profile
+	}
+	if p := os.Getenv("AWS_PROFILE"); p != "" {
+		return p
+	}
+	return "default"
+}
+
+// resolvedEndpoint returns the endpoint override, if any.
+func resolvedEndpoint() string {
+	if endpointURL != "" {
+		return endpointURL
+	}
+	return os.Getenv("AWS_ENDPOINT_URL")
+}
+
+// jsonOutput reports whether the user asked for JSON output, either with
+// --output json or with a command's own --json flag.
+func jsonOutput(legacy bool) bool {
+	return legacy || outputFormat == "json"
+}
+
+// fileCacheProvider keeps the credentials of another provider on disk until
+// they expire, so that consecutive invocations don't ask for a new MFA token.
+type fileCacheProvider struct {
+	path     string
+	provider aws.CredentialsProvider
+}
+
+// cachedCredentials is the on-disk form of a cached session.
+type cachedCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Expires         time.Time
+}
+
+func (p *fileCacheProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	if creds, err := p.read(); err == nil {
+		return creds, nil
+	}
+
+	creds, err := p.provider.Retrieve(ctx)
+	if err != nil {
+		return creds, err
+	}
+
+	if err := p.write(creds); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: unable to cache session credentials: %v\n", err)
+	}
+	return creds, nil
+}
+
+// read returns the cached credentials, or an error if there are none or they
+// are about to expire.
+func (p *fileCacheProvider) read() (aws.Credentials, error) {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return aws.Credentials{}, err
+	}
+
+	var cached cachedCredentials
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return aws.Credentials{}, err
+	}
+	if time.Until(cached.Expires) < time.Minute {
+		return aws.Credentials{}, errors.New("cached credentials expired")
+	}
+
+	return aws.Credentials{
+		AccessKeyID:     cached.AccessKeyID,
+		SecretAccessKey: cached.SecretAccessKey,
+		SessionToken:    cached.SessionToken,
+		Source:          stscreds.ProviderName,
+		CanExpire:       true,
+		Expires:         cached.Expires,
+	}, nil
+}
+
+func (p *fileCacheProvider) write(creds aws.Credentials) error {
+	if !creds.CanExpire {
+		return nil
+	}
+
+	data, err := json.Marshal(cachedCredentials{
+		AccessKeyID:     creds.AccessKeyID,
+		SecretAccessKey: creds.SecretAccessKey,
+		SessionToken:    creds.SessionToken,
+		Expires:         creds.Expires,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p.path), 0o700); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so that a concurrent reader never sees
+	// a half written session.
+	tmp, err := os.CreateTemp(filepath.Dir(p.path), ".session-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p.path)
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Shared config profile to use")
+	rootCmd.PersistentFlags().StringVar(&region, "region", "", "AWS region to use")
+	rootCmd.PersistentFlags().StringVar(&endpointURL, "endpoint-url", "", "Override the AWS service endpoint")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text or json")
+	rootCmd.PersistentFlags().StringVar(&roleArn, "role-arn", "", "ARN of an IAM role to assume before running the command")
+	rootCmd.PersistentFlags().StringVar(&externalID, "external-id", "", "External ID to pass when assuming --role-arn")
+	rootCmd.PersistentFlags().StringVar(&mfaSerial, "mfa-serial", "", "Serial number or ARN of the MFA device required by --role-arn")
+	rootCmd.PersistentFlags().DurationVar(&sessionDuration, "session-duration", time.Hour, "Duration of the assumed role session")
+}
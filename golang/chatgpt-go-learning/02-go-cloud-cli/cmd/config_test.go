@@ -0,0 +1,215 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// fakeSTS answers AssumeRole with a new session every time and records the
+// requests it got.
+type fakeSTS struct {
+	lifetime time.Duration
+
+	mu       sync.Mutex
+	requests []map[string]string
+}
+
+func (f *fakeSTS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.Form.Get("Action") != "AssumeRole" {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	f.mu.Lock()
+	n := len(f.requests) + 1
+	request := map[string]string{}
+	for k := range r.Form {
+		request[k] = r.Form.Get(k)
+	}
+	f.requests = append(f.requests, request)
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/xml")
+	fmt.Fprintf(w, `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>ASIASESSION%d</AccessKeyId>
+      <SecretAccessKey>secret%d</SecretAccessKey>
+      <SessionToken>token%d</SessionToken>
+      <Expiration>%s</Expiration>
+    </Credentials>
+    <AssumedRoleUser>
+      <Arn>%s/session</Arn>
+      <AssumedRoleId>AROA:session</AssumedRoleId>
+    </AssumedRoleUser>
+  </AssumeRoleResult>
+  <ResponseMetadata><RequestId>req-%d</RequestId></ResponseMetadata>
+</AssumeRoleResponse>`, n, n, n, time.Now().Add(f.lifetime).UTC().Format(time.RFC3339), request["RoleArn"], n)
+}
+
+func (f *fakeSTS) calls() []map[string]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests
+}
+
+// useFakeSTS points --endpoint-url at a fake STS, sets --role-arn and keeps
+// the session cache and audit log in a temporary directory.
+func useFakeSTS(t *testing.T) *fakeSTS {
+	t.Helper()
+	fake := &fakeSTS{lifetime: time.Hour}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "aws-config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "aws-credentials"))
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIABASE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "base-secret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	t.Setenv("AWS_REGION", "us-east-1")
+
+	saved := []string{endpointURL, roleArn, externalID, mfaSerial}
+	savedDuration, savedStdin := sessionDuration, stdin
+	t.Cleanup(func() {
+		endpointURL, roleArn, externalID, mfaSerial = saved[0], saved[1], saved[2], saved[3]
+		sessionDuration, stdin = savedDuration, savedStdin
+	})
+	endpointURL = srv.URL
+	roleArn = "arn:aws:iam::123456789012:role/deploy"
+	externalID, mfaSerial = "", ""
+	sessionDuration = time.Hour
+	return fake
+}
+
+// assumedCredentials loads the config the way every command does and
+// retrieves its credentials.
+func assumedCredentials(t *testing.T) aws.Credentials {
+	t.Helper()
+	ctx := context.Background()
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	creds, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return creds
+}
+
+func TestAssumeRoleCachesSession(t *testing.T) {
+	fake := useFakeSTS(t)
+	externalID = "ext-1"
+
+	first := assumedCredentials(t)
+	if first.AccessKeyID != "ASIASESSION1" {
+		t.Fatalf("AccessKeyID = %q, want the assumed role session", first.AccessKeyID)
+	}
+	calls := fake.calls()
+	if len(calls) != 1 {
+		t.Fatalf("%d AssumeRole calls, want 1", len(calls))
+	}
+	if calls[0]["RoleArn"] != roleArn || calls[0]["ExternalId"] != "ext-1" || calls[0]["DurationSeconds"] != "3600" {
+		t.Errorf("AssumeRole got %v", calls[0])
+	}
+
+	path := sessionCachePath()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("cache file mode = %v, want 0600", mode)
+	}
+	if info, err := os.Stat(filepath.Dir(path)); err != nil || info.Mode().Perm() != 0o700 {
+		t.Errorf("cache directory: %v, %v, want mode 0700", info.Mode().Perm(), err)
+	}
+
+	// A later invocation builds a new config and finds the session on disk.
+	second := assumedCredentials(t)
+	if second.AccessKeyID != first.AccessKeyID || second.SessionToken != first.SessionToken {
+		t.Errorf("second run got %q, want the cached %q", second.AccessKeyID, first.AccessKeyID)
+	}
+	if n := len(fake.calls()); n != 1 {
+		t.Errorf("%d AssumeRole calls after a cache hit, want 1", n)
+	}
+}
+
+func TestAssumeRoleRenewsExpiredSession(t *testing.T) {
+	fake := useFakeSTS(t)
+
+	path := sessionCachePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	expired, _ := json.Marshal(cachedCredentials{
+		AccessKeyID:     "ASIAEXPIRED",
+		SecretAccessKey: "old",
+		SessionToken:    "old",
+		Expires:         time.Now().Add(30 * time.Second),
+	})
+	if err := os.WriteFile(path, expired, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	creds := assumedCredentials(t)
+	if creds.AccessKeyID != "ASIASESSION1" {
+		t.Errorf("AccessKeyID = %q, want a new session", creds.AccessKeyID)
+	}
+	if n := len(fake.calls()); n != 1 {
+		t.Errorf("%d AssumeRole calls, want 1", n)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cached cachedCredentials
+	if err := json.Unmarshal(data, &cached); err != nil {
+		t.Fatal(err)
+	}
+	if cached.AccessKeyID != "ASIASESSION1" || time.Until(cached.Expires) < 50*time.Minute {
+		t.Errorf("cache holds %q until %v, want the new session", cached.AccessKeyID, cached.Expires)
+	}
+}
+
+func TestAssumeRolePromptsForMFA(t *testing.T) {
+	fake := useFakeSTS(t)
+	mfaSerial = "arn:aws:iam::123456789012:mfa/alice"
+	stdin = bufio.NewReader(strings.NewReader("123456\n"))
+
+	assumedCredentials(t)
+	calls := fake.calls()
+	if len(calls) != 1 {
+		t.Fatalf("%d AssumeRole calls, want 1", len(calls))
+	}
+	if calls[0]["SerialNumber"] != mfaSerial || calls[0]["TokenCode"] != "123456" {
+		t.Errorf("AssumeRole got SerialNumber %q and TokenCode %q", calls[0]["SerialNumber"], calls[0]["TokenCode"])
+	}
+
+	// The cached session is reused without asking for another token; with
+	// nothing left on stdin a prompt would fail.
+	assumedCredentials(t)
+	if n := len(fake.calls()); n != 1 {
+		t.Errorf("%d AssumeRole calls, want the cached session to be reused", n)
+	}
+}
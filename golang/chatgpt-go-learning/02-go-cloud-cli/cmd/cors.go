@@ -0,0 +1,182 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+// corsFile is the layout of the file given to cors put:
+//
+//	rules:
+//	  - id: web
+//	    allowedOrigins: ["https://example.com"]
+//	    allowedMethods: [GET, HEAD]
+//	    allowedHeaders: ["*"]
+//	    maxAgeSeconds: 3000
+type corsFile struct {
+	Rules []cloud.CORSRule `yaml:"rules"`
+}
+
+type corsCmdInput struct {
+	name    string
+	file    string
+	id      string
+	replace bool
+}
+
+// corsCmd represents the cors command
+var corsCmd = &cobra.Command{
+	Use:   "cors",
+	Short: "Manage the CORS configuration of a bucket",
+}
+
+// corsGetCmd represents the cors get command
+var corsGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Print the CORS rules of a bucket",
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		getCors(&corsCmdInput{name: name})
+	},
+}
+
+// corsPutCmd represents the cors put command
+var corsPutCmd = &cobra.Command{
+	Use:   "put",
+	Short: "Add CORS rules from a YAML file",
+	Long: `Validates the rules in a YAML file and adds them to the CORS configuration of
+a bucket. A rule replaces an existing rule with the same id, other existing
+rules are kept. Use --replace to drop the existing rules instead.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		file, _ := cmd.Flags().GetString("file")
+		replace, _ := cmd.Flags().GetBool("replace")
+		putCors(&corsCmdInput{name: name, file: file, replace: replace})
+	},
+}
+
+// corsDeleteCmd represents the cors delete command
+var corsDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a CORS rule, or the whole CORS configuration",
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		id, _ := cmd.Flags().GetString("id")
+		deleteCors(&corsCmdInput{name: name, id: id})
+	},
+}
+
+// readCorsFile reads the rules of a rule file. They are validated when put.
+func readCorsFile(path string) ([]cloud.CORSRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file corsFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if len(file.Rules) == 0 {
+		return nil, fmt.Errorf("%s has no rules", path)
+	}
+	return file.Rules, nil
+}
+
+func getCors(input *corsCmdInput) {
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+
+	rules, err := cloud.New(cfg).CORSRules(ctx, input.name)
+	if err != nil {
+		log.Fatalf("Failed to get CORS configuration: %v", err)
+	}
+
+	if jsonOutput(false) {
+		jsonData, _ := json.MarshalIndent(rules, "", "  ")
+		fmt.Println(string(jsonData))
+		return
+	}
+	if len(rules) == 0 {
+		fmt.Printf("%s has no CORS rules\n", input.name)
+		return
+	}
+	data, _ := yaml.Marshal(corsFile{Rules: rules})
+	fmt.Print(string(data))
+}
+
+func putCors(input *corsCmdInput) {
+	rules, err := readCorsFile(input.file)
+	if err != nil {
+		log.Fatalf("Invalid CORS rules: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+
+	rules, err = cloud.New(cfg).PutCORSRules(ctx, input.name, rules, &cloud.PutCORSOptions{Replace: input.replace})
+	if err != nil {
+		log.Fatalf("Failed to put CORS configuration: %v", err)
+	}
+
+	fmt.Printf("%s now has %d CORS rules\n", input.name, len(rules))
+}
+
+func deleteCors(input *corsCmdInput) {
+	guardDestructive(input.name)
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+
+	rules, err := cloud.New(cfg).DeleteCORS(ctx, input.name, &cloud.DeleteCORSOptions{ID: input.id})
+	if err != nil {
+		log.Fatalf("Failed to delete CORS configuration: %v", err)
+	}
+
+	fmt.Printf("%s now has %d CORS rules\n", input.name, len(rules))
+}
+
+func init() {
+	corsCmd.PersistentFlags().StringP("name", "n", "", "Bucket Name")
+	corsCmd.MarkPersistentFlagRequired("name")
+
+	corsPutCmd.Flags().StringP("file", "f", "", "YAML file with the CORS rules")
+	corsPutCmd.MarkFlagRequired("file")
+	corsPutCmd.Flags().Bool("replace", false, "Replace the existing rules instead of merging with them")
+
+	corsDeleteCmd.Flags().String("id", "", "Delete only the rule with this id")
+
+	corsCmd.AddCommand(corsGetCmd, corsPutCmd, corsDeleteCmd)
+	rootCmd.AddCommand(corsCmd)
+}
@@ -0,0 +1,299 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+//go:embed pricing.json
+var defaultPricing []byte
+
+const bytesPerGB = 1024 * 1024 * 1024
+
+// pricingTable holds monthly storage prices per GB. Regions only need to list
+// the classes whose price differs from the default.
+type pricingTable struct {
+	Currency       string
+	StorageClasses map[string]float64
+	Regions        map[string]map[string]float64
+}
+
+func (p *pricingTable) price(region, class string) (float64, bool) {
+	if price, ok := p.Regions[region][class]; ok {
+		return price, true
+	}
+	price, ok := p.StorageClasses[class]
+	return price, ok
+}
+
+// lifecycleTransitions are the lifecycle rules we consider recommending. The
+// ages must be present in lifecycleAges.
+var lifecycleTransitions = []struct {
+	Days         int
+	StorageClass string
+}{
+	{30, "STANDARD_IA"},
+	{90, "GLACIER_IR"},
+	{180, "DEEP_ARCHIVE"},
+}
+
+// archiveClasses are never transitioned out of, either because they are
+// already the cheapest or because S3 manages their tiering.
+var archiveClasses = map[string]bool{
+	"GLACIER":             true,
+	"DEEP_ARCHIVE":        true,
+	"INTELLIGENT_TIERING": true,
+	"EXPRESS_ONEZONE":     true,
+}
+
+type costCmdInput struct {
+	buckets     []string
+	pricingFile string
+	minSavings  float64
+	jsonOutput  bool
+	timeout     int
+}
+
+type classCost struct {
+	StorageClass string
+	Objects      int64
+	Bytes        int64
+	MonthlyCost  float64
+}
+
+type bucketCost struct {
+	Bucket      string
+	Region      string
+	Classes     []classCost
+	MonthlyCost float64
+}
+
+type lifecycleRecommendation struct {
+	Bucket         string
+	StorageClass   string
+	Days           int
+	TransitionTo   string
+	Bytes          int64
+	MonthlySavings float64
+}
+
+type costEstimate struct {
+	Currency        string
+	Buckets         []bucketCost
+	Recommendations []lifecycleRecommendation
+	MonthlyCost     float64
+}
+
+// costCmd represents the cost command
+var costCmd = &cobra.Command{
+	Use:   "cost",
+	Short: "Estimate what bucket storage costs",
+}
+
+// costEstimateCmd represents the cost estimate command
+var costEstimateCmd = &cobra.Command{
+	Use:   "estimate [bucket...]",
+	Short: "Estimate the monthly storage cost of buckets",
+	Long: `Lists the objects of each bucket, or of every bucket when none are given,
+and prices their storage per storage class. With --regions or --all-regions,
+only the buckets of those regions are estimated. Prices come from an embedded table
+that can be replaced with --pricing. Lifecycle transitions that would save at
+least --min-savings a month are recommended.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		pricingFile, _ := cmd.Flags().GetString("pricing")
+		minSavings, _ := cmd.Flags().GetFloat64("min-savings")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		timeout, _ := cmd.Flags().GetInt("timeout")
+		estimateCost(&costCmdInput{
+			buckets:     args,
+			pricingFile: pricingFile,
+			minSavings:  minSavings,
+			jsonOutput:  jsonOutput,
+			timeout:     timeout,
+		})
+	},
+}
+
+func loadPricing(path string) (*pricingTable, error) {
+	data := defaultPricing
+	if path != "" {
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var pricing pricingTable
+	if err := json.Unmarshal(data, &pricing); err != nil {
+		return nil, fmt.Errorf("parsing pricing table: %w", err)
+	}
+	if len(pricing.StorageClasses) == 0 {
+		return nil, errors.New("pricing table has no storage classes")
+	}
+	return &pricing, nil
+}
+
+func estimateCost(input *costCmdInput) {
+	pricing, err := loadPricing(input.pricingFile)
+	if err != nil {
+		log.Fatalf("Unable to load pricing: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), time.Duration(input.timeout)*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+	cc := cloud.New(cfg)
+
+	regions, err := resolveRegions(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Unable to resolve regions: %v", err)
+	}
+	if regions != nil && len(input.buckets) > 0 {
+		log.Fatalf("--regions and --all-regions only apply when no buckets are given")
+	}
+
+	// Buckets of regions that fail to list are left out of the estimate and
+	// reported at the end.
+	buckets := input.buckets
+	var regionsErr *cloud.RegionsError
+	if len(buckets) == 0 {
+		rows, err := cc.ListBuckets(ctx, &cloud.ListBucketsOptions{
+			Regions:     regions,
+			Concurrency: regionConcurrency,
+		})
+		if err != nil && !errors.As(err, &regionsErr) {
+			log.Fatalf("Unable to list buckets: %v", err)
+		}
+		for _, row := range rows {
+			buckets = append(buckets, *row.Item.Name)
+		}
+	}
+
+	estimate := costEstimate{Currency: pricing.Currency}
+	for _, bucket := range buckets {
+		stats, err := collectBucketStats(ctx, cc, bucket)
+		if err != nil {
+			log.Fatalf("Unable to collect statistics for %s: %v", bucket, err)
+		}
+
+		cost := priceBucket(pricing, stats)
+		estimate.Buckets = append(estimate.Buckets, cost)
+		estimate.MonthlyCost += cost.MonthlyCost
+		estimate.Recommendations = append(estimate.Recommendations, recommendTransitions(pricing, stats, input.minSavings)...)
+	}
+
+	if jsonOutput(input.jsonOutput) {
+		jsonData, _ := json.MarshalIndent(estimate, "", "  ")
+		fmt.Println(string(jsonData))
+	} else {
+		printCostEstimate(&estimate)
+	}
+
+	if regionsErr != nil {
+		for _, e := range regionsErr.Failed {
+			log.Printf("Unable to list buckets in %v", e)
+		}
+		log.Fatalf("%d of %d regions failed, their buckets are not in the estimate", len(regionsErr.Failed), regionsErr.Total)
+	}
+}
+
+func priceBucket(pricing *pricingTable, stats *cloud.BucketStats) bucketCost {
+	cost := bucketCost{Bucket: stats.Bucket, Region: stats.Region}
+	for class, s := range stats.Classes {
+		price, ok := pricing.price(stats.Region, class)
+		if !ok {
+			log.Printf("Warning: no price for storage class %s, counting it as free", class)
+		}
+		monthly := float64(s.Bytes) / bytesPerGB * price
+		cost.Classes = append(cost.Classes, classCost{class, s.Objects, s.Bytes, monthly})
+		cost.MonthlyCost += monthly
+	}
+	sort.Slice(cost.Classes, func(i, j int) bool {
+		return cost.Classes[i].StorageClass < cost.Classes[j].StorageClass
+	})
+	return cost
+}
+
+// recommendTransitions suggests, for each storage class, the single lifecycle
+// transition that saves the most.
+func recommendTransitions(pricing *pricingTable, stats *cloud.BucketStats, minSavings float64) []lifecycleRecommendation {
+	var recommendations []lifecycleRecommendation
+	for class, s := range stats.Classes {
+		from, ok := pricing.price(stats.Region, class)
+		if !ok || archiveClasses[class] {
+			continue
+		}
+
+		var best *lifecycleRecommendation
+		for _, t := range lifecycleTransitions {
+			to, ok := pricing.price(stats.Region, t.StorageClass)
+			if !ok || to >= from || t.StorageClass == class {
+				continue
+			}
+			bytes := s.AgedBytes[t.Days]
+			savings := float64(bytes) / bytesPerGB * (from - to)
+			if savings < minSavings || (best != nil && savings <= best.MonthlySavings) {
+				continue
+			}
+			best = &lifecycleRecommendation{stats.Bucket, class, t.Days, t.StorageClass, bytes, savings}
+		}
+		if best != nil {
+			recommendations = append(recommendations, *best)
+		}
+	}
+	sort.Slice(recommendations, func(i, j int) bool {
+		return recommendations[i].MonthlySavings > recommendations[j].MonthlySavings
+	})
+	return recommendations
+}
+
+func printCostEstimate(estimate *costEstimate) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BUCKET\tREGION\tSTORAGE CLASS\tOBJECTS\tSIZE (GB)\tMONTHLY COST")
+	for _, b := range estimate.Buckets {
+		for _, c := range b.Classes {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%.2f\t%.2f %s\n", b.Bucket, b.Region, c.StorageClass, c.Objects, float64(c.Bytes)/bytesPerGB, c.MonthlyCost, estimate.Currency)
+		}
+	}
+	w.Flush()
+	fmt.Printf("\nTotal: %.2f %s per month\n", estimate.MonthlyCost, estimate.Currency)
+
+	if len(estimate.Recommendations) == 0 {
+		return
+	}
+
+	fmt.Println("\nRecommended lifecycle transitions:")
+	for _, r := range estimate.Recommendations {
+		fmt.Printf("  %s: move %s objects older than %d days to %s (%.2f GB), saving %.2f %s per month\n",
+			r.Bucket, r.StorageClass, r.Days, r.TransitionTo, float64(r.Bytes)/bytesPerGB, r.MonthlySavings, estimate.Currency)
+	}
+}
+
+func init() {
+	costEstimateCmd.Flags().String("pricing", "", "JSON file with a pricing table to use instead of the built-in one")
+	costEstimateCmd.Flags().Float64("min-savings", 1, "Only recommend transitions saving at least this much per month")
+	costEstimateCmd.Flags().Bool("json", false, "Output in JSON format")
+	costEstimateCmd.Flags().IntP("timeout", "t", 300, "Timeout in seconds")
+	costCmd.AddCommand(costEstimateCmd)
+	rootCmd.AddCommand(costCmd)
+}
@@ -10,9 +10,9 @@ import (
 	"log"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
 )
 
 // createCmd represents the create command
@@ -40,16 +40,12 @@ func createBucket(name string) {
 	ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Second, timeoutErr)
 	defer cancel()
 
-	cfg, err := config.LoadDefaultConfig(ctx)
+	cfg, err := loadConfig(ctx)
 	if err != nil {
 		log.Fatalf("Unable to load SDK config: %v", err)
 	}
 
-	s3client := s3.NewFromConfig(cfg)
-
-	_, err = s3client.CreateBucket(ctx, &s3.CreateBucketInput{
-		Bucket: &name,
-	})
+	err = cloud.New(cfg).CreateBucket(ctx, name, nil)
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			log.Fatalf("Request failed: %v", err)
@@ -9,9 +9,9 @@ import (
 	"log"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
 )
 
 type deleteCmdInput struct {
@@ -19,20 +19,18 @@ type deleteCmdInput struct {
 }
 
 func deleteBucket(input *deleteCmdInput) {
+	guardDestructive(input.name)
+
 	timeoutErr := errors.New("Timeout")
 	ctx, cancel := context.WithTimeoutCause(context.Background(), 5*time.Second, timeoutErr)
 	defer cancel()
 
-	cfg, err := config.LoadDefaultConfig(ctx)
+	cfg, err := loadConfig(ctx)
 	if err != nil {
 		log.Fatalf("Error loading default config: %v", err)
 	}
 
-	s3client := s3.NewFromConfig(cfg)
-
-	_, err = s3client.DeleteBucket(ctx, &s3.DeleteBucketInput{
-		Bucket: &input.name,
-	})
+	err = cloud.New(cfg).DeleteBucket(ctx, input.name, nil)
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			log.Fatalf("Request failed: %v", err)
@@ -0,0 +1,384 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+type diffCmdInput struct {
+	left        string
+	right       string
+	checksum    bool
+	summary     bool
+	concurrency int
+	timeout     int
+}
+
+// diffSide is one side of a diff: a prefix in a bucket or a local directory.
+type diffSide struct {
+	uri    string
+	bucket string
+	prefix string
+	dir    string
+	cc     *cloud.Client
+}
+
+// diffEntry is an object or a file, keyed by its path relative to its side.
+type diffEntry struct {
+	Key  string
+	Size int64
+	ETag string
+}
+
+// diffChange is a key present on both sides with different contents.
+type diffChange struct {
+	Key    string
+	Reason string
+	Left   string
+	Right  string
+}
+
+// diffReport is the outcome of a diff.
+type diffReport struct {
+	Left      string
+	Right     string
+	OnlyLeft  []string
+	OnlyRight []string
+	Changed   []diffChange
+	Identical int
+}
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff <s3://bucket/prefix or directory> <s3://bucket/prefix or directory>",
+	Short: "Compare two prefixes, or a prefix and a local directory",
+	Long: `Lists both sides concurrently and reports the keys only on the left, only on
+the right, and on both sides with a different size or ETag. Local files are
+compared by MD5 with ETags. The output looks like a patch:
+
+  - key only on the left
+  + key only on the right
+  ~ key that differs
+
+The ETag of an object uploaded in parts or encrypted with KMS isn't its MD5, so
+identical objects can have different ETags. --checksum compares the full object
+checksums stored with such objects instead.
+
+Exits with status 1 if the sides differ.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		checksum, _ := cmd.Flags().GetBool("checksum")
+		summary, _ := cmd.Flags().GetBool("summary")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		timeout, _ := cmd.Flags().GetInt("timeout")
+		diff(&diffCmdInput{args[0], args[1], checksum, summary, concurrency, timeout})
+	},
+}
+
+// newDiffSide parses a side. Prefixes are compared as directories, so
+// s3://bucket/logs is the same as s3://bucket/logs/.
+func newDiffSide(ctx context.Context, cc *cloud.Client, uri string) (*diffSide, error) {
+	if !strings.HasPrefix(uri, "s3://") {
+		info, err := os.Stat(uri)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%s is not a directory", uri)
+		}
+		return &diffSide{uri: uri, dir: uri}, nil
+	}
+
+	bucket, prefix, err := cloud.ParseS3URI(uri)
+	if err != nil {
+		return nil, err
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	if _, err := cc.BucketClient(ctx, bucket); err != nil {
+		return nil, fmt.Errorf("unable to locate bucket %s: %w", bucket, err)
+	}
+	return &diffSide{uri: uri, bucket: bucket, prefix: prefix, cc: cc}, nil
+}
+
+func (s *diffSide) local() bool {
+	return s.bucket == ""
+}
+
+// list returns the entries of a side sorted by key. Local files have no ETag
+// until one is needed.
+func (s *diffSide) list(ctx context.Context, cc *cloud.Client, concurrency int) ([]diffEntry, error) {
+	var entries []diffEntry
+
+	if s.local() {
+		err := filepath.WalkDir(s.dir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(s.dir, p)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, diffEntry{Key: filepath.ToSlash(rel), Size: info.Size()})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		objects, err := cc.ListObjects(ctx, s.bucket, s.prefix, &cloud.ListObjectsOptions{Concurrency: concurrency})
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range objects {
+			key := aws.ToString(o.Key)
+			if strings.HasSuffix(key, "/") {
+				continue
+			}
+			entries = append(entries, diffEntry{
+				Key:  strings.TrimPrefix(key, s.prefix),
+				Size: aws.ToInt64(o.Size),
+				ETag: strings.Trim(aws.ToString(o.ETag), `"`),
+			})
+		}
+	}
+
+	slices.SortFunc(entries, func(a, b diffEntry) int { return strings.Compare(a.Key, b.Key) })
+	return entries, nil
+}
+
+// etag returns the ETag of an entry, computing the MD5 of local files.
+func (s *diffSide) etag(e diffEntry) (string, error) {
+	if !s.local() {
+		return e.ETag, nil
+	}
+	return cloud.FileMD5(filepath.Join(s.dir, filepath.FromSlash(e.Key)))
+}
+
+// checksum returns the full object checksum of an entry. Local files are
+// hashed with algorithm, which must be given for them.
+func (s *diffSide) checksum(ctx context.Context, key string, algorithm types.ChecksumAlgorithm) (types.ChecksumAlgorithm, string, bool, error) {
+	if s.local() {
+		value, err := cloud.FileChecksum(filepath.Join(s.dir, filepath.FromSlash(key)), algorithm)
+		return algorithm, value, err == nil, err
+	}
+
+	return s.cc.ObjectChecksum(ctx, s.bucket, s.prefix+key)
+}
+
+// compareChecksums compares the stored checksums of a key. It reports ok
+// false if they can't be compared.
+func compareChecksums(ctx context.Context, left, right *diffSide, key string) (same, ok bool, change diffChange, err error) {
+	// Ask the S3 side first: a local file can be hashed with any algorithm.
+	first, second := left, right
+	if left.local() {
+		first, second = right, left
+	}
+
+	algorithm, a, ok, err := first.checksum(ctx, key, types.ChecksumAlgorithmSha256)
+	if err != nil || !ok {
+		return false, false, diffChange{}, err
+	}
+	other, b, ok, err := second.checksum(ctx, key, algorithm)
+	if err != nil || !ok || other != algorithm {
+		return false, false, diffChange{}, err
+	}
+
+	if first != left {
+		a, b = b, a
+	}
+	return a == b, true, diffChange{key, "checksum", string(algorithm) + ":" + a, string(algorithm) + ":" + b}, nil
+}
+
+// compareEntries decides whether a key present on both sides differs.
+func compareEntries(ctx context.Context, left, right *diffSide, l, r diffEntry, checksum bool) (*diffChange, error) {
+	if l.Size != r.Size {
+		return &diffChange{l.Key, "size", fmt.Sprint(l.Size), fmt.Sprint(r.Size)}, nil
+	}
+
+	a, err := left.etag(l)
+	if err != nil {
+		return nil, err
+	}
+	b, err := right.etag(r)
+	if err != nil {
+		return nil, err
+	}
+	if a == b {
+		return nil, nil
+	}
+
+	if checksum {
+		same, ok, change, err := compareChecksums(ctx, left, right, l.Key)
+		if err != nil {
+			return nil, err
+		}
+		if ok && same {
+			return nil, nil
+		}
+		if ok {
+			return &change, nil
+		}
+	}
+	return &diffChange{l.Key, "etag", a, b}, nil
+}
+
+func diff(input *diffCmdInput) {
+	ctx, cancel := context.WithTimeoutCause(context.Background(), time.Duration(input.timeout)*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+	cc := cloud.New(cfg)
+
+	left, err := newDiffSide(ctx, cc, input.left)
+	if err != nil {
+		log.Fatalf("Invalid left side: %v", err)
+	}
+	right, err := newDiffSide(ctx, cc, input.right)
+	if err != nil {
+		log.Fatalf("Invalid right side: %v", err)
+	}
+
+	var leftEntries, rightEntries []diffEntry
+	var leftErr, rightErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		leftEntries, leftErr = left.list(ctx, cc, input.concurrency)
+	}()
+	go func() {
+		defer wg.Done()
+		rightEntries, rightErr = right.list(ctx, cc, input.concurrency)
+	}()
+	wg.Wait()
+	if leftErr != nil {
+		log.Fatalf("Unable to list %s: %v", left.uri, leftErr)
+	}
+	if rightErr != nil {
+		log.Fatalf("Unable to list %s: %v", right.uri, rightErr)
+	}
+
+	report := diffReport{Left: left.uri, Right: right.uri}
+	var pairs [][2]diffEntry
+	i, j := 0, 0
+	for i < len(leftEntries) || j < len(rightEntries) {
+		switch {
+		case j == len(rightEntries) || (i < len(leftEntries) && leftEntries[i].Key < rightEntries[j].Key):
+			report.OnlyLeft = append(report.OnlyLeft, leftEntries[i].Key)
+			i++
+		case i == len(leftEntries) || rightEntries[j].Key < leftEntries[i].Key:
+			report.OnlyRight = append(report.OnlyRight, rightEntries[j].Key)
+			j++
+		default:
+			pairs = append(pairs, [2]diffEntry{leftEntries[i], rightEntries[j]})
+			i++
+			j++
+		}
+	}
+
+	queue := make(chan [2]diffEntry)
+	var mu sync.Mutex
+	failed := 0
+	for range max(input.concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for pair := range queue {
+				change, err := compareEntries(ctx, left, right, pair[0], pair[1], input.checksum)
+
+				mu.Lock()
+				switch {
+				case err != nil:
+					log.Printf("Unable to compare %s: %v", pair[0].Key, err)
+					failed++
+				case change != nil:
+					report.Changed = append(report.Changed, *change)
+				default:
+					report.Identical++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, pair := range pairs {
+		queue <- pair
+	}
+	close(queue)
+	wg.Wait()
+	slices.SortFunc(report.Changed, func(a, b diffChange) int { return strings.Compare(a.Key, b.Key) })
+
+	if jsonOutput(false) {
+		jsonData, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(jsonData))
+	} else {
+		printDiff(&report, input.summary)
+	}
+
+	if failed > 0 {
+		log.Fatalf("%d of %d keys could not be compared", failed, len(pairs))
+	}
+	if len(report.OnlyLeft) > 0 || len(report.OnlyRight) > 0 || len(report.Changed) > 0 {
+		os.Exit(1)
+	}
+}
+
+func printDiff(report *diffReport, summaryOnly bool) {
+	if !summaryOnly {
+		fmt.Printf("--- %s\n+++ %s\n", report.Left, report.Right)
+		// Interleave the three lists in key order, like a patch.
+		var lines []string
+		for _, k := range report.OnlyLeft {
+			lines = append(lines, k+"\x00- "+k)
+		}
+		for _, k := range report.OnlyRight {
+			lines = append(lines, k+"\x00+ "+k)
+		}
+		for _, c := range report.Changed {
+			lines = append(lines, fmt.Sprintf("%s\x00~ %s (%s %s -> %s)", c.Key, c.Key, c.Reason, c.Left, c.Right))
+		}
+		slices.Sort(lines)
+		for _, l := range lines {
+			_, line, _ := strings.Cut(l, "\x00")
+			fmt.Println(line)
+		}
+	}
+
+	fmt.Printf("%d only in %s, %d only in %s, %d differ, %d identical\n",
+		len(report.OnlyLeft), report.Left, len(report.OnlyRight), report.Right, len(report.Changed), report.Identical)
+}
+
+func init() {
+	diffCmd.Flags().Bool("checksum", false, "Compare stored checksums when ETags differ")
+	diffCmd.Flags().Bool("summary", false, "Only print the summary")
+	diffCmd.Flags().Int("concurrency", 8, "Number of prefixes listed and keys compared at once")
+	diffCmd.Flags().IntP("timeout", "t", 600, "Timeout in seconds")
+	rootCmd.AddCommand(diffCmd)
+}
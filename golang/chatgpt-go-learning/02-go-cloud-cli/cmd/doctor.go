@@ -0,0 +1,417 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+type doctorCmdInput struct {
+	bucket  string
+	write   bool
+	timeout int
+}
+
+// Outcomes of a doctor check.
+const (
+	doctorPass = "pass"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+	doctorSkip = "skip"
+)
+
+// doctorCheck is a line of the doctor checklist.
+type doctorCheck struct {
+	Name   string
+	Status string
+	Detail string
+	Hint   string `json:",omitempty"`
+}
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the configuration and the connection to AWS",
+	Long: `Checks, in order, that credentials can be found, that AWS accepts them, that a
+region is set, that the S3 endpoint can be reached, that the local clock agrees
+with the server's, how proxies are configured and that basic S3 calls are
+allowed. Every check that doesn't pass comes with a hint on how to fix it.
+
+--bucket also checks access to a bucket, and --write uploads and deletes a
+small object in it. The command exits with status 1 if a check failed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		bucket, _ := cmd.Flags().GetString("bucket")
+		write, _ := cmd.Flags().GetBool("write")
+		timeout, _ := cmd.Flags().GetInt("timeout")
+		runDoctor(&doctorCmdInput{bucket, write, timeout})
+	},
+}
+
+// doctor runs the checks. Later checks use what earlier ones found and are
+// skipped when what they need is missing.
+type doctor struct {
+	input      *doctorCmdInput
+	timeout    time.Duration
+	cfg        aws.Config
+	loaded     bool
+	haveCreds  bool
+	endpoint   *url.URL
+	serverDate time.Time
+}
+
+// regionPattern matches the names of AWS regions, such as eu-west-1 or
+// us-gov-east-1.
+var regionPattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-\d+$`)
+
+// redactURL hides the password of a URL.
+func redactURL(u *url.URL) string {
+	if _, ok := u.User.Password(); ok {
+		return strings.Replace(u.String(), u.User.String()+"@", u.User.Username()+":REDACTED@", 1)
+	}
+	return u.String()
+}
+
+// apiErrorCode returns the error code of an API error, or "" for other errors.
+func apiErrorCode(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	return ""
+}
+
+func (d *doctor) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeoutCause(context.Background(), d.timeout, errors.New("Timeout"))
+}
+
+func (d *doctor) credentials() doctorCheck {
+	check := doctorCheck{Name: "Credentials"}
+	ctx, cancel := d.context()
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		check.Status, check.Detail = doctorFail, fmt.Sprintf("unable to load the configuration: %v", err)
+		check.Hint = "Check that the profile exists in ~/.aws/config and ~/.aws/credentials, or unset AWS_PROFILE"
+		return check
+	}
+	d.cfg, d.loaded = cfg, true
+
+	creds, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		check.Status, check.Detail = doctorFail, fmt.Sprintf("no credentials found: %v", err)
+		check.Hint = "Set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY, run 'aws configure', or 'aws sso login' for SSO profiles"
+		return check
+	}
+	d.haveCreds = true
+
+	key := creds.AccessKeyID
+	if len(key) > 8 {
+		key = key[:4] + "..." + key[len(key)-4:]
+	}
+	check.Status = doctorPass
+	check.Detail = fmt.Sprintf("access key %s from %s (profile %s)", key, creds.Source, resolvedProfile())
+	if creds.CanExpire {
+		left := time.Until(creds.Expires).Round(time.Second)
+		check.Detail += fmt.Sprintf(", expires in %s", left)
+		if left < 15*time.Minute {
+			check.Status = doctorWarn
+			check.Hint = "The session is about to expire; refresh it, for example with 'aws sso login'"
+		}
+	}
+	return check
+}
+
+func (d *doctor) callerIdentity() doctorCheck {
+	check := doctorCheck{Name: "Caller identity"}
+	if !d.haveCreds {
+		check.Status, check.Detail = doctorSkip, "no credentials"
+		return check
+	}
+	ctx, cancel := d.context()
+	defer cancel()
+
+	identity, err := sts.NewFromConfig(d.cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	switch {
+	case err != nil && resolvedEndpoint() != "":
+		check.Status, check.Detail = doctorWarn, fmt.Sprintf("STS is not available at %s: %v", resolvedEndpoint(), err)
+		check.Hint = "S3 compatible services often don't implement STS; this is only a problem if other checks fail"
+	case err != nil && apiErrorCode(err) == "":
+		check.Status, check.Detail = doctorFail, fmt.Sprintf("unable to reach STS: %v", err)
+		check.Hint = "Check DNS, firewalls and VPN; behind a corporate proxy set HTTPS_PROXY"
+	case err != nil:
+		check.Status, check.Detail = doctorFail, fmt.Sprintf("the credentials were rejected: %v", err)
+		check.Hint = "The keys may be revoked, mistyped or expired; for temporary credentials make sure AWS_SESSION_TOKEN is set too"
+	default:
+		check.Status = doctorPass
+		check.Detail = fmt.Sprintf("%s in account %s", aws.ToString(identity.Arn), aws.ToString(identity.Account))
+	}
+	return check
+}
+
+func (d *doctor) region() doctorCheck {
+	check := doctorCheck{Name: "Region"}
+	if !d.loaded {
+		check.Status, check.Detail = doctorSkip, "no configuration"
+		return check
+	}
+
+	source := "profile " + resolvedProfile()
+	switch {
+	case region != "":
+		source = "--region"
+	case os.Getenv("AWS_REGION") != "":
+		source = "AWS_REGION"
+	case os.Getenv("AWS_DEFAULT_REGION") != "":
+		source = "AWS_DEFAULT_REGION"
+	}
+
+	switch {
+	case d.cfg.Region == "":
+		check.Status, check.Detail = doctorFail, "no region is set"
+		check.Hint = "Pass --region, set AWS_REGION, or add 'region = ...' to the profile in ~/.aws/config"
+	case !regionPattern.MatchString(d.cfg.Region) && resolvedEndpoint() == "":
+		check.Status, check.Detail = doctorWarn, fmt.Sprintf("%q from %s doesn't look like an AWS region", d.cfg.Region, source)
+		check.Hint = "Regions look like us-east-1 or eu-west-2; check for typos"
+	default:
+		check.Status, check.Detail = doctorPass, fmt.Sprintf("%s from %s", d.cfg.Region, source)
+	}
+	return check
+}
+
+func (d *doctor) reachability() doctorCheck {
+	check := doctorCheck{Name: "Endpoint"}
+	endpoint := resolvedEndpoint()
+	if endpoint == "" {
+		r := d.cfg.Region
+		if r == "" {
+			r = "us-east-1"
+		}
+		endpoint = "https://s3." + r + ".amazonaws.com"
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		check.Status, check.Detail = doctorFail, fmt.Sprintf("invalid endpoint %q", endpoint)
+		check.Hint = "--endpoint-url and AWS_ENDPOINT_URL must be full URLs such as https://minio.example.com:9000"
+		return check
+	}
+	d.endpoint = u
+
+	ctx, cancel := d.context()
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u.String(), nil)
+	if err != nil {
+		check.Status, check.Detail = doctorFail, err.Error()
+		return check
+	}
+
+	// An unsigned request is enough: any HTTP response, even an error, shows
+	// the endpoint can be reached.
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		check.Status, check.Detail = doctorFail, fmt.Sprintf("unable to reach %s: %v", u.Host, err)
+		check.Hint = "Check DNS, firewalls and VPN; behind a corporate proxy set HTTPS_PROXY"
+		return check
+	}
+	resp.Body.Close()
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		d.serverDate = date.Add(elapsed / 2)
+	}
+	check.Status, check.Detail = doctorPass, fmt.Sprintf("%s answered in %s", u.Host, elapsed)
+	if elapsed > 2*time.Second {
+		check.Status = doctorWarn
+		check.Hint = "The endpoint is slow to answer; a closer region or a direct connection may help"
+	}
+	return check
+}
+
+func (d *doctor) clockSkew() doctorCheck {
+	check := doctorCheck{Name: "Clock"}
+	if d.serverDate.IsZero() {
+		check.Status, check.Detail = doctorSkip, "the server's time is unknown"
+		return check
+	}
+
+	skew := time.Since(d.serverDate).Round(time.Second)
+	abs := max(skew, -skew)
+	check.Detail = fmt.Sprintf("local clock is %s off the server's", skew)
+	switch {
+	// S3 rejects requests signed more than 15 minutes off its clock.
+	case abs >= 15*time.Minute:
+		check.Status = doctorFail
+		check.Hint = "Requests will fail with RequestTimeTooSkewed; sync the clock with NTP"
+	case abs >= time.Minute:
+		check.Status = doctorWarn
+		check.Hint = "The clock is drifting; enable NTP before it breaks request signing"
+	default:
+		check.Status = doctorPass
+	}
+	return check
+}
+
+func (d *doctor) proxy() doctorCheck {
+	check := doctorCheck{Name: "Proxy"}
+	var set []string
+	for _, name := range []string{"HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy", "NO_PROXY", "no_proxy"} {
+		if os.Getenv(name) != "" {
+			set = append(set, name)
+		}
+	}
+	if d.endpoint == nil {
+		check.Status, check.Detail = doctorSkip, "no endpoint"
+		return check
+	}
+
+	proxy, err := http.ProxyFromEnvironment(&http.Request{URL: d.endpoint})
+	switch {
+	case err != nil:
+		check.Status, check.Detail = doctorFail, fmt.Sprintf("invalid proxy setting: %v", err)
+		check.Hint = "Proxy variables must be URLs such as http://proxy.example.com:3128"
+	case proxy != nil:
+		check.Status, check.Detail = doctorPass, fmt.Sprintf("%s goes through %s", d.endpoint.Host, redactURL(proxy))
+	case len(set) > 0:
+		check.Status, check.Detail = doctorPass, fmt.Sprintf("%s is reached directly; %s set", d.endpoint.Host, strings.Join(set, ", "))
+		if d.endpoint.Scheme == "https" && os.Getenv("HTTPS_PROXY")+os.Getenv("https_proxy") == "" && os.Getenv("HTTP_PROXY")+os.Getenv("http_proxy") != "" {
+			check.Status = doctorWarn
+			check.Hint = "HTTP_PROXY doesn't apply to https endpoints; set HTTPS_PROXY as well"
+		}
+	default:
+		check.Status, check.Detail = doctorPass, "none configured"
+	}
+	return check
+}
+
+func (d *doctor) permissions() []doctorCheck {
+	if !d.haveCreds {
+		return []doctorCheck{{Name: "S3 access", Status: doctorSkip, Detail: "no credentials"}}
+	}
+	ctx, cancel := d.context()
+	defer cancel()
+	cc := cloud.New(d.cfg)
+
+	denied := func(name, action string, err error) doctorCheck {
+		check := doctorCheck{Name: name, Status: doctorFail, Detail: err.Error()}
+		switch code := apiErrorCode(err); code {
+		case "AccessDenied", "Forbidden", "403":
+			check.Detail = "denied"
+			check.Hint = "Grant " + action + " to the identity above, and check bucket policies and SCPs"
+		case "NoSuchBucket", "NotFound", "404":
+			check.Detail = "the bucket doesn't exist"
+			check.Hint = "Check the bucket name; bucket names are global and case-sensitive"
+		case "SignatureDoesNotMatch", "InvalidAccessKeyId":
+			check.Hint = "The secret key doesn't match the access key; re-enter the credentials"
+		case "":
+			check.Hint = "The request didn't get an answer; see the endpoint check above"
+		}
+		return check
+	}
+
+	var checks []doctorCheck
+	if result, err := cc.S3().ListBuckets(ctx, &s3.ListBucketsInput{MaxBuckets: aws.Int32(1000)}); err != nil {
+		check := denied("List buckets", "s3:ListAllMyBuckets", err)
+		if check.Detail == "denied" {
+			// Many roles are scoped to a few buckets on purpose.
+			check.Status = doctorWarn
+		}
+		checks = append(checks, check)
+	} else {
+		checks = append(checks, doctorCheck{Name: "List buckets", Status: doctorPass, Detail: fmt.Sprintf("%d buckets visible", len(result.Buckets))})
+	}
+
+	bucket := d.input.bucket
+	if bucket == "" {
+		return checks
+	}
+	client, err := cc.BucketClient(ctx, bucket)
+	if err != nil {
+		return append(checks, denied("Bucket "+bucket, "s3:ListBucket", err))
+	}
+	checks = append(checks, doctorCheck{Name: "Bucket " + bucket, Status: doctorPass, Detail: "in " + client.Options().Region})
+
+	if _, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{Bucket: &bucket, MaxKeys: aws.Int32(1)}); err != nil {
+		checks = append(checks, denied("List objects", "s3:ListBucket", err))
+	} else {
+		checks = append(checks, doctorCheck{Name: "List objects", Status: doctorPass, Detail: "allowed"})
+	}
+
+	if d.input.write {
+		key := fmt.Sprintf(".go-cloud-cli-doctor-%d", time.Now().UnixNano())
+		_, err := client.PutObject(ctx, &s3.PutObjectInput{Bucket: &bucket, Key: &key, Body: strings.NewReader("ok")})
+		if err != nil {
+			return append(checks, denied("Write objects", "s3:PutObject", err))
+		}
+		checks = append(checks, doctorCheck{Name: "Write objects", Status: doctorPass, Detail: "uploaded " + key})
+		if _, err := client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: &bucket, Key: &key}); err != nil {
+			check := denied("Delete objects", "s3:DeleteObject", err)
+			check.Hint = strings.TrimSpace(check.Hint + "; remove " + key + " by hand")
+			checks = append(checks, check)
+		} else {
+			checks = append(checks, doctorCheck{Name: "Delete objects", Status: doctorPass, Detail: "deleted " + key})
+		}
+	}
+	return checks
+}
+
+func runDoctor(input *doctorCmdInput) {
+	if input.write && input.bucket == "" {
+		fmt.Fprintln(os.Stderr, "--write needs --bucket")
+		os.Exit(2)
+	}
+	d := &doctor{input: input, timeout: time.Duration(input.timeout) * time.Second}
+
+	checks := []doctorCheck{d.credentials(), d.callerIdentity(), d.region(), d.reachability(), d.clockSkew(), d.proxy()}
+	checks = append(checks, d.permissions()...)
+
+	failed := false
+	for _, c := range checks {
+		failed = failed || c.Status == doctorFail
+	}
+
+	if jsonOutput(false) {
+		jsonData, _ := json.MarshalIndent(checks, "", "  ")
+		fmt.Println(string(jsonData))
+	} else {
+		width := 0
+		for _, c := range checks {
+			width = max(width, len(c.Name))
+		}
+		for _, c := range checks {
+			fmt.Printf("[%s] %-*s  %s\n", strings.ToUpper(c.Status), width, c.Name, c.Detail)
+			if c.Hint != "" {
+				fmt.Printf("       %-*s  hint: %s\n", width, "", c.Hint)
+			}
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func init() {
+	doctorCmd.Flags().String("bucket", "", "Also check access to this bucket")
+	doctorCmd.Flags().Bool("write", false, "Also upload and delete a small object in --bucket")
+	doctorCmd.Flags().IntP("timeout", "t", 10, "Timeout of each check in seconds")
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+type downloadCmdInput struct {
+	source      string
+	destination string
+	customerKey string
+	concurrency int
+	timeout     int
+}
+
+// downloadCmd represents the download command
+var downloadCmd = &cobra.Command{
+	Use:   "download s3://bucket/key <file or directory>",
+	Short: "Download an object, or every object under a prefix, from S3",
+	Long: `Downloads an object, or every object under a prefix ending in "/", and
+verifies each against the checksum S3 stored for it. Files are only moved into
+place once they have been verified, so pressing Ctrl-C leaves no partial
+files behind. Objects uploaded with a customer-provided key need the same key
+in --sse-c-key-file.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		customerKey, _ := cmd.Flags().GetString("sse-c-key-file")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		timeout, _ := cmd.Flags().GetInt("timeout")
+		download(&downloadCmdInput{
+			source:      args[0],
+			destination: args[1],
+			customerKey: customerKey,
+			concurrency: concurrency,
+			timeout:     timeout,
+		})
+	},
+}
+
+func download(input *downloadCmdInput) {
+	bucket, prefix, err := cloud.ParseS3URI(input.source)
+	if err != nil {
+		log.Fatalf("Invalid source: %v", err)
+	}
+	enc, err := newObjectEncryption("", "", input.customerKey)
+	if err != nil {
+		log.Fatalf("Invalid encryption: %v", err)
+	}
+
+	ctx, cancel := transferContext(time.Duration(input.timeout) * time.Second)
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+
+	cc := cloud.New(cfg)
+	jobs, err := cc.PlanDownload(ctx, bucket, prefix, input.destination, &cloud.PlanDownloadOptions{
+		Concurrency: input.concurrency,
+		Encryption:  enc,
+	})
+	if err != nil {
+		log.Fatalf("Unable to list %s: %v", input.source, err)
+	}
+
+	status := runTransfers(ctx, jobs, input.concurrency, func(ctx context.Context, job cloud.Transfer, progress *fileProgress) error {
+		return downloadFile(ctx, cc, job, &cloud.DownloadOptions{Encryption: enc, Progress: progress})
+	})
+	reportTransfers(ctx, status, "downloads", jsonOutput(false))
+
+	log.Printf("Downloaded %d objects to %s", len(jobs), input.destination)
+}
+
+func init() {
+	downloadCmd.Flags().String("sse-c-key-file", "", "File holding the customer-provided key the objects were uploaded with")
+	downloadCmd.Flags().Int("concurrency", 4, "Number of objects downloaded at once")
+	downloadCmd.Flags().IntP("timeout", "t", 3600, "Timeout in seconds")
+	rootCmd.AddCommand(downloadCmd)
+}
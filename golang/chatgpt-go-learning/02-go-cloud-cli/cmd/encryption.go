@@ -0,0 +1,329 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+type encryptionCmdInput struct {
+	name        string
+	sse         string
+	kmsKeyID    string
+	bucketKey   bool
+	prefix      string
+	sample      int
+	concurrency int
+	timeout     int
+}
+
+// encryptionScanReport is the outcome of encryption scan.
+type encryptionScanReport struct {
+	Bucket      string
+	Prefix      string
+	Default     string
+	Objects     int
+	Sampled     int
+	ByType      map[string]int
+	Unencrypted []string
+	Unreadable  []string
+}
+
+// encryptionCmd represents the encryption command
+var encryptionCmd = &cobra.Command{
+	Use:   "encryption",
+	Short: "Manage bucket default encryption and check objects are encrypted",
+}
+
+// encryptionGetCmd represents the encryption get command
+var encryptionGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Show the default encryption of a bucket",
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		getBucketEncryption(&encryptionCmdInput{name: name})
+	},
+}
+
+// encryptionSetCmd represents the encryption set command
+var encryptionSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set the default encryption of a bucket",
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		sse, _ := cmd.Flags().GetString("sse")
+		kmsKeyID, _ := cmd.Flags().GetString("kms-key-id")
+		bucketKey, _ := cmd.Flags().GetBool("bucket-key")
+		setBucketEncryption(&encryptionCmdInput{name: name, sse: sse, kmsKeyID: kmsKeyID, bucketKey: bucketKey})
+	},
+}
+
+// encryptionDisableCmd represents the encryption disable command
+var encryptionDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Remove the default encryption configuration of a bucket",
+	Long: `Removes the default encryption configuration of a bucket. S3 encrypts every
+new object with SSE-S3 (AES256) regardless, so this reverts the bucket to that.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		disableBucketEncryption(&encryptionCmdInput{name: name})
+	},
+}
+
+// encryptionScanCmd represents the encryption scan command
+var encryptionScanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Sample objects to find unencrypted ones",
+	Long: `Picks a random sample of the objects under a prefix and checks how each is
+encrypted. Exits with status 1 if any sampled object is unencrypted or can't be
+checked, so it can be used to show that encryption at rest is enforced.
+
+Objects encrypted with a customer-provided key (SSE-C) can't be inspected
+without the key and are reported as unreadable.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		prefix, _ := cmd.Flags().GetString("prefix")
+		sample, _ := cmd.Flags().GetInt("sample")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		timeout, _ := cmd.Flags().GetInt("timeout")
+		scanEncryption(&encryptionCmdInput{
+			name:        name,
+			prefix:      prefix,
+			sample:      sample,
+			concurrency: concurrency,
+			timeout:     timeout,
+		})
+	},
+}
+
+// newObjectEncryption builds the object encryption from the --sse,
+// --sse-kms-key-id and --sse-c-key-file flags. Any of them may be empty.
+func newObjectEncryption(sse, kmsKeyID, customerKeyFile string) (*cloud.ObjectEncryption, error) {
+	enc := &cloud.ObjectEncryption{KMSKeyID: kmsKeyID}
+
+	if sse != "" {
+		var err error
+		if enc.SSE, err = cloud.ServerSideEncryption(sse); err != nil {
+			return nil, err
+		}
+	}
+	if kmsKeyID != "" && !cloud.IsKMS(enc.SSE) {
+		return nil, errors.New("--sse-kms-key-id needs --sse aws:kms or aws:kms:dsse")
+	}
+
+	if customerKeyFile != "" {
+		if enc.SSE != "" {
+			return nil, errors.New("--sse and --sse-c-key-file can't be used together")
+		}
+		key, err := readCustomerKey(customerKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		enc.CustomerKey = key
+	}
+	return enc, nil
+}
+
+// readCustomerKey reads a 256 bit SSE-C key, either as 32 raw bytes or
+// base64 encoded.
+func readCustomerKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 32 {
+		return data, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("%s must hold a 256 bit key, as 32 raw bytes or base64", path)
+	}
+	return key, nil
+}
+
+func getBucketEncryption(input *encryptionCmdInput) {
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+
+	enc, err := cloud.New(cfg).BucketEncryption(ctx, input.name)
+	if err != nil {
+		log.Fatalf("Failed to get encryption: %v", err)
+	}
+
+	if jsonOutput(false) {
+		jsonData, _ := json.MarshalIndent(enc, "", "  ")
+		fmt.Println(string(jsonData))
+		return
+	}
+	if enc.SSE == "" {
+		fmt.Printf("%s has no default encryption configuration, S3 uses AES256\n", input.name)
+		return
+	}
+	fmt.Printf("SSE:        %s\n", enc.SSE)
+	if enc.KMSKeyID != "" {
+		fmt.Printf("KMS key:    %s\n", enc.KMSKeyID)
+	}
+	fmt.Printf("Bucket key: %t\n", enc.BucketKey)
+}
+
+func setBucketEncryption(input *encryptionCmdInput) {
+	sse, err := cloud.ServerSideEncryption(input.sse)
+	if err != nil {
+		log.Fatalf("Invalid --sse: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+
+	err = cloud.New(cfg).SetBucketEncryption(ctx, input.name, sse, &cloud.SetBucketEncryptionOptions{
+		KMSKeyID:  input.kmsKeyID,
+		BucketKey: input.bucketKey,
+	})
+	if err != nil {
+		log.Fatalf("Failed to set encryption: %v", err)
+	}
+
+	fmt.Printf("New objects in %s are encrypted with %s by default\n", input.name, sse)
+}
+
+func disableBucketEncryption(input *encryptionCmdInput) {
+	guardDestructive(input.name)
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+
+	if err := cloud.New(cfg).DisableBucketEncryption(ctx, input.name); err != nil {
+		log.Fatalf("Failed to remove encryption: %v", err)
+	}
+
+	fmt.Printf("Removed the default encryption of %s, new objects are encrypted with AES256\n", input.name)
+}
+
+func scanEncryption(input *encryptionCmdInput) {
+	if input.sample < 1 {
+		log.Fatalf("Invalid --sample %d, it must be at least 1", input.sample)
+	}
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), time.Duration(input.timeout)*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+
+	cc := cloud.New(cfg)
+	defaults, err := cc.BucketEncryption(ctx, input.name)
+	if err != nil {
+		log.Fatalf("Failed to get encryption: %v", err)
+	}
+
+	scan, err := cc.ScanEncryption(ctx, input.name, input.prefix, &cloud.ScanEncryptionOptions{
+		Sample:      input.sample,
+		Concurrency: input.concurrency,
+	})
+	if err != nil {
+		log.Fatalf("Unable to scan objects: %v", err)
+	}
+
+	report := encryptionScanReport{
+		Bucket:      input.name,
+		Prefix:      input.prefix,
+		Default:     defaults.SSE,
+		Objects:     scan.Objects,
+		Sampled:     scan.Sampled,
+		ByType:      scan.ByType,
+		Unencrypted: scan.Unencrypted,
+	}
+	for _, e := range scan.Unreadable {
+		log.Printf("Unable to check %s: %s", e.Key, e.Message)
+		report.Unreadable = append(report.Unreadable, e.Key)
+	}
+
+	if jsonOutput(false) {
+		jsonData, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(jsonData))
+	} else {
+		printEncryptionScan(&report)
+	}
+
+	if len(report.Unencrypted) > 0 || len(report.Unreadable) > 0 {
+		os.Exit(1)
+	}
+}
+
+func printEncryptionScan(report *encryptionScanReport) {
+	def := report.Default
+	if def == "" {
+		def = "none"
+	}
+	fmt.Printf("Bucket default: %s\n", def)
+	fmt.Printf("Sampled %d of %d objects under s3://%s/%s\n\n", report.Sampled, report.Objects, report.Bucket, report.Prefix)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ENCRYPTION\tOBJECTS")
+	for _, t := range slices.Sorted(maps.Keys(report.ByType)) {
+		fmt.Fprintf(w, "%s\t%d\n", t, report.ByType[t])
+	}
+	fmt.Fprintf(w, "none\t%d\n", len(report.Unencrypted))
+	if len(report.Unreadable) > 0 {
+		fmt.Fprintf(w, "unreadable\t%d\n", len(report.Unreadable))
+	}
+	w.Flush()
+
+	for _, key := range report.Unencrypted {
+		fmt.Printf("unencrypted: %s\n", key)
+	}
+	for _, key := range report.Unreadable {
+		fmt.Printf("unreadable: %s\n", key)
+	}
+}
+
+func init() {
+	encryptionCmd.PersistentFlags().StringP("name", "n", "", "Bucket Name")
+	encryptionCmd.MarkPersistentFlagRequired("name")
+
+	encryptionSetCmd.Flags().String("sse", "", "Encryption: AES256, aws:kms or aws:kms:dsse")
+	encryptionSetCmd.MarkFlagRequired("sse")
+	encryptionSetCmd.Flags().String("kms-key-id", "", "KMS key ID or ARN (default is the AWS managed aws/s3 key)")
+	encryptionSetCmd.Flags().Bool("bucket-key", true, "Use an S3 Bucket Key to reduce KMS requests")
+
+	encryptionScanCmd.Flags().String("prefix", "", "Only sample objects with this prefix")
+	encryptionScanCmd.Flags().Int("sample", 100, "Number of objects to sample")
+	encryptionScanCmd.Flags().Int("concurrency", 8, "Number of objects checked at once")
+	encryptionScanCmd.Flags().IntP("timeout", "t", 300, "Timeout in seconds")
+
+	encryptionCmd.AddCommand(encryptionGetCmd, encryptionSetCmd, encryptionDisableCmd, encryptionScanCmd)
+	rootCmd.AddCommand(encryptionCmd)
+}
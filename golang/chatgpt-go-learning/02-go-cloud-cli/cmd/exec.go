@@ -0,0 +1,144 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+)
+
+// execCommand is an --exec template split into the words of a command. Every
+// word is rendered on its own and handed to the command as a single argument,
+// without a shell, so that object keys holding blanks, quotes, ";" or "$(...)"
+// stay data and can't run anything.
+type execCommand []*template.Template
+
+// execFuncs are the functions --exec templates can use. shellquote is for
+// commands that hand a value to a shell themselves, e.g.
+// sh -c "gzip -d < {{shellquote .Key}}".
+var execFuncs = template.FuncMap{"shellquote": shellQuote}
+
+// parseExec splits an --exec template into words the way a shell would,
+// without expanding anything, and parses each word as a template.
+func parseExec(s string) (execCommand, error) {
+	words, err := splitCommand(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(words) == 0 {
+		return nil, errors.New("empty command")
+	}
+
+	command := make(execCommand, len(words))
+	for i, word := range words {
+		command[i], err = template.New(fmt.Sprintf("exec%d", i)).Funcs(execFuncs).Parse(word)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return command, nil
+}
+
+// args renders the words of the command with data.
+func (c execCommand) args(data any) ([]string, error) {
+	args := make([]string, len(c))
+	for i, t := range c {
+		var b strings.Builder
+		if err := t.Execute(&b, data); err != nil {
+			return nil, err
+		}
+		args[i] = b.String()
+	}
+	return args, nil
+}
+
+// run renders the command with data and runs it.
+func (c execCommand) run(data any) error {
+	args, err := c.args(data)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// splitCommand splits s into words at blanks that are neither quoted nor
+// inside a {{ }} action. Single quotes keep everything literally, double
+// quotes and backslashes escape the next character.
+func splitCommand(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case strings.HasPrefix(s[i:], "{{"):
+			end := strings.Index(s[i:], "}}")
+			if end < 0 {
+				return nil, errors.New("unclosed action")
+			}
+			word.WriteString(s[i : i+end+2])
+			i += end + 1
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, errors.New("unterminated quote")
+			}
+			word.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case c == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if strings.HasPrefix(s[i:], "{{") {
+					end := strings.Index(s[i:], "}}")
+					if end < 0 {
+						return nil, errors.New("unclosed action")
+					}
+					word.WriteString(s[i : i+end+2])
+					i += end + 1
+					continue
+				}
+				if s[i] == '\\' && i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\\') {
+					i++
+				}
+				word.WriteByte(s[i])
+			}
+			if i == len(s) {
+				return nil, errors.New("unterminated quote")
+			}
+			inWord = true
+		case c == '\\' && i+1 < len(s):
+			i++
+			word.WriteByte(s[i])
+			inWord = true
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,107 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+// hostileKeys are object keys that would run a command if they reached a
+// shell unquoted.
+var hostileKeys = []string{
+	"x;touch PWNED",
+	"$(touch PWNED)",
+	"`touch PWNED`",
+	"a b && touch PWNED",
+	"it's | touch PWNED",
+	`"; touch PWNED; "`,
+	"x\ntouch PWNED",
+}
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{`echo {{.Key}}`, []string{"echo", "{{.Key}}"}},
+		{`echo "{{.Event}}" "{{.Key}}"`, []string{"echo", "{{.Event}}", "{{.Key}}"}},
+		{`cp {{ printf "%s/%s" .Bucket .Key }} /tmp`, []string{"cp", `{{ printf "%s/%s" .Bucket .Key }}`, "/tmp"}},
+		{`sh -c 'echo "$1"' _ {{.Key}}`, []string{"sh", "-c", `echo "$1"`, "_", "{{.Key}}"}},
+		{`a\ b "c \"d\"" ''`, []string{"a b", `c "d"`, ""}},
+	}
+	for _, tt := range tests {
+		got, err := splitCommand(tt.in)
+		if err != nil {
+			t.Errorf("splitCommand(%q): %v", tt.in, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("splitCommand(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{`echo 'x`, `echo "x`, `echo {{.Key`} {
+		if _, err := splitCommand(in); err == nil {
+			t.Errorf("splitCommand(%q) succeeded, want an error", in)
+		}
+	}
+}
+
+func TestExecKeepsKeysAsArguments(t *testing.T) {
+	command, err := parseExec(`echo --key={{.Key}} "{{.Bucket}}"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range hostileKeys {
+		args, err := command.args(cloud.FoundObject{Bucket: "docs", Key: key})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"echo", "--key=" + key, "docs"}; !slices.Equal(args, want) {
+			t.Errorf("args for %q = %q, want %q", key, args, want)
+		}
+	}
+}
+
+func TestExecRunsNoShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	dir := t.TempDir()
+	t.Chdir(dir)
+	out := filepath.Join(dir, "out")
+
+	run := func(template string, key string) {
+		t.Helper()
+		command, err := parseExec(template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := command.run(map[string]string{"Key": key, "Out": out}); err != nil {
+			t.Fatalf("%s with %q: %v", template, key, err)
+		}
+	}
+
+	for _, key := range hostileKeys {
+		run(`true {{.Key}}`, key)
+		run(`sh -c "printf %s {{shellquote .Key}} > {{shellquote .Out}}"`, key)
+
+		if _, err := os.Stat(filepath.Join(dir, "PWNED")); err == nil {
+			t.Fatalf("key %q ran a command", key)
+		}
+		got, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != key {
+			t.Errorf("shellquote passed %q to the shell as %q", key, got)
+		}
+	}
+}
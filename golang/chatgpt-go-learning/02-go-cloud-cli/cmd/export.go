@@ -0,0 +1,255 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+type exportCmdInput struct {
+	source      string
+	archive     string
+	format      string
+	concurrency int
+	timeout     int
+}
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export s3://bucket/prefix backup.tar.zst",
+	Short: "Export a prefix into a tar or zip archive",
+	Long: `Writes every object under a prefix into an archive, together with a manifest
+of their keys, metadata and tags, so that import can recreate them in any
+bucket and account. The format follows the extension of the archive file:
+.tar, .tar.gz, .tar.zst or .zip.
+
+Objects are downloaded into a temporary directory first, the same way download
+does, so the archive can be larger than memory but needs as much free disk
+space again. Export is meant for small buckets such as configuration. Archived
+objects must be restored first.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		timeout, _ := cmd.Flags().GetInt("timeout")
+		exportPrefix(&exportCmdInput{args[0], args[1], format, concurrency, timeout})
+	},
+}
+
+// describeObjects reads the metadata and tags of every object for the
+// manifest.
+func describeObjects(ctx context.Context, cc *cloud.Client, bucket, prefix string, objects []types.Object, concurrency int) ([]archiveObject, error) {
+	described := make([]archiveObject, len(objects))
+	queue := make(chan int)
+	var mu sync.Mutex
+	var errs []error
+
+	var wg sync.WaitGroup
+	for range max(concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				key := aws.ToString(objects[i].Key)
+				o, err := describeObject(ctx, cc, bucket, key)
+				if err != nil {
+					mu.Lock()
+					errs = append(errs, fmt.Errorf("%s: %w", key, err))
+					mu.Unlock()
+					continue
+				}
+				o.Key = strings.TrimPrefix(key, prefix)
+				o.Path = archiveObjectsDir + o.Key
+				described[i] = *o
+			}
+		}()
+	}
+
+	for i := range objects {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	return described, errors.Join(errs...)
+}
+
+func describeObject(ctx context.Context, cc *cloud.Client, bucket, key string) (*archiveObject, error) {
+	head, err := cc.HeadObject(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	if state := cloud.RestoreStateOf(head).State; state == cloud.RestoreArchived || state == cloud.RestoreInProgress {
+		return nil, fmt.Errorf("the object is in %s, restore it first with restore request", head.StorageClass)
+	}
+	tags, err := cc.ObjectTags(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &archiveObject{
+		Size:         aws.ToInt64(head.ContentLength),
+		ETag:         aws.ToString(head.ETag),
+		LastModified: aws.ToTime(head.LastModified),
+		StorageClass: string(head.StorageClass),
+		ObjectMetadata: cloud.ObjectMetadata{
+			ContentType:        aws.ToString(head.ContentType),
+			CacheControl:       aws.ToString(head.CacheControl),
+			ContentDisposition: aws.ToString(head.ContentDisposition),
+			ContentEncoding:    aws.ToString(head.ContentEncoding),
+			ContentLanguage:    aws.ToString(head.ContentLanguage),
+			Metadata:           head.Metadata,
+		},
+	}
+	if len(tags) > 0 {
+		o.Tags = tags
+	}
+	return o, nil
+}
+
+// writeArchive writes the manifest and then the data of every object, read
+// from the files in dir that downloadObjects wrote.
+func writeArchive(manifest *archiveManifest, dir string, aw archiveWriter) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := aw.add(archiveManifestName, int64(len(data)), manifest.Created, bytes.NewReader(data)); err != nil {
+		return err
+	}
+
+	for i, o := range manifest.Objects {
+		f, err := os.Open(filepath.Join(dir, strconv.Itoa(i)))
+		if err != nil {
+			return err
+		}
+		err = aw.add(o.Path, o.Size, o.LastModified, f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", o.Key, err)
+		}
+	}
+	return nil
+}
+
+func exportPrefix(input *exportCmdInput) {
+	bucket, prefix, err := cloud.ParseS3URI(input.source)
+	if err != nil {
+		log.Fatalf("Invalid source: %v", err)
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	format, err := archiveFormat(input.archive, input.format)
+	if err != nil {
+		log.Fatalf("Invalid archive: %v", err)
+	}
+
+	ctx, cancel := transferContext(time.Duration(input.timeout) * time.Second)
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+
+	cc := cloud.New(cfg)
+	listed, err := cc.ListObjects(ctx, bucket, prefix, &cloud.ListObjectsOptions{Concurrency: input.concurrency})
+	if err != nil {
+		log.Fatalf("Unable to list %s: %v", input.source, err)
+	}
+	// Folder placeholders have no content worth keeping.
+	objects := slices.DeleteFunc(listed, func(o types.Object) bool { return strings.HasSuffix(aws.ToString(o.Key), "/") })
+	if len(objects) == 0 {
+		log.Fatalf("No objects under %s", input.source)
+	}
+
+	described, err := describeObjects(ctx, cc, bucket, prefix, objects, input.concurrency)
+	if err != nil {
+		log.Fatalf("Unable to export every object:\n%v", err)
+	}
+	manifest := &archiveManifest{
+		Version: archiveVersion,
+		Source:  "s3://" + bucket + "/" + prefix,
+		Created: time.Now().UTC(),
+		Objects: described,
+	}
+
+	// Download the objects the way download does, into files named by their
+	// position in the manifest, so keys can't reach outside dir.
+	dir, err := os.MkdirTemp("", "go-cloud-cli-export-")
+	if err != nil {
+		log.Fatalf("Unable to create a temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	jobs := make([]cloud.Transfer, len(described))
+	etags := map[string]string{}
+	for i, o := range described {
+		jobs[i] = cloud.Transfer{Bucket: bucket, Key: prefix + o.Key, Path: filepath.Join(dir, strconv.Itoa(i)), Size: o.Size}
+		etags[prefix+o.Key] = o.ETag
+	}
+	status := runTransfers(ctx, jobs, input.concurrency, func(ctx context.Context, job cloud.Transfer, progress *fileProgress) error {
+		// The object must be the one the manifest describes.
+		return downloadFile(ctx, cc, job, &cloud.DownloadOptions{IfMatch: etags[job.Key], Progress: progress})
+	})
+	// Fatal exits skip the deferred clean-up.
+	if ctx.Err() != nil || status.Failed > 0 {
+		os.RemoveAll(dir)
+	}
+	reportTransfers(ctx, status, "downloads", jsonOutput(false))
+
+	// Write next to the archive and move it into place once complete, so a
+	// failed export never leaves a truncated archive behind.
+	out, err := os.CreateTemp(filepath.Dir(input.archive), ".export-*")
+	if err != nil {
+		os.RemoveAll(dir)
+		log.Fatalf("Unable to create archive: %v", err)
+	}
+
+	aw, err := newArchiveWriter(out, format)
+	if err == nil {
+		err = writeArchive(manifest, dir, aw)
+		err = errors.Join(err, aw.Close())
+	}
+	err = errors.Join(err, out.Close())
+	if err == nil {
+		err = os.Rename(out.Name(), input.archive)
+	}
+	if err != nil {
+		os.Remove(out.Name())
+		os.RemoveAll(dir)
+		log.Fatalf("Failed to write archive: %v", err)
+	}
+
+	var size int64
+	for _, o := range described {
+		size += o.Size
+	}
+	log.Printf("Exported %d objects (%s) from %s to %s", len(described), formatBytes(size), manifest.Source, input.archive)
+}
+
+func init() {
+	exportCmd.Flags().String("format", "", "Archive format: tar, tar.gz, tar.zst or zip (default from the file extension)")
+	exportCmd.Flags().Int("concurrency", 8, "Number of objects described and downloaded at once")
+	exportCmd.Flags().IntP("timeout", "t", 3600, "Timeout in seconds")
+	rootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,225 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+type findCmdInput struct {
+	uri          string
+	name         string
+	largerThan   string
+	olderThan    string
+	storageClass string
+	tags         []string
+	print        bool
+	delete       bool
+	exec         string
+	yes          bool
+	concurrency  int
+	timeout      int
+}
+
+// findCmd represents the find command
+var findCmd = &cobra.Command{
+	Use:   "find s3://bucket/prefix",
+	Short: "Search for objects by name, size, age, storage class and tags",
+	Long: `Lists the objects under a prefix and applies an action to those matching
+every predicate, much like unix find. Without an action, matches are printed.
+The bucket is searched in its own region, so --regions and --all-regions don't
+apply; use list --all-regions to find the buckets to search.
+
+--exec runs a command for each match. The command is a Go template with the
+fields Bucket, Key, Size, LastModified, StorageClass and ETag, for example:
+
+  go-cloud-cli find s3://logs/2024/ --older-than 90d --exec 'echo "{{.Key}}"'
+
+The command is split into words before the fields are filled in and runs
+without a shell, so each field stays a single argument whatever the key holds.
+A command that passes a field to a shell must quote it with shellquote:
+
+  --exec 'sh -c "echo {{shellquote .Key}} >> expired.txt"'`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		largerThan, _ := cmd.Flags().GetString("larger-than")
+		olderThan, _ := cmd.Flags().GetString("older-than")
+		storageClass, _ := cmd.Flags().GetString("storage-class")
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		print, _ := cmd.Flags().GetBool("print")
+		del, _ := cmd.Flags().GetBool("delete")
+		execTemplate, _ := cmd.Flags().GetString("exec")
+		yes, _ := cmd.Flags().GetBool("yes")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		timeout, _ := cmd.Flags().GetInt("timeout")
+		findObjects(&findCmdInput{
+			uri:          args[0],
+			name:         name,
+			largerThan:   largerThan,
+			olderThan:    olderThan,
+			storageClass: storageClass,
+			tags:         tags,
+			print:        print,
+			delete:       del,
+			exec:         execTemplate,
+			yes:          yes,
+			concurrency:  concurrency,
+			timeout:      timeout,
+		})
+	},
+}
+
+// findOptions turns the predicate flags into options for cloud.FindObjects.
+func findOptions(input *findCmdInput) (*cloud.FindOptions, error) {
+	opts := &cloud.FindOptions{
+		Name:         input.name,
+		StorageClass: input.storageClass,
+		Concurrency:  input.concurrency,
+	}
+	if input.largerThan != "" {
+		size, err := parseSize(input.largerThan)
+		if err != nil {
+			return nil, err
+		}
+		opts.LargerThan = &size
+	}
+	if input.olderThan != "" {
+		age, err := parseAge(input.olderThan)
+		if err != nil {
+			return nil, err
+		}
+		opts.OlderThan = age
+	}
+	tags, err := parseTags(input.tags)
+	if err != nil {
+		return nil, err
+	}
+	opts.Tags = tags
+	return opts, nil
+}
+
+// parseTags parses k=v arguments into a map.
+func parseTags(tags []string) (map[string]string, error) {
+	parsed := map[string]string{}
+	for _, t := range tags {
+		k, v, ok := strings.Cut(t, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("%q is not of the form k=v", t)
+		}
+		parsed[k] = v
+	}
+	return parsed, nil
+}
+
+func findObjects(input *findCmdInput) {
+	bucket, prefix, err := cloud.ParseS3URI(input.uri)
+	if err != nil {
+		log.Fatalf("Invalid location: %v", err)
+	}
+	if input.delete {
+		guardDestructive(bucket)
+	}
+	opts, err := findOptions(input)
+	if err != nil {
+		log.Fatalf("Invalid predicate: %v", err)
+	}
+
+	var command execCommand
+	if input.exec != "" {
+		command, err = parseExec(input.exec)
+		if err != nil {
+			log.Fatalf("Invalid --exec template: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), time.Duration(input.timeout)*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+
+	cc := cloud.New(cfg)
+	matches, err := cc.FindObjects(ctx, bucket, prefix, opts)
+	if err != nil {
+		log.Fatalf("Unable to find objects in %s: %v", bucket, err)
+	}
+
+	if input.print || (!input.delete && command == nil) {
+		printFound(matches)
+	}
+	if command != nil {
+		for _, m := range matches {
+			if err := command.run(m); err != nil {
+				log.Printf("Command for %s failed: %v", m.Key, err)
+			}
+		}
+	}
+	if input.delete {
+		deleteFound(ctx, cc, bucket, matches, input.yes)
+	}
+}
+
+func printFound(matches []cloud.FoundObject) {
+	if jsonOutput(false) {
+		jsonData, _ := json.MarshalIndent(matches, "", "  ")
+		fmt.Println(string(jsonData))
+		return
+	}
+	for _, m := range matches {
+		fmt.Printf("s3://%s/%s\n", m.Bucket, m.Key)
+	}
+}
+
+func deleteFound(ctx context.Context, cc *cloud.Client, bucket string, matches []cloud.FoundObject, yes bool) {
+	if len(matches) == 0 {
+		return
+	}
+	if !yes && !confirm("Delete %d objects from %s?", len(matches), bucket) {
+		log.Fatalf("Aborted")
+	}
+
+	keys := make([]string, len(matches))
+	for i, m := range matches {
+		keys[i] = m.Key
+	}
+	deleted, err := cc.DeleteObjects(ctx, bucket, keys)
+	var objectsErr *cloud.ObjectsError
+	if errors.As(err, &objectsErr) {
+		for _, f := range objectsErr.Failed {
+			log.Printf("Failed to delete %s: %s", f.Key, f.Message)
+		}
+	} else if err != nil {
+		log.Fatalf("Failed to delete objects: %v", err)
+	}
+
+	log.Printf("Deleted %d objects from %s", deleted, bucket)
+}
+
+func init() {
+	findCmd.Flags().String("name", "", "Match object base names against a glob pattern")
+	findCmd.Flags().String("larger-than", "", "Match objects larger than a size, e.g. 10MB")
+	findCmd.Flags().String("older-than", "", "Match objects last modified longer ago than an age, e.g. 30d")
+	findCmd.Flags().String("storage-class", "", "Match objects in a storage class")
+	findCmd.Flags().StringSlice("tag", nil, "Match objects having tag k=v (repeatable)")
+	findCmd.Flags().Bool("print", false, "Print matching objects (the default action)")
+	findCmd.Flags().Bool("delete", false, "Delete matching objects")
+	findCmd.Flags().String("exec", "", "Run a templated command for each match")
+	findCmd.Flags().BoolP("yes", "y", false, "Don't ask for confirmation before deleting")
+	findCmd.Flags().Int("concurrency", 8, "Number of prefixes listed, and of object tags fetched, at once")
+	findCmd.Flags().IntP("timeout", "t", 300, "Timeout in seconds")
+	rootCmd.AddCommand(findCmd)
+}
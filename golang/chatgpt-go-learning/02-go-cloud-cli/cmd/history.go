@@ -0,0 +1,156 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type historyCmdInput struct {
+	bucket string
+	since  string
+	until  string
+	user   string
+	errors bool
+	limit  int
+}
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Query the audit log of mutating API calls",
+	Long: `Prints the records of the audit log, oldest first. Every create, put, delete,
+copy and restore call made by this host is recorded with who made it, the
+command line, the outcome and the AWS request ID.
+
+--since and --until take an age such as 24h or 7d, a date such as 2025-01-31,
+or an RFC 3339 time. For example, to find who deleted a bucket:
+
+  go-cloud-cli history --bucket logs --since 30d`,
+	Run: func(cmd *cobra.Command, args []string) {
+		bucket, _ := cmd.Flags().GetString("bucket")
+		since, _ := cmd.Flags().GetString("since")
+		until, _ := cmd.Flags().GetString("until")
+		user, _ := cmd.Flags().GetString("user")
+		errorsOnly, _ := cmd.Flags().GetBool("errors")
+		limit, _ := cmd.Flags().GetInt("limit")
+		showHistory(&historyCmdInput{bucket, since, until, user, errorsOnly, limit})
+	},
+}
+
+// parseTimeBound parses a --since or --until value.
+func parseTimeBound(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, nil
+	}
+	age, err := parseAge(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected an age, a date or an RFC 3339 time", s)
+	}
+	return time.Now().Add(-age), nil
+}
+
+func showHistory(input *historyCmdInput) {
+	var since, until time.Time
+	var err error
+	if input.since != "" {
+		if since, err = parseTimeBound(input.since); err != nil {
+			log.Fatalf("Invalid --since: %v", err)
+		}
+	}
+	if input.until != "" {
+		if until, err = parseTimeBound(input.until); err != nil {
+			log.Fatalf("Invalid --until: %v", err)
+		}
+	}
+
+	path := auditLogPath()
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		log.Fatalf("No audit log at %s yet", path)
+	}
+	if err != nil {
+		log.Fatalf("Unable to open audit log: %v", err)
+	}
+	defer f.Close()
+
+	var records []auditRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var r auditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			log.Printf("Skipping malformed record on line %d of %s", line, path)
+			continue
+		}
+		if input.bucket != "" && r.Bucket != input.bucket {
+			continue
+		}
+		if input.user != "" && r.User != input.user {
+			continue
+		}
+		if input.errors && r.Result != "error" {
+			continue
+		}
+		if !since.IsZero() && r.Time.Before(since) {
+			continue
+		}
+		if !until.IsZero() && r.Time.After(until) {
+			continue
+		}
+		records = append(records, r)
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatalf("Unable to read audit log: %v", err)
+	}
+
+	if input.limit > 0 && len(records) > input.limit {
+		records = records[len(records)-input.limit:]
+	}
+
+	if jsonOutput(false) {
+		jsonData, _ := json.MarshalIndent(records, "", "  ")
+		fmt.Println(string(jsonData))
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tUSER\tPROFILE\tREGION\tOPERATION\tTARGET\tRESULT\tREQUEST ID\tCOMMAND")
+	for _, r := range records {
+		target := r.Bucket
+		if r.Key != "" {
+			target += "/" + r.Key
+		}
+		result := r.Result
+		if r.Error != "" {
+			result += " (" + r.Error + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s@%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.Time.Local().Format(time.DateTime), r.User, r.Host, r.Profile, r.Region,
+			r.Operation, target, result, r.RequestID, strings.Join(r.Args, " "))
+	}
+	w.Flush()
+}
+
+func init() {
+	historyCmd.Flags().String("bucket", "", "Only show calls on this bucket")
+	historyCmd.Flags().String("since", "", "Only show calls made after this time")
+	historyCmd.Flags().String("until", "", "Only show calls made before this time")
+	historyCmd.Flags().String("user", "", "Only show calls made by this OS user")
+	historyCmd.Flags().Bool("errors", false, "Only show calls that failed")
+	historyCmd.Flags().Int("limit", 0, "Only show the last N matching calls")
+	rootCmd.AddCommand(historyCmd)
+}
@@ -0,0 +1,211 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+type importCmdInput struct {
+	archive          string
+	destination      string
+	format           string
+	keepStorageClass bool
+	checksum         string
+	partSize         string
+	concurrency      int
+	yes              bool
+	timeout          int
+}
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import backup.tar.zst s3://bucket/prefix",
+	Short: "Recreate the objects of an export archive under a prefix",
+	Long: `Uploads every object of an archive made by export under a prefix, with the
+metadata and tags recorded in its manifest. Objects get the default encryption
+of the destination bucket and, with --keep-storage-class, their original
+storage class.
+
+Objects already under the prefix are overwritten when their key is in the
+archive, after asking for confirmation.
+
+The archive is unpacked into a temporary directory first, and the objects are
+then uploaded the same way upload does: with a checksum S3 verifies, in parts
+above --part-size, and aborting the uploads in flight on Ctrl-C.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		keepStorageClass, _ := cmd.Flags().GetBool("keep-storage-class")
+		checksum, _ := cmd.Flags().GetString("checksum")
+		partSize, _ := cmd.Flags().GetString("part-size")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		yes, _ := cmd.Flags().GetBool("yes")
+		timeout, _ := cmd.Flags().GetInt("timeout")
+		importArchive(&importCmdInput{args[0], args[1], format, keepStorageClass, checksum, partSize, concurrency, yes, timeout})
+	},
+}
+
+// unpackArchive writes the objects of an archive into dir, one file each,
+// and returns the uploads that recreate them under prefix. Files are named by
+// their position in the manifest, so keys can't reach outside dir.
+func unpackArchive(ar archiveReader, manifest *archiveManifest, dir, bucket, prefix string) ([]cloud.Transfer, error) {
+	objects := map[string]int{}
+	for i, o := range manifest.Objects {
+		objects[o.Path] = i
+	}
+
+	var jobs []cloud.Transfer
+	for {
+		name, entrySize, r, err := ar.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		i, ok := objects[name]
+		if !ok {
+			log.Printf("Skipping %s, which the manifest doesn't list", name)
+			continue
+		}
+		o := manifest.Objects[i]
+		if entrySize != o.Size {
+			return nil, fmt.Errorf("%s holds %d bytes, the manifest says %d; the archive is damaged", name, entrySize, o.Size)
+		}
+		delete(objects, name)
+
+		path := filepath.Join(dir, strconv.Itoa(i))
+		if err := writeFile(path, r); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		jobs = append(jobs, cloud.Transfer{Bucket: bucket, Key: prefix + o.Key, Path: path, Size: o.Size})
+	}
+
+	if len(objects) > 0 {
+		return nil, fmt.Errorf("the archive is missing %d objects listed in its manifest", len(objects))
+	}
+	return jobs, nil
+}
+
+func writeFile(path string, r io.Reader) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	return errors.Join(err, f.Close())
+}
+
+func importArchive(input *importCmdInput) {
+	bucket, prefix, err := cloud.ParseS3URI(input.destination)
+	if err != nil {
+		log.Fatalf("Invalid destination: %v", err)
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	format, err := archiveFormat(input.archive, input.format)
+	if err != nil {
+		log.Fatalf("Invalid archive: %v", err)
+	}
+	algorithm, err := cloud.ChecksumAlgorithm(input.checksum)
+	if err != nil {
+		log.Fatalf("Invalid checksum: %v", err)
+	}
+	partSize, err := parseSize(input.partSize)
+	if err != nil || partSize < cloud.MinPartSize {
+		log.Fatalf("Invalid --part-size %q, it must be at least 5MiB", input.partSize)
+	}
+	guardDestructive(bucket)
+
+	ar, err := openArchive(input.archive, format)
+	if err != nil {
+		log.Fatalf("Unable to open archive: %v", err)
+	}
+	defer ar.Close()
+	manifest, err := readArchiveManifest(ar)
+	if err != nil {
+		log.Fatalf("Unable to read archive: %v", err)
+	}
+	attributes := map[string]*cloud.ObjectAttributes{}
+	for _, o := range manifest.Objects {
+		attrs := &cloud.ObjectAttributes{ObjectMetadata: o.ObjectMetadata, Tags: o.Tags}
+		if input.keepStorageClass {
+			attrs.StorageClass = types.StorageClass(o.StorageClass)
+		}
+		attributes[prefix+o.Key] = attrs
+	}
+
+	ctx, cancel := transferContext(time.Duration(input.timeout) * time.Second)
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+	cc := cloud.New(cfg)
+
+	existing, err := cc.HasObjects(ctx, bucket, prefix)
+	if err != nil {
+		log.Fatalf("Unable to list %s: %v", input.destination, err)
+	}
+	if existing && !input.yes &&
+		!confirm("s3://%s/%s already has objects. Import %d objects from %s, overwriting any with the same key?", bucket, prefix, len(manifest.Objects), manifest.Source) {
+		log.Fatalf("Aborted")
+	}
+
+	dir, err := os.MkdirTemp("", "go-cloud-cli-import-")
+	if err != nil {
+		log.Fatalf("Unable to unpack archive: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	jobs, err := unpackArchive(ar, manifest, dir, bucket, prefix)
+	if err != nil {
+		os.RemoveAll(dir)
+		log.Fatalf("Unable to unpack archive: %v", err)
+	}
+
+	status := runTransfers(ctx, jobs, input.concurrency, func(ctx context.Context, job cloud.Transfer, progress *fileProgress) error {
+		return cc.UploadFile(ctx, job, &cloud.UploadOptions{
+			ChecksumAlgorithm: algorithm,
+			PartSize:          partSize,
+			Attributes:        attributes[job.Key],
+			Progress:          progress,
+		})
+	})
+	// Fatal exits skip the deferred clean-up.
+	if ctx.Err() != nil || status.Failed > 0 {
+		os.RemoveAll(dir)
+	}
+	reportTransfers(ctx, status, "imports", jsonOutput(false))
+
+	log.Printf("Imported %d objects (%s) to s3://%s/%s, exported from %s on %s",
+		status.Files, formatBytes(status.Bytes), bucket, prefix, manifest.Source, manifest.Created.Local().Format(time.DateTime))
+}
+
+func init() {
+	importCmd.Flags().String("format", "", "Archive format: tar, tar.gz, tar.zst or zip (default from the file extension)")
+	importCmd.Flags().Bool("keep-storage-class", false, "Give objects the storage class they had when exported")
+	importCmd.Flags().String("checksum", "crc32c", "Checksum algorithm: crc32c or sha256")
+	importCmd.Flags().String("part-size", "64MiB", "Upload objects larger than this in parts of this size")
+	importCmd.Flags().Int("concurrency", 4, "Number of objects uploaded at once")
+	importCmd.Flags().BoolP("yes", "y", false, "Don't ask for confirmation before writing to a prefix that has objects")
+	importCmd.Flags().IntP("timeout", "t", 3600, "Timeout in seconds")
+	rootCmd.AddCommand(importCmd)
+}
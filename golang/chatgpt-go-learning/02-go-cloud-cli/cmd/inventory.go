@@ -0,0 +1,232 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+// inventoryDeliveryService is the principal that writes inventory reports.
+const inventoryDeliveryService = "s3.amazonaws.com"
+
+type inventoryCmdInput struct {
+	name               string
+	id                 string
+	destination        string
+	destinationAccount string
+	schedule           string
+	format             string
+	fields             []string
+	prefix             string
+	versions           string
+	disabled           bool
+	grant              bool
+	yes                bool
+}
+
+// inventoryCmd represents the inventory command
+var inventoryCmd = &cobra.Command{
+	Use:   "inventory",
+	Short: "Manage the S3 Inventory reports of a bucket",
+}
+
+// inventoryListCmd represents the inventory list command
+var inventoryListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the inventory configurations of a bucket",
+	Run: func(cmd *cobra.Command, args []string) {
+		requireName(cmd)
+		name, _ := cmd.Flags().GetString("name")
+		listInventories(&inventoryCmdInput{name: name})
+	},
+}
+
+// inventorySetCmd represents the inventory set command
+var inventorySetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Create or replace an inventory configuration",
+	Long: `Makes S3 write a daily or weekly list of the objects of a bucket, with the
+chosen fields, as CSV, ORC or Parquet files. Reports go to
+<destination>/<bucket>/<id>/ and the first one can take up to 48 hours.
+
+The destination bucket must be in the same region and must let
+s3.amazonaws.com write to it. --grant adds that permission to its policy.
+
+Fields are any of: ` + strings.Join(cloud.InventoryFieldNames(), ", ") + `.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		requireName(cmd)
+		name, _ := cmd.Flags().GetString("name")
+		id, _ := cmd.Flags().GetString("id")
+		destination, _ := cmd.Flags().GetString("destination")
+		destinationAccount, _ := cmd.Flags().GetString("destination-account")
+		schedule, _ := cmd.Flags().GetString("schedule")
+		format, _ := cmd.Flags().GetString("format")
+		fields, _ := cmd.Flags().GetStringSlice("fields")
+		prefix, _ := cmd.Flags().GetString("prefix")
+		versions, _ := cmd.Flags().GetString("versions")
+		disabled, _ := cmd.Flags().GetBool("disabled")
+		grant, _ := cmd.Flags().GetBool("grant")
+		yes, _ := cmd.Flags().GetBool("yes")
+		setInventory(&inventoryCmdInput{name, id, destination, destinationAccount, schedule, format, fields, prefix, versions, disabled, grant, yes})
+	},
+}
+
+// inventoryDeleteCmd represents the inventory delete command
+var inventoryDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete an inventory configuration",
+	Run: func(cmd *cobra.Command, args []string) {
+		requireName(cmd)
+		name, _ := cmd.Flags().GetString("name")
+		id, _ := cmd.Flags().GetString("id")
+		deleteInventory(&inventoryCmdInput{name: name, id: id})
+	},
+}
+
+// requireName fails like a missing required flag when --name isn't given.
+func requireName(cmd *cobra.Command) {
+	if name, _ := cmd.Flags().GetString("name"); name == "" {
+		log.Fatalf(`required flag(s) "name" not set`)
+	}
+}
+
+func listInventories(input *inventoryCmdInput) {
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 30*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+	cc := cloud.New(cfg)
+
+	inventories, err := cc.Inventories(ctx, input.name)
+	if err != nil {
+		log.Fatalf("Failed to list inventory configurations: %v", err)
+	}
+
+	if jsonOutput(false) {
+		jsonData, _ := json.MarshalIndent(inventories, "", "  ")
+		fmt.Println(string(jsonData))
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tENABLED\tSCHEDULE\tFORMAT\tDESTINATION\tPREFIX\tVERSIONS\tFIELDS")
+	for _, c := range inventories {
+		fmt.Fprintf(w, "%s\t%t\t%s\t%s\t%s\t%s\t%s\t%s\n", c.ID, c.Enabled, c.Schedule, c.Format, c.Destination, c.Prefix, c.Versions, strings.Join(c.Fields, ","))
+	}
+	w.Flush()
+}
+
+func setInventory(input *inventoryCmdInput) {
+	opts := &cloud.PutInventoryOptions{
+		Destination:        input.destination,
+		DestinationAccount: input.destinationAccount,
+		Schedule:           input.schedule,
+		Format:             input.format,
+		Fields:             input.fields,
+		Prefix:             input.prefix,
+		Versions:           input.versions,
+		Disabled:           input.disabled,
+	}
+	if err := opts.Validate(); err != nil {
+		log.Fatalf("Invalid inventory configuration: %v", err)
+	}
+	destBucket, _, _ := cloud.ParseS3URI(input.destination)
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 30*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+	cc := cloud.New(cfg)
+
+	// A bucket of another account can't be checked or granted from here.
+	if input.destinationAccount == "" {
+		if err := cc.CheckSameRegion(ctx, input.name, destBucket); err != nil {
+			log.Fatalf("Unable to write reports to %s: %v; inventory reports can only go to a bucket in the same region", destBucket, err)
+		}
+
+		if input.grant {
+			if !input.yes && !confirm("Let %s write the inventory reports of %s to %s?", inventoryDeliveryService, input.name, input.destination) {
+				log.Fatalf("Aborted")
+			}
+			condition := map[string]any{"StringEquals": map[string]any{"s3:x-amz-acl": "bucket-owner-full-control"}}
+			if _, err := grantDelivery(ctx, cc, "S3InventoryDelivery", destBucket, "", input.name, inventoryDeliveryService, condition); err != nil {
+				log.Fatalf("Failed to update the policy of %s: %v", destBucket, err)
+			}
+		} else if ok, err := deliveryAllowed(ctx, cc, destBucket, inventoryDeliveryService); err == nil && !ok {
+			log.Printf("Warning: the policy of %s doesn't let %s write to it, so no reports will arrive; --grant adds it", destBucket, inventoryDeliveryService)
+		}
+	} else if input.grant {
+		log.Fatalf("--grant can't change the policy of a bucket in another account")
+	}
+
+	inventory, err := cc.PutInventory(ctx, input.name, input.id, opts)
+	if err != nil {
+		log.Fatalf("Failed to set inventory configuration: %v", err)
+	}
+
+	reports := strings.TrimSuffix(inventory.Destination, "/") + "/"
+	fmt.Printf("Inventory %s of %s set; %s reports will be written to %s%s/%s/\n", input.id, input.name, strings.ToLower(inventory.Schedule), reports, input.name, input.id)
+}
+
+func deleteInventory(input *inventoryCmdInput) {
+	guardDestructive(input.name)
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+	cc := cloud.New(cfg)
+
+	if err := cc.DeleteInventory(ctx, input.name, input.id); err != nil {
+		log.Fatalf("Failed to delete inventory configuration: %v", err)
+	}
+
+	fmt.Printf("Inventory %s deleted from %s\n", input.id, input.name)
+}
+
+func init() {
+	// --name isn't required for the group, since inventory read can take the
+	// location of the reports instead.
+	inventoryCmd.PersistentFlags().StringP("name", "n", "", "Bucket Name")
+
+	inventorySetCmd.Flags().String("id", "", "Inventory configuration ID")
+	inventorySetCmd.MarkFlagRequired("id")
+	inventorySetCmd.Flags().String("destination", "", "Where to write the reports, as s3://bucket/prefix")
+	inventorySetCmd.MarkFlagRequired("destination")
+	inventorySetCmd.Flags().String("destination-account", "", "Account that owns the destination bucket, if it's another one")
+	inventorySetCmd.Flags().String("schedule", "daily", "How often to write a report: daily or weekly")
+	inventorySetCmd.Flags().String("format", "csv", "Format of the reports: csv, orc or parquet")
+	inventorySetCmd.Flags().StringSlice("fields", []string{"Size", "LastModifiedDate", "StorageClass"}, "Fields to list besides the bucket and key")
+	inventorySetCmd.Flags().String("prefix", "", "Only list objects under this prefix")
+	inventorySetCmd.Flags().String("versions", "current", "Versions to list: current or all")
+	inventorySetCmd.Flags().Bool("disabled", false, "Create the configuration without writing reports")
+	inventorySetCmd.Flags().Bool("grant", false, "Let S3 write the reports to the destination bucket")
+	inventorySetCmd.Flags().BoolP("yes", "y", false, "Don't ask for confirmation before changing the policy of the destination bucket")
+
+	inventoryDeleteCmd.Flags().String("id", "", "Inventory configuration ID")
+	inventoryDeleteCmd.MarkFlagRequired("id")
+
+	inventoryCmd.AddCommand(inventoryListCmd, inventorySetCmd, inventoryDeleteCmd)
+	rootCmd.AddCommand(inventoryCmd)
+}
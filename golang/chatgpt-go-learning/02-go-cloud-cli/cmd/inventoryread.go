@@ -0,0 +1,738 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"cmp"
+	"compress/gzip"
+	"context"
+	"crypto/md5"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"os/signal"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+type inventoryReadCmdInput struct {
+	location    string
+	name        string
+	id          string
+	date        string
+	where       string
+	groupBy     string
+	depth       int
+	list        bool
+	concurrency int
+	timeout     int
+}
+
+// inventoryFile is a data file of an inventory report.
+type inventoryFile struct {
+	Key         string `json:"key"`
+	Size        int64  `json:"size"`
+	MD5checksum string `json:"MD5checksum"`
+}
+
+// inventoryManifest is the manifest.json S3 writes along with every
+// inventory report.
+type inventoryManifest struct {
+	SourceBucket      string          `json:"sourceBucket"`
+	DestinationBucket string          `json:"destinationBucket"`
+	Version           string          `json:"version"`
+	CreationTimestamp string          `json:"creationTimestamp"`
+	FileFormat        string          `json:"fileFormat"`
+	FileSchema        string          `json:"fileSchema"`
+	Files             []inventoryFile `json:"files"`
+}
+
+// inventoryGroup is the number and size of the objects sharing a value.
+type inventoryGroup struct {
+	Group   string
+	Objects int64
+	Size    int64
+}
+
+// inventorySummary is what inventory read reports about the objects of a
+// report that match the condition.
+type inventorySummary struct {
+	Manifest     string
+	SourceBucket string
+	Created      time.Time
+	Format       string
+	Files        int
+	Objects      int64
+	Size         int64
+	GroupBy      string           `json:",omitempty"`
+	Groups       []inventoryGroup `json:",omitempty"`
+}
+
+// inventoryReadCmd represents the inventory read command
+var inventoryReadCmd = &cobra.Command{
+	Use:   "read [s3://bucket/prefix/source/id/ or .../manifest.json]",
+	Short: "Summarize or list the objects of the latest inventory report",
+	Long: `Reads the latest inventory report of a bucket and counts its objects and their
+size, grouped by storage class or any other field, without listing the bucket.
+On buckets with billions of objects this takes minutes instead of days.
+
+The report is either given by its location, the destination of the inventory
+followed by <source bucket>/<id>/, or found from --name and --id. The latest
+complete report is read unless --date picks another one, and a manifest.json
+can also be given directly. CSV and ORC reports are supported, Parquet is not.
+
+--where filters objects with the conditions of an S3 Select WHERE clause on
+the fields of the report, for example:
+
+  go-cloud-cli inventory read -n data --id daily --where "StorageClass = 'STANDARD' AND Size < 131072"
+
+--group-by takes a field, or "prefix" to group by the first --depth levels of
+the key. Empty fields are NULL. --list writes the matching objects instead,
+as CSV with a header, or JSON lines with -o json.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		id, _ := cmd.Flags().GetString("id")
+		date, _ := cmd.Flags().GetString("date")
+		where, _ := cmd.Flags().GetString("where")
+		groupBy, _ := cmd.Flags().GetString("group-by")
+		depth, _ := cmd.Flags().GetInt("depth")
+		list, _ := cmd.Flags().GetBool("list")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		timeout, _ := cmd.Flags().GetInt("timeout")
+		var location string
+		if len(args) > 0 {
+			location = args[0]
+		}
+		readInventory(&inventoryReadCmdInput{location, name, id, date, where, groupBy, depth, list, concurrency, timeout})
+	},
+}
+
+// inventoryDatePattern matches the folders S3 writes each report to.
+var inventoryDatePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}-\d{2}Z$`)
+
+// inventoryLocation returns the bucket and the key of a manifest, or the
+// folder holding the reports of an inventory configuration.
+func inventoryLocation(ctx context.Context, cc *cloud.Client, input *inventoryReadCmdInput) (string, string, error) {
+	if input.location != "" {
+		bucket, key, err := cloud.ParseS3URI(input.location)
+		if err != nil {
+			return "", "", err
+		}
+		if path.Base(key) == "manifest.json" {
+			return bucket, key, nil
+		}
+		key = strings.TrimSuffix(key, "/") + "/"
+		if inventoryDatePattern.MatchString(path.Base(key)) {
+			return bucket, key + "manifest.json", nil
+		}
+		return bucket, key, nil
+	}
+
+	inventory, err := cc.Inventory(ctx, input.name, input.id)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get inventory configuration: %w", err)
+	}
+	bucket, prefix, _ := cloud.ParseS3URI(inventory.Destination)
+	if prefix != "" {
+		prefix = strings.TrimSuffix(prefix, "/") + "/"
+	}
+	return bucket, prefix + input.name + "/" + input.id + "/", nil
+}
+
+// getObjectBytes reads a whole object.
+func getObjectBytes(ctx context.Context, client *s3.Client, bucket, key string) ([]byte, error) {
+	result, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: &bucket, Key: &key})
+	if err != nil {
+		return nil, err
+	}
+	defer result.Body.Close()
+	return io.ReadAll(result.Body)
+}
+
+// readManifest reads a manifest and checks it against the manifest.checksum
+// next to it. complete is false when there is no checksum, which S3 writes
+// once the report is complete.
+func readManifest(ctx context.Context, client *s3.Client, bucket, key string) (manifest *inventoryManifest, complete bool, err error) {
+	checksum, err := getObjectBytes(ctx, client, bucket, path.Dir(key)+"/manifest.checksum")
+	if err != nil && apiErrorCode(err) != "NoSuchKey" {
+		return nil, false, err
+	}
+	data, err := getObjectBytes(ctx, client, bucket, key)
+	if err != nil {
+		return nil, false, err
+	}
+	if checksum != nil {
+		sum := md5.Sum(data)
+		if want := strings.TrimSpace(string(checksum)); !strings.EqualFold(hex.EncodeToString(sum[:]), want) {
+			return nil, false, fmt.Errorf("the checksum of s3://%s/%s doesn't match manifest.checksum", bucket, key)
+		}
+	}
+	manifest = &inventoryManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, false, fmt.Errorf("invalid manifest: %w", err)
+	}
+	return manifest, checksum != nil, nil
+}
+
+// latestManifest finds the latest complete report in the folder of an
+// inventory configuration, or the latest one of a date.
+func latestManifest(ctx context.Context, client *s3.Client, bucket, prefix, date string) (string, *inventoryManifest, error) {
+	var dates []string
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket:    &bucket,
+		Prefix:    &prefix,
+		Delimiter: aws.String("/"),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return "", nil, err
+		}
+		for _, p := range page.CommonPrefixes {
+			d := path.Base(aws.ToString(p.Prefix))
+			if inventoryDatePattern.MatchString(d) && strings.HasPrefix(d, date) {
+				dates = append(dates, d)
+			}
+		}
+	}
+	if len(dates) == 0 {
+		if date != "" {
+			return "", nil, fmt.Errorf("no report of %s under s3://%s/%s", date, bucket, prefix)
+		}
+		return "", nil, fmt.Errorf("no reports under s3://%s/%s; the first one can take up to 48 hours", bucket, prefix)
+	}
+
+	slices.Sort(dates)
+	slices.Reverse(dates)
+	for _, d := range dates {
+		key := prefix + d + "/manifest.json"
+		manifest, complete, err := readManifest(ctx, client, bucket, key)
+		if apiErrorCode(err) == "NoSuchKey" || err == nil && !complete {
+			log.Printf("Skipping the report of %s, which isn't complete", d)
+			continue
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		return key, manifest, nil
+	}
+	return "", nil, fmt.Errorf("no complete report under s3://%s/%s", bucket, prefix)
+}
+
+// inventoryFieldName turns the snake case column names of ORC and Parquet
+// reports into the names CSV reports use, e.g. e_tag into ETag.
+func inventoryFieldName(column string) string {
+	parts := strings.Split(column, "_")
+	for i, p := range parts {
+		if p != "" {
+			parts[i] = strings.ToUpper(p[:1]) + p[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// inventoryScan holds what is gathered from the files of a report.
+type inventoryScan struct {
+	fields    []string
+	query     *selectQuery
+	keyIndex  int
+	sizeIndex int
+	// groupIndex is the field to group by, or -1 to group by key prefix.
+	groupIndex int
+	groupBy    string
+	depth      int
+
+	mu      sync.Mutex
+	out     *selectWriter
+	objects int64
+	size    int64
+	groups  map[string]*inventoryGroup
+}
+
+// inventoryCounts is what a single file adds to a scan.
+type inventoryCounts struct {
+	objects, size int64
+	groups        map[string]*inventoryGroup
+}
+
+// keyPrefix returns the first depth levels of a key, ending in "/".
+func keyPrefix(key string, depth int) string {
+	end := 0
+	for range depth {
+		i := strings.IndexByte(key[end:], '/')
+		if i < 0 {
+			break
+		}
+		end += i + 1
+	}
+	return key[:end]
+}
+
+func inventoryInt(v any) int64 {
+	switch n := v.(type) {
+	case json.Number:
+		i, _ := n.Int64()
+		return i
+	case string:
+		i, _ := strconv.ParseInt(n, 10, 64)
+		return i
+	}
+	return 0
+}
+
+// record adds a row of a report to the counts of a file, and writes it out
+// when objects are listed.
+func (s *inventoryScan) record(counts *inventoryCounts, rec *selectRecord) error {
+	if !s.query.matches(rec) {
+		return nil
+	}
+	if s.out != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.out.write(s.query, rec)
+	}
+
+	var size int64
+	if s.sizeIndex >= 0 {
+		size = inventoryInt(rec.row[s.sizeIndex])
+	}
+	counts.objects++
+	counts.size += size
+
+	var group string
+	switch {
+	case s.groupBy == "":
+		return nil
+	case s.groupIndex >= 0:
+		group = formatSelectValue(rec.row[s.groupIndex])
+	default:
+		group = keyPrefix(formatSelectValue(rec.row[s.keyIndex]), s.depth)
+	}
+	g := counts.groups[group]
+	if g == nil {
+		g = &inventoryGroup{Group: group}
+		counts.groups[group] = g
+	}
+	g.Objects++
+	g.Size += size
+	return nil
+}
+
+func (s *inventoryScan) merge(counts *inventoryCounts) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects += counts.objects
+	s.size += counts.size
+	for name, g := range counts.groups {
+		total := s.groups[name]
+		if total == nil {
+			total = &inventoryGroup{Group: name}
+			s.groups[name] = total
+		}
+		total.Objects += g.Objects
+		total.Size += g.Size
+	}
+}
+
+// readFile reads a data file of a report.
+func (s *inventoryScan) readFile(ctx context.Context, client *s3.Client, bucket, format string, file inventoryFile) error {
+	err := s.scanFile(func(row []any, emit func() error) error {
+		switch format {
+		case "CSV":
+			result, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: &bucket, Key: &file.Key})
+			if err != nil {
+				return err
+			}
+			defer result.Body.Close()
+			return s.readCSV(result.Body, file.MD5checksum, row, emit)
+		case "ORC":
+			return s.readORC(&s3ReaderAt{ctx: ctx, client: client, bucket: bucket, key: file.Key}, file.Size, row, emit)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("s3://%s/%s: %w", bucket, file.Key, err)
+	}
+	return nil
+}
+
+// scanFile counts the rows read calls emit for into the scan. They only count
+// once the whole file is read, so a file that fails part way adds nothing.
+func (s *inventoryScan) scanFile(read func(row []any, emit func() error) error) error {
+	counts := &inventoryCounts{groups: map[string]*inventoryGroup{}}
+	rec := &selectRecord{fields: map[string]any{}, names: s.fields, row: make([]any, len(s.fields))}
+	emit := func() error {
+		clear(rec.fields)
+		for i, name := range s.fields {
+			rec.fields[name] = rec.row[i]
+		}
+		return s.record(counts, rec)
+	}
+
+	if err := read(rec.row, emit); err != nil {
+		return err
+	}
+	s.merge(counts)
+	return nil
+}
+
+// readCSV reads a gzipped CSV file, checking it against its MD5 checksum
+// unless that is empty. Keys are URL encoded.
+func (s *inventoryScan) readCSV(r io.Reader, checksum string, row []any, emit func() error) error {
+	hash := md5.New()
+	body := io.TeeReader(r, hash)
+	gz, err := gzip.NewReader(body)
+	if err != nil {
+		return err
+	}
+	cr := csv.NewReader(gz)
+	cr.FieldsPerRecord = len(s.fields)
+	cr.ReuseRecord = true
+	for {
+		values, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		for i, v := range values {
+			switch v {
+			case "":
+				row[i] = nil
+			case "true", "false":
+				row[i] = v == "true"
+			default:
+				row[i] = v
+			}
+		}
+		// Sizes are numbers, as they are in ORC reports.
+		if s.sizeIndex >= 0 {
+			if size, ok := row[s.sizeIndex].(string); ok {
+				if _, err := strconv.ParseInt(size, 10, 64); err == nil {
+					row[s.sizeIndex] = json.Number(size)
+				}
+			}
+		}
+		if key, ok := row[s.keyIndex].(string); ok {
+			if decoded, err := url.QueryUnescape(key); err == nil {
+				row[s.keyIndex] = decoded
+			}
+		}
+		if err := emit(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		return err
+	}
+	if sum := hex.EncodeToString(hash.Sum(nil)); checksum != "" && !strings.EqualFold(sum, checksum) {
+		return errors.New("MD5 checksum mismatch")
+	}
+	return nil
+}
+
+// s3ReaderAt reads an object with ranged GETs, failing if it changes
+// between them.
+type s3ReaderAt struct {
+	ctx    context.Context
+	client *s3.Client
+	bucket string
+	key    string
+	etag   *string
+}
+
+func (r *s3ReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	result, err := r.client.GetObject(r.ctx, &s3.GetObjectInput{
+		Bucket:  &r.bucket,
+		Key:     &r.key,
+		Range:   aws.String(fmt.Sprintf("bytes=%d-%d", off, off+int64(len(p))-1)),
+		IfMatch: r.etag,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer result.Body.Close()
+	if r.etag == nil {
+		r.etag = result.ETag
+	}
+	return io.ReadFull(result.Body, p)
+}
+
+// readORC reads an ORC file of the given size, a stripe at a time.
+func (s *inventoryScan) readORC(r io.ReaderAt, size int64, row []any, emit func() error) error {
+	f, err := openORC(r, size)
+	if err != nil {
+		return err
+	}
+	defer f.close()
+	if len(f.columns()) != len(s.fields) {
+		return fmt.Errorf("the file has %d columns but the schema has %d", len(f.columns()), len(s.fields))
+	}
+	return f.readRows(func(values []any) error {
+		for i, v := range values {
+			switch x := v.(type) {
+			case int64:
+				row[i] = json.Number(strconv.FormatInt(x, 10))
+			case time.Time:
+				row[i] = x.Format("2006-01-02T15:04:05.000Z")
+			default:
+				row[i] = v
+			}
+		}
+		return emit()
+	})
+}
+
+// inventorySchema returns the field names of a report.
+func inventorySchema(manifest *inventoryManifest) ([]string, error) {
+	var fields []string
+	switch manifest.FileFormat {
+	case "CSV":
+		for _, f := range strings.Split(manifest.FileSchema, ",") {
+			fields = append(fields, strings.TrimSpace(f))
+		}
+	case "ORC":
+		// The schema is an ORC type such as struct<bucket:string,key:string>.
+		schema, ok := strings.CutPrefix(manifest.FileSchema, "struct<")
+		if !ok {
+			return nil, fmt.Errorf("unexpected ORC schema %q", manifest.FileSchema)
+		}
+		for _, f := range strings.Split(strings.TrimSuffix(schema, ">"), ",") {
+			name, _, _ := strings.Cut(f, ":")
+			fields = append(fields, inventoryFieldName(strings.TrimSpace(name)))
+		}
+	case "Parquet":
+		return nil, errors.New("Parquet reports can't be read; set the inventory --format to csv or orc")
+	default:
+		return nil, fmt.Errorf("unknown report format %q", manifest.FileFormat)
+	}
+	return fields, nil
+}
+
+// newInventoryScan returns a scan of the objects of a report with the given
+// fields that match q. Objects are grouped by groupBy, which is a field,
+// "prefix" for the first depth levels of the key, or "none". An empty
+// groupBy groups by storage class when the report has it.
+func newInventoryScan(fields []string, q *selectQuery, groupBy string, depth int) (*inventoryScan, error) {
+	fieldIndex := func(name string) int {
+		return slices.IndexFunc(fields, func(f string) bool { return strings.EqualFold(f, name) })
+	}
+	scan := &inventoryScan{
+		fields:     fields,
+		query:      q,
+		keyIndex:   fieldIndex("Key"),
+		sizeIndex:  fieldIndex("Size"),
+		groupIndex: -1,
+		groupBy:    groupBy,
+		depth:      depth,
+		groups:     map[string]*inventoryGroup{},
+	}
+	if scan.keyIndex < 0 {
+		return nil, errors.New("the report has no Key field")
+	}
+	switch {
+	case groupBy == "" && fieldIndex("StorageClass") >= 0:
+		scan.groupBy = "StorageClass"
+		scan.groupIndex = fieldIndex("StorageClass")
+	case groupBy == "", strings.EqualFold(groupBy, "none"):
+		scan.groupBy = ""
+	case strings.EqualFold(groupBy, "prefix"):
+		scan.groupBy = "prefix"
+	default:
+		if scan.groupIndex = fieldIndex(groupBy); scan.groupIndex < 0 {
+			return nil, fmt.Errorf("the report has no field %s; it has %s", groupBy, strings.Join(fields, ", "))
+		}
+		scan.groupBy = fields[scan.groupIndex]
+	}
+	return scan, nil
+}
+
+func readInventory(input *inventoryReadCmdInput) {
+	if input.location == "" && (input.name == "" || input.id == "") {
+		log.Fatalf("Either the location of the reports or --name and --id are required")
+	}
+	if input.location != "" && input.name != "" {
+		log.Fatalf("--name can't be used with the location of the reports")
+	}
+	where := ""
+	if input.where != "" {
+		where = " WHERE " + input.where
+	}
+	q, err := parseSelect("SELECT * FROM s3object s" + where)
+	if err != nil {
+		log.Fatalf("Invalid --where: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeoutCause(ctx, time.Duration(input.timeout)*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+	cc := cloud.New(cfg)
+
+	bucket, key, err := inventoryLocation(ctx, cc, input)
+	if err != nil {
+		log.Fatalf("Unable to find the inventory reports: %v", err)
+	}
+	s3client, err := cc.BucketClient(ctx, bucket)
+	if err != nil {
+		log.Fatalf("Unable to locate bucket %s: %v", bucket, err)
+	}
+
+	var manifest *inventoryManifest
+	if strings.HasSuffix(key, "/") {
+		key, manifest, err = latestManifest(ctx, s3client, bucket, key, input.date)
+	} else {
+		manifest, _, err = readManifest(ctx, s3client, bucket, key)
+	}
+	if err != nil {
+		log.Fatalf("Failed to read the inventory manifest: %v", err)
+	}
+
+	fields, err := inventorySchema(manifest)
+	if err != nil {
+		log.Fatalf("Failed to read s3://%s/%s: %v", bucket, key, err)
+	}
+	scan, err := newInventoryScan(fields, q, input.groupBy, input.depth)
+	if err != nil {
+		log.Fatalf("Unable to read the inventory report: %v", err)
+	}
+
+	if input.list {
+		outputFormat := "csv"
+		if jsonOutput(false) {
+			outputFormat = "json"
+		}
+		scan.out = newSelectWriter(os.Stdout, &queryCmdInput{outputFormat: outputFormat, delimiter: ","})
+		if scan.out.csv != nil {
+			scan.out.csv.Write(fields)
+		}
+	}
+
+	// The destination bucket is named by ARN.
+	destination := bucket
+	if parsed, err := arn.Parse(manifest.DestinationBucket); err == nil {
+		destination = parsed.Resource
+	}
+	// The first file that fails stops the others.
+	readCtx, stopReading := context.WithCancel(ctx)
+	defer stopReading()
+	var errOnce sync.Once
+	sem := make(chan struct{}, max(input.concurrency, 1))
+	var wg sync.WaitGroup
+	for _, file := range manifest.Files {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if readErr := scan.readFile(readCtx, s3client, destination, manifest.FileFormat, file); readErr != nil {
+				errOnce.Do(func() {
+					err = readErr
+					stopReading()
+				})
+			}
+		}()
+	}
+	wg.Wait()
+	if scan.out != nil {
+		if flushErr := scan.out.flush(); err == nil {
+			err = flushErr
+		}
+	}
+	if err != nil {
+		log.Fatalf("Failed to read the inventory report: %v", err)
+	}
+	if input.list {
+		return
+	}
+
+	summary := inventorySummary{
+		Manifest:     "s3://" + bucket + "/" + key,
+		SourceBucket: manifest.SourceBucket,
+		Format:       manifest.FileFormat,
+		Files:        len(manifest.Files),
+		Objects:      scan.objects,
+		Size:         scan.size,
+		GroupBy:      scan.groupBy,
+	}
+	if ms, err := strconv.ParseInt(manifest.CreationTimestamp, 10, 64); err == nil {
+		summary.Created = time.UnixMilli(ms).UTC()
+	}
+	for _, g := range scan.groups {
+		summary.Groups = append(summary.Groups, *g)
+	}
+	slices.SortFunc(summary.Groups, func(a, b inventoryGroup) int {
+		return cmp.Or(cmp.Compare(b.Size, a.Size), strings.Compare(a.Group, b.Group))
+	})
+
+	if jsonOutput(false) {
+		jsonData, _ := json.MarshalIndent(summary, "", "  ")
+		fmt.Println(string(jsonData))
+		return
+	}
+	fmt.Printf("Report:   %s\n", summary.Manifest)
+	fmt.Printf("Source:   %s\n", summary.SourceBucket)
+	fmt.Printf("Created:  %s\n", summary.Created.Format(time.RFC3339))
+	fmt.Printf("Files:    %d %s\n", summary.Files, summary.Format)
+	fmt.Printf("Objects:  %d\n", summary.Objects)
+	fmt.Printf("Size:     %s\n", formatBytes(summary.Size))
+	if summary.GroupBy == "" {
+		return
+	}
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\tOBJECTS\tSIZE\n", strings.ToUpper(summary.GroupBy))
+	for _, g := range summary.Groups {
+		group := g.Group
+		if group == "" {
+			group = "-"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\n", group, g.Objects, formatBytes(g.Size))
+	}
+	w.Flush()
+}
+
+func init() {
+	inventoryReadCmd.Flags().String("id", "", "Inventory configuration ID, to find the reports with --name")
+	inventoryReadCmd.Flags().String("date", "", "Read the report of this date (YYYY-MM-DD) instead of the latest")
+	inventoryReadCmd.Flags().String("where", "", "Only count objects matching this S3 Select condition")
+	inventoryReadCmd.Flags().String("group-by", "", "Field to group objects by, prefix, or none (default StorageClass)")
+	inventoryReadCmd.Flags().Int("depth", 1, "Key levels to group by with --group-by prefix")
+	inventoryReadCmd.Flags().Bool("list", false, "List the matching objects instead of summarizing them")
+	inventoryReadCmd.Flags().Int("concurrency", 4, "Number of report files to read at once")
+	inventoryReadCmd.Flags().IntP("timeout", "t", 3600, "Timeout in seconds")
+
+	inventoryCmd.AddCommand(inventoryReadCmd)
+}
@@ -0,0 +1,347 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testReport is a report under testdata/inventory. Both reports list the
+// same five objects, the CSV one in two files and the ORC one in a single
+// file of two stripes.
+type testReport struct {
+	manifest *inventoryManifest
+	fields   []string
+	files    [][]byte
+}
+
+func loadTestReport(t *testing.T, format string) *testReport {
+	t.Helper()
+	dir := filepath.Join("testdata", "inventory", format)
+	data, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := &testReport{manifest: &inventoryManifest{}}
+	if err := json.Unmarshal(data, report.manifest); err != nil {
+		t.Fatal(err)
+	}
+	if report.fields, err = inventorySchema(report.manifest); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range report.manifest.Files {
+		data, err := os.ReadFile(filepath.Join(dir, "data", path.Base(file.Key)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		report.files = append(report.files, data)
+	}
+	return report
+}
+
+// readTestFile reads the data of a file of the report into scan.
+func (r *testReport) readTestFile(scan *inventoryScan, file inventoryFile, data []byte) error {
+	return scan.scanFile(func(row []any, emit func() error) error {
+		if r.manifest.FileFormat == "CSV" {
+			return scan.readCSV(bytes.NewReader(data), file.MD5checksum, row, emit)
+		}
+		return scan.readORC(bytes.NewReader(data), int64(len(data)), row, emit)
+	})
+}
+
+func (r *testReport) scan(t *testing.T, where, groupBy string, depth int) *inventoryScan {
+	t.Helper()
+	if where != "" {
+		where = " WHERE " + where
+	}
+	q, err := parseSelect("SELECT * FROM s3object s" + where)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scan, err := newInventoryScan(r.fields, q, groupBy, depth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, file := range r.manifest.Files {
+		if err := r.readTestFile(scan, file, r.files[i]); err != nil {
+			t.Fatalf("%s: %v", file.Key, err)
+		}
+	}
+	return scan
+}
+
+func TestInventorySchema(t *testing.T) {
+	want := []string{"Bucket", "Key", "Size", "LastModifiedDate", "StorageClass", "IsLatest"}
+	for _, format := range []string{"csv", "orc"} {
+		if got := loadTestReport(t, format).fields; !slices.Equal(got, want) {
+			t.Errorf("%s schema = %q, want %q", format, got, want)
+		}
+	}
+
+	for _, m := range []inventoryManifest{
+		{FileFormat: "Parquet", FileSchema: "message s3.inventory { required binary bucket (UTF8); }"},
+		{FileFormat: "ORC", FileSchema: "bucket:string"},
+		{FileFormat: "Avro"},
+	} {
+		if fields, err := inventorySchema(&m); err == nil {
+			t.Errorf("inventorySchema(%s %q) = %q, want an error", m.FileFormat, m.FileSchema, fields)
+		}
+	}
+}
+
+func TestInventorySummary(t *testing.T) {
+	type group struct{ objects, size int64 }
+	tests := []struct {
+		name    string
+		where   string
+		groupBy string
+		depth   int
+		objects int64
+		size    int64
+		groups  map[string]group
+	}{
+		{
+			name:    "storage class by default",
+			objects: 5,
+			size:    255108,
+			groups: map[string]group{
+				"STANDARD":    {3, 1012},
+				"GLACIER":     {1, 250000},
+				"STANDARD_IA": {1, 4096},
+			},
+		},
+		{
+			name:    "prefix",
+			groupBy: "prefix",
+			depth:   1,
+			objects: 5,
+			size:    255108,
+			groups: map[string]group{
+				"photos/": {2, 251000},
+				"logs/":   {2, 4096},
+				"":        {1, 12},
+			},
+		},
+		{
+			name:    "deeper prefix",
+			groupBy: "prefix",
+			depth:   2,
+			objects: 5,
+			size:    255108,
+			groups: map[string]group{
+				"photos/":      {1, 1000},
+				"photos/2024/": {1, 250000},
+				"logs/":        {1, 4096},
+				"logs/old/":    {1, 0},
+				"":             {1, 12},
+			},
+		},
+		{
+			name:    "another field",
+			groupBy: "islatest",
+			objects: 5,
+			size:    255108,
+			groups: map[string]group{
+				"true":  {4, 255108},
+				"false": {1, 0},
+			},
+		},
+		{
+			name:    "where on numbers and booleans",
+			where:   "s.Size < 5000 AND s.IsLatest = true",
+			groupBy: "none",
+			objects: 3,
+			size:    5108,
+		},
+		{
+			name:    "where on a missing size",
+			where:   "s.Size IS NULL",
+			groupBy: "none",
+			objects: 1,
+		},
+		{
+			name:    "where on dates and decoded keys",
+			where:   "s.LastModifiedDate < '2025-01-03' OR s.Key LIKE '% 1.jpg'",
+			objects: 4,
+			size:    251012,
+			groups: map[string]group{
+				"STANDARD": {3, 1012},
+				"GLACIER":  {1, 250000},
+			},
+		},
+	}
+	for _, format := range []string{"csv", "orc"} {
+		report := loadTestReport(t, format)
+		for _, tt := range tests {
+			t.Run(format+"/"+tt.name, func(t *testing.T) {
+				scan := report.scan(t, tt.where, tt.groupBy, tt.depth)
+				if scan.objects != tt.objects || scan.size != tt.size {
+					t.Errorf("got %d objects of %d bytes, want %d of %d", scan.objects, scan.size, tt.objects, tt.size)
+				}
+				groups := map[string]group{}
+				for name, g := range scan.groups {
+					groups[name] = group{g.Objects, g.Size}
+				}
+				if len(groups) != len(tt.groups) {
+					t.Errorf("groups = %v, want %v", groups, tt.groups)
+				}
+				for name, want := range tt.groups {
+					if groups[name] != want {
+						t.Errorf("group %q = %v, want %v", name, groups[name], want)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestInventoryList(t *testing.T) {
+	want := `{"Bucket":"docs","Key":"photos/cat.jpg","Size":1000,"LastModifiedDate":"2025-01-02T03:04:05.000Z","StorageClass":"STANDARD","IsLatest":true}
+{"Bucket":"docs","Key":"photos/2024/dog 1.jpg","Size":250000,"LastModifiedDate":"2025-01-03T00:00:00.000Z","StorageClass":"GLACIER","IsLatest":true}
+{"Bucket":"docs","Key":"logs/app.log","Size":4096,"LastModifiedDate":"2025-02-01T12:30:00.123Z","StorageClass":"STANDARD_IA","IsLatest":true}
+{"Bucket":"docs","Key":"logs/old/app.log","Size":null,"LastModifiedDate":"2024-12-31T23:59:59.000Z","StorageClass":"STANDARD","IsLatest":false}
+{"Bucket":"docs","Key":"readme.txt","Size":12,"LastModifiedDate":"2025-01-02T03:04:05.000Z","StorageClass":"STANDARD","IsLatest":true}
+`
+	for _, format := range []string{"csv", "orc"} {
+		report := loadTestReport(t, format)
+		q, _ := parseSelect("SELECT * FROM s3object s")
+		scan, err := newInventoryScan(report.fields, q, "", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out bytes.Buffer
+		scan.out = newSelectWriter(&out, &queryCmdInput{outputFormat: "json", delimiter: ","})
+		for i, file := range report.manifest.Files {
+			if err := report.readTestFile(scan, file, report.files[i]); err != nil {
+				t.Fatalf("%s: %v", file.Key, err)
+			}
+		}
+		scan.out.flush()
+		if out.String() != want {
+			t.Errorf("%s listed:\n%s\nwant:\n%s", format, out.String(), want)
+		}
+	}
+}
+
+func TestInventoryTruncated(t *testing.T) {
+	for _, format := range []string{"csv", "orc"} {
+		report := loadTestReport(t, format)
+		q, _ := parseSelect("SELECT * FROM s3object s")
+		file, data := report.manifest.Files[0], report.files[0]
+		for n := range len(data) {
+			scan, err := newInventoryScan(report.fields, q, "", 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := report.readTestFile(scan, file, data[:n]); err == nil {
+				t.Errorf("%s cut to %d of %d bytes: no error", format, n, len(data))
+			}
+			if scan.objects != 0 {
+				t.Errorf("%s cut to %d of %d bytes: counted %d objects", format, n, len(data), scan.objects)
+			}
+		}
+	}
+
+	// A byte missing anywhere in the file, which cuts a stream short when the
+	// footer is intact.
+	report := loadTestReport(t, "orc")
+	q, _ := parseSelect("SELECT * FROM s3object s")
+	data := report.files[0]
+	for n := range len(data) {
+		scan, _ := newInventoryScan(report.fields, q, "", 1)
+		cut := slices.Concat(data[:n], data[n+1:])
+		if err := report.readTestFile(scan, report.manifest.Files[0], cut); err == nil {
+			t.Errorf("orc without byte %d: no error", n)
+		}
+	}
+}
+
+func TestInventoryChecksum(t *testing.T) {
+	report := loadTestReport(t, "csv")
+	q, _ := parseSelect("SELECT * FROM s3object s")
+	scan, err := newInventoryScan(report.fields, q, "", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := report.manifest.Files[0]
+	file.MD5checksum = strings.Repeat("0", 32)
+	if err := report.readTestFile(scan, file, report.files[0]); err == nil || !strings.Contains(err.Error(), "MD5") {
+		t.Errorf("error = %v, want a checksum mismatch", err)
+	}
+}
+
+func TestNewInventoryScan(t *testing.T) {
+	q, _ := parseSelect("SELECT * FROM s3object")
+	tests := []struct {
+		fields  []string
+		groupBy string
+		want    string
+		err     string
+	}{
+		{fields: []string{"Bucket", "Key", "StorageClass"}, want: "StorageClass"},
+		{fields: []string{"Bucket", "Key", "Size"}, want: ""},
+		{fields: []string{"Bucket", "Key", "StorageClass"}, groupBy: "None", want: ""},
+		{fields: []string{"Bucket", "Key"}, groupBy: "Prefix", want: "prefix"},
+		{fields: []string{"Bucket", "Key", "ETag"}, groupBy: "etag", want: "ETag"},
+		{fields: []string{"Bucket", "Key"}, groupBy: "Size", err: "no field Size"},
+		{fields: []string{"Bucket", "Size"}, err: "no Key field"},
+	}
+	for _, tt := range tests {
+		scan, err := newInventoryScan(tt.fields, q, tt.groupBy, 1)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("newInventoryScan(%q, %q) error = %v, want %q", tt.fields, tt.groupBy, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("newInventoryScan(%q, %q): %v", tt.fields, tt.groupBy, err)
+			continue
+		}
+		if scan.groupBy != tt.want {
+			t.Errorf("newInventoryScan(%q, %q) groups by %q, want %q", tt.fields, tt.groupBy, scan.groupBy, tt.want)
+		}
+	}
+}
+
+func TestInventoryFieldName(t *testing.T) {
+	for column, want := range map[string]string{
+		"key":                           "Key",
+		"e_tag":                         "ETag",
+		"last_modified_date":            "LastModifiedDate",
+		"object_lock_retain_until_date": "ObjectLockRetainUntilDate",
+		"version_id":                    "VersionId",
+	} {
+		if got := inventoryFieldName(column); got != want {
+			t.Errorf("inventoryFieldName(%q) = %q, want %q", column, got, want)
+		}
+	}
+}
+
+func TestKeyPrefix(t *testing.T) {
+	tests := []struct {
+		key   string
+		depth int
+		want  string
+	}{
+		{"a/b/c.txt", 1, "a/"},
+		{"a/b/c.txt", 2, "a/b/"},
+		{"a/b/c.txt", 5, "a/b/"},
+		{"c.txt", 1, ""},
+		{"a//c.txt", 2, "a//"},
+		{"a/b/", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := keyPrefix(tt.key, tt.depth); got != tt.want {
+			t.Errorf("keyPrefix(%q, %d) = %q, want %q", tt.key, tt.depth, got, tt.want)
+		}
+	}
+}
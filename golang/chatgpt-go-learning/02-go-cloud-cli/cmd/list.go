@@ -11,9 +11,11 @@ import (
 	"log"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
 )
 
 type listCmdInput struct {
@@ -43,33 +45,60 @@ to quickly create a Cobra application.`,
 
 func listBuckets(input *listCmdInput) {
 	ctx, cancel := context.WithTimeoutCause(context.Background(), time.Duration(input.timeout)*time.Second, errors.New("Timeout"))
+	defer cancel()
 
-	cfg, err := config.LoadDefaultConfig(ctx)
+	cfg, err := loadConfig(ctx)
 	if err != nil {
 		log.Fatalf("Unable to load sdk config: %v", err)
 
 	}
 
-	s3client := s3.NewFromConfig(cfg)
-
-	result, err := s3client.ListBuckets(ctx, &s3.ListBucketsInput{})
+	regions, err := resolveRegions(ctx, cfg)
 	if err != nil {
+		log.Fatalf("Unable to resolve regions: %v", err)
+	}
+
+	// Regions that fail are reported after the buckets of the regions that
+	// succeeded.
+	rows, err := cloud.New(cfg).ListBuckets(ctx, &cloud.ListBucketsOptions{
+		Regions:     regions,
+		Concurrency: regionConcurrency,
+	})
+	var regionsErr *cloud.RegionsError
+	if err != nil && !errors.As(err, &regionsErr) {
 		if errors.Is(err, context.DeadlineExceeded) {
 			log.Fatalf("Request failed: %v", err)
 		}
 		log.Fatalf("Unable to list buckets: %v", err)
 	}
 
-	defer cancel()
-
-	if input.jsonOutput {
-		jsonData, _ := json.MarshalIndent(&result.Buckets, "", "  ")
+	switch {
+	case jsonOutput(input.jsonOutput) && regions != nil:
+		jsonData, _ := json.MarshalIndent(rows, "", "  ")
+		fmt.Println(string(jsonData))
+	case jsonOutput(input.jsonOutput):
+		buckets := make([]types.Bucket, len(rows))
+		for i, row := range rows {
+			buckets[i] = row.Item
+		}
+		jsonData, _ := json.MarshalIndent(buckets, "", "  ")
 		fmt.Println(string(jsonData))
-	} else {
+	default:
 		fmt.Println("Buckets:")
-		for _, bucket := range result.Buckets {
-			fmt.Println(*bucket.Name)
+		for _, row := range rows {
+			if regions != nil {
+				fmt.Printf("%s\t%s\n", row.Region, aws.ToString(row.Item.Name))
+			} else {
+				fmt.Println(aws.ToString(row.Item.Name))
+			}
+		}
+	}
+
+	if regionsErr != nil {
+		for _, e := range regionsErr.Failed {
+			log.Printf("Unable to list buckets in %v", e)
 		}
+		log.Fatalf("%d of %d regions failed", len(regionsErr.Failed), regionsErr.Total)
 	}
 }
 
@@ -0,0 +1,189 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+// logDeliveryService is the principal that writes server access logs.
+const logDeliveryService = "logging.s3.amazonaws.com"
+
+type loggingCmdInput struct {
+	name         string
+	targetBucket string
+	targetPrefix string
+	partitioned  string
+	grant        bool
+	yes          bool
+}
+
+// loggingCmd represents the logging command
+var loggingCmd = &cobra.Command{
+	Use:   "logging",
+	Short: "Manage the server access logging of a bucket",
+}
+
+// loggingGetCmd represents the logging get command
+var loggingGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Show where a bucket writes its access logs",
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		getLogging(&loggingCmdInput{name: name})
+	},
+}
+
+// loggingEnableCmd represents the logging enable command
+var loggingEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Write the access logs of a bucket to another bucket",
+	Long: `Turns on server access logging. S3 writes the logs of the bucket under a prefix
+of the target bucket, which must be in the same region and account, and which
+must let logging.s3.amazonaws.com write to it. --grant adds that permission to
+the policy of the target bucket.
+
+--partitioned event-time or delivery-time writes the logs under date
+partitioned keys, which makes them easier to query with Athena.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		targetBucket, _ := cmd.Flags().GetString("target-bucket")
+		targetPrefix, _ := cmd.Flags().GetString("target-prefix")
+		partitioned, _ := cmd.Flags().GetString("partitioned")
+		grant, _ := cmd.Flags().GetBool("grant")
+		yes, _ := cmd.Flags().GetBool("yes")
+		if !cmd.Flags().Changed("target-prefix") {
+			targetPrefix = name + "/"
+		}
+		enableLogging(&loggingCmdInput{name, targetBucket, targetPrefix, partitioned, grant, yes})
+	},
+}
+
+// loggingDisableCmd represents the logging disable command
+var loggingDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Stop writing the access logs of a bucket",
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		disableLogging(&loggingCmdInput{name: name})
+	},
+}
+
+func getLogging(input *loggingCmdInput) {
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+	cc := cloud.New(cfg)
+
+	config, err := cc.Logging(ctx, input.name)
+	if err != nil {
+		log.Fatalf("Failed to get logging configuration: %v", err)
+	}
+
+	if jsonOutput(false) {
+		jsonData, _ := json.MarshalIndent(config, "", "  ")
+		fmt.Println(string(jsonData))
+		return
+	}
+	if !config.Enabled {
+		fmt.Printf("%s has no access logging\n", input.name)
+		return
+	}
+	fmt.Printf("Target:      s3://%s/%s\n", config.TargetBucket, config.TargetPrefix)
+	if config.Partitioned != "" {
+		fmt.Printf("Partitioned: by %s\n", config.Partitioned)
+	}
+}
+
+func enableLogging(input *loggingCmdInput) {
+	opts := &cloud.EnableLoggingOptions{TargetPrefix: input.targetPrefix}
+	if input.partitioned != "" {
+		source, err := cloud.PartitionDateSource(input.partitioned)
+		if err != nil {
+			log.Fatalf("Invalid --partitioned: %v", err)
+		}
+		opts.Partitioned = source
+	}
+	if input.targetBucket == input.name {
+		log.Printf("Warning: logging %s into itself makes every log delivery write another log record", input.name)
+	}
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 30*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+	cc := cloud.New(cfg)
+
+	// Checked before granting, which would be of no use otherwise.
+	if err := cc.CheckSameRegion(ctx, input.name, input.targetBucket); err != nil {
+		log.Fatalf("Unable to log to %s: %v; logs can only go to a bucket in the same region", input.targetBucket, err)
+	}
+
+	if input.grant {
+		if !input.yes && !confirm("Let %s write the access logs of %s to s3://%s/%s?", logDeliveryService, input.name, input.targetBucket, input.targetPrefix) {
+			log.Fatalf("Aborted")
+		}
+		if _, err := grantDelivery(ctx, cc, "S3ServerAccessLogs", input.targetBucket, input.targetPrefix, input.name, logDeliveryService, nil); err != nil {
+			log.Fatalf("Failed to update the policy of %s: %v", input.targetBucket, err)
+		}
+	} else if ok, err := deliveryAllowed(ctx, cc, input.targetBucket, logDeliveryService); err == nil && !ok {
+		log.Printf("Warning: the policy of %s doesn't let %s write to it, so no logs will arrive; --grant adds it", input.targetBucket, logDeliveryService)
+	}
+
+	if err := cc.EnableLogging(ctx, input.name, input.targetBucket, opts); err != nil {
+		log.Fatalf("Failed to enable logging: %v", err)
+	}
+
+	fmt.Printf("%s now logs to s3://%s/%s\n", input.name, input.targetBucket, input.targetPrefix)
+}
+
+func disableLogging(input *loggingCmdInput) {
+	guardDestructive(input.name)
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+	cc := cloud.New(cfg)
+
+	if err := cc.DisableLogging(ctx, input.name); err != nil {
+		log.Fatalf("Failed to disable logging: %v", err)
+	}
+
+	fmt.Printf("Access logging disabled for %s\n", input.name)
+}
+
+func init() {
+	loggingCmd.PersistentFlags().StringP("name", "n", "", "Bucket Name")
+	loggingCmd.MarkPersistentFlagRequired("name")
+
+	loggingEnableCmd.Flags().String("target-bucket", "", "Bucket to write the logs to")
+	loggingEnableCmd.MarkFlagRequired("target-bucket")
+	loggingEnableCmd.Flags().String("target-prefix", "", "Prefix of the log objects (default <name>/)")
+	loggingEnableCmd.Flags().String("partitioned", "", "Partition log keys by date: event-time or delivery-time")
+	loggingEnableCmd.Flags().Bool("grant", false, "Let the log delivery service write to the target bucket")
+	loggingEnableCmd.Flags().BoolP("yes", "y", false, "Don't ask for confirmation before changing the policy of the target bucket")
+
+	loggingCmd.AddCommand(loggingGetCmd, loggingEnableCmd, loggingDisableCmd)
+	rootCmd.AddCommand(loggingCmd)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+type notificationsCmdInput struct {
+	name   string
+	id     string
+	arn    string
+	events []string
+	prefix string
+	suffix string
+}
+
+// notificationsCmd represents the notifications command
+var notificationsCmd = &cobra.Command{
+	Use:   "notifications",
+	Short: "Manage the event notifications of a bucket",
+}
+
+// notificationsListCmd represents the notifications list command
+var notificationsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the event notifications of a bucket",
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		listNotifications(&notificationsCmdInput{name: name})
+	},
+}
+
+// notificationsAddCmd represents the notifications add command
+var notificationsAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Send bucket events to an SQS queue, SNS topic or Lambda function",
+	Long: `Adds an event notification to a bucket and keeps the existing ones. The target
+type is taken from the ARN. S3 rejects notifications whose event types, prefix
+and suffix all overlap with another one; such overlaps are reported before
+anything is changed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		id, _ := cmd.Flags().GetString("id")
+		targetArn, _ := cmd.Flags().GetString("arn")
+		events, _ := cmd.Flags().GetStringSlice("event")
+		prefix, _ := cmd.Flags().GetString("prefix")
+		suffix, _ := cmd.Flags().GetString("suffix")
+		addNotification(&notificationsCmdInput{name, id, targetArn, events, prefix, suffix})
+	},
+}
+
+// notificationsRemoveCmd represents the notifications remove command
+var notificationsRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove an event notification from a bucket",
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		id, _ := cmd.Flags().GetString("id")
+		removeNotification(&notificationsCmdInput{name: name, id: id})
+	},
+}
+
+func listNotifications(input *notificationsCmdInput) {
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+
+	config, err := cloud.New(cfg).Notifications(ctx, input.name)
+	if err != nil {
+		log.Fatalf("Failed to get notification configuration: %v", err)
+	}
+
+	if jsonOutput(false) {
+		jsonData, _ := json.MarshalIndent(config.Notifications, "", "  ")
+		fmt.Println(string(jsonData))
+		return
+	}
+	if config.EventBridge {
+		fmt.Println("EventBridge: enabled")
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTARGET\tARN\tEVENTS\tPREFIX\tSUFFIX")
+	for _, r := range config.Notifications {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", r.ID, r.Target, r.Arn, strings.Join(r.Events, ","), r.Prefix, r.Suffix)
+	}
+	w.Flush()
+}
+
+func addNotification(input *notificationsCmdInput) {
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+
+	rules, err := cloud.New(cfg).AddNotification(ctx, input.name, cloud.Notification{
+		ID:     input.id,
+		Arn:    input.arn,
+		Events: input.events,
+		Prefix: input.prefix,
+		Suffix: input.suffix,
+	})
+	if err != nil {
+		log.Fatalf("Failed to add notification: %v", err)
+	}
+
+	fmt.Printf("%s now has %d notifications\n", input.name, len(rules))
+}
+
+func removeNotification(input *notificationsCmdInput) {
+	guardDestructive(input.name)
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+
+	rules, err := cloud.New(cfg).RemoveNotification(ctx, input.name, input.id)
+	if err != nil {
+		log.Fatalf("Failed to remove notification: %v", err)
+	}
+
+	fmt.Printf("%s now has %d notifications\n", input.name, len(rules))
+}
+
+func init() {
+	notificationsCmd.PersistentFlags().StringP("name", "n", "", "Bucket Name")
+	notificationsCmd.MarkPersistentFlagRequired("name")
+
+	notificationsAddCmd.Flags().String("id", "", "Id of the notification")
+	notificationsAddCmd.Flags().String("arn", "", "ARN of the SQS queue, SNS topic or Lambda function to notify")
+	notificationsAddCmd.MarkFlagRequired("arn")
+	notificationsAddCmd.Flags().StringSlice("event", nil, "Event type to notify about, e.g. s3:ObjectCreated:* (repeatable)")
+	notificationsAddCmd.Flags().String("prefix", "", "Only notify about keys starting with this prefix")
+	notificationsAddCmd.Flags().String("suffix", "", "Only notify about keys ending with this suffix")
+
+	notificationsRemoveCmd.Flags().String("id", "", "Id of the notification to remove")
+	notificationsRemoveCmd.MarkFlagRequired("id")
+
+	notificationsCmd.AddCommand(notificationsListCmd, notificationsAddCmd, notificationsRemoveCmd)
+	rootCmd.AddCommand(notificationsCmd)
+}
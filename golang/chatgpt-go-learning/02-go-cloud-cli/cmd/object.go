@@ -0,0 +1,381 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+type objectCmdInput struct {
+	uri         string
+	pairs       []string
+	replace     bool
+	metadata    cloud.ObjectMetadata
+	remove      []string
+	checkpoint  string
+	restart     bool
+	yes         bool
+	concurrency int
+	timeout     int
+}
+
+// objectCmd represents the object command
+var objectCmd = &cobra.Command{
+	Use:   "object",
+	Short: "Edit the tags and metadata of objects",
+	Long: `Edits the tags and metadata of an object given as s3://bucket/key, or of every
+object under a prefix given as s3://bucket/prefix/.
+
+Runs over a prefix record the objects they finished in a checkpoint file. If
+some objects fail, running the same command again only retries the objects
+that are left. --restart starts over instead.`,
+}
+
+// objectTagCmd represents the object tag command
+var objectTagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Manage the tags of objects",
+}
+
+// objectTagGetCmd represents the object tag get command
+var objectTagGetCmd = &cobra.Command{
+	Use:   "get s3://bucket/key-or-prefix",
+	Short: "Print the tags of objects",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		input := objectFlags(cmd, args)
+		getObjectTags(input)
+	},
+}
+
+// objectTagSetCmd represents the object tag set command
+var objectTagSetCmd = &cobra.Command{
+	Use:   "set s3://bucket/key-or-prefix k=v...",
+	Short: "Set tags on objects",
+	Long: `Sets the given tags on objects and keeps their other tags. Use --replace to
+drop the tags that aren't given.`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		input := objectFlags(cmd, args)
+		input.replace, _ = cmd.Flags().GetBool("replace")
+		setObjectTags(input)
+	},
+}
+
+// objectTagRmCmd represents the object tag rm command
+var objectTagRmCmd = &cobra.Command{
+	Use:   "rm s3://bucket/key-or-prefix [k...]",
+	Short: "Remove tags from objects",
+	Long:  `Removes the given tags from objects, or every tag if none are given.`,
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		removeObjectTags(objectFlags(cmd, args))
+	},
+}
+
+// objectMetadataCmd represents the object metadata command
+var objectMetadataCmd = &cobra.Command{
+	Use:   "metadata",
+	Short: "Manage the metadata of objects",
+}
+
+// objectMetadataSetCmd represents the object metadata set command
+var objectMetadataSetCmd = &cobra.Command{
+	Use:   "set s3://bucket/key-or-prefix [k=v...]",
+	Short: "Set the metadata of objects",
+	Long: `Sets user metadata given as k=v and the headers given as flags on objects.
+Everything else is kept: other user metadata and headers, the storage class,
+the encryption settings and the tags.
+
+S3 can't edit metadata in place, so each object is copied onto itself. Objects
+larger than 5 GiB can't be copied this way and are reported as failed.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		input := objectFlags(cmd, args)
+		input.metadata.ContentType, _ = cmd.Flags().GetString("content-type")
+		input.metadata.CacheControl, _ = cmd.Flags().GetString("cache-control")
+		input.metadata.ContentDisposition, _ = cmd.Flags().GetString("content-disposition")
+		input.metadata.ContentEncoding, _ = cmd.Flags().GetString("content-encoding")
+		input.metadata.ContentLanguage, _ = cmd.Flags().GetString("content-language")
+		input.remove, _ = cmd.Flags().GetStringSlice("remove")
+		setObjectMetadata(input)
+	},
+}
+
+func objectFlags(cmd *cobra.Command, args []string) *objectCmdInput {
+	checkpoint, _ := cmd.Flags().GetString("checkpoint")
+	restart, _ := cmd.Flags().GetBool("restart")
+	yes, _ := cmd.Flags().GetBool("yes")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	timeout, _ := cmd.Flags().GetInt("timeout")
+	return &objectCmdInput{
+		uri:         args[0],
+		pairs:       args[1:],
+		checkpoint:  checkpoint,
+		restart:     restart,
+		yes:         yes,
+		concurrency: concurrency,
+		timeout:     timeout,
+	}
+}
+
+// objectRun is an object command about to run on one key or on a prefix.
+type objectRun struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	cc     *cloud.Client
+	bucket string
+	keys   []string
+	bulk   bool
+}
+
+// startObjectRun loads the config and resolves the keys of a command. Ctrl-C
+// stops a bulk run after the objects in flight. Commands that rewrite the
+// tags or metadata of objects are destructive and refused on protected
+// buckets before anything is listed.
+func startObjectRun(input *objectCmdInput, destructive bool) *objectRun {
+	bucket, prefix, err := cloud.ParseS3URI(input.uri)
+	if err != nil {
+		log.Fatalf("Invalid location: %v", err)
+	}
+	if destructive {
+		guardDestructive(bucket)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	ctx, cancel := context.WithTimeoutCause(ctx, time.Duration(input.timeout)*time.Second, errors.New("Timeout"))
+	run := &objectRun{ctx: ctx, bucket: bucket, cancel: func() { cancel(); stop() }}
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+	run.cc = cloud.New(cfg)
+
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		run.keys = []string{prefix}
+		return run
+	}
+
+	run.bulk = true
+	objects, err := run.cc.ListObjects(ctx, bucket, prefix, &cloud.ListObjectsOptions{Concurrency: input.concurrency})
+	if err != nil {
+		log.Fatalf("Unable to list %s: %v", input.uri, err)
+	}
+	for _, o := range objects {
+		if key := aws.ToString(o.Key); !strings.HasSuffix(key, "/") {
+			run.keys = append(run.keys, key)
+		}
+	}
+	return run
+}
+
+// apply runs a change on every key. On a prefix it asks for confirmation
+// first, skips the keys a previous run finished and exits with the number of
+// objects left to retry if some failed.
+func (r *objectRun) apply(input *objectCmdInput, what string, id []string, fn func(context.Context, string) error) {
+	defer r.cancel()
+
+	if !r.bulk {
+		if err := fn(r.ctx, r.keys[0]); err != nil {
+			log.Fatalf("Failed to %s: %v", what, err)
+		}
+		return
+	}
+
+	path := input.checkpoint
+	if path == "" {
+		path = checkpointPath(append([]string{what, input.uri}, id...)...)
+	}
+	cp, err := openCheckpoint(path, input.restart)
+	if err != nil {
+		log.Fatalf("Unable to open checkpoint: %v", err)
+	}
+
+	todo := cp.remaining(r.keys)
+	if done := len(r.keys) - len(todo); done > 0 {
+		log.Printf("Resuming from %s: %d of %d objects already done", path, done, len(r.keys))
+	}
+	if len(todo) > 0 && !input.yes && !confirm("%s on %d objects under %s?", strings.ToUpper(what[:1])+what[1:], len(todo), input.uri) {
+		cp.close(false)
+		log.Fatalf("Aborted")
+	}
+
+	runBulk(r.ctx, todo, input.concurrency, cp, fn)
+	left := len(cp.remaining(r.keys))
+	if err := cp.close(left == 0); err != nil {
+		log.Printf("Warning: unable to update checkpoint %s: %v", path, err)
+	}
+	if left > 0 {
+		if r.ctx.Err() != nil {
+			log.Printf("Stopped: %v", context.Cause(r.ctx))
+		}
+		log.Fatalf("%d of %d objects failed or were not reached; run the same command again to resume", left, len(r.keys))
+	}
+	log.Printf("Done: %s on %d objects", what, len(todo))
+}
+
+func getObjectTags(input *objectCmdInput) {
+	run := startObjectRun(input, false)
+	defer run.cancel()
+
+	var mu sync.Mutex
+	tags := map[string]map[string]string{}
+	failed := runBulk(run.ctx, run.keys, input.concurrency, nil, func(ctx context.Context, key string) error {
+		t, err := run.cc.ObjectTags(ctx, run.bucket, key)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		tags[key] = t
+		mu.Unlock()
+		return nil
+	})
+
+	switch {
+	case jsonOutput(false):
+		var out any = tags
+		if !run.bulk {
+			out = tags[run.keys[0]]
+		}
+		jsonData, _ := json.MarshalIndent(out, "", "  ")
+		fmt.Println(string(jsonData))
+	case !run.bulk:
+		t := tags[run.keys[0]]
+		for _, k := range slices.Sorted(maps.Keys(t)) {
+			fmt.Printf("%s=%s\n", k, t[k])
+		}
+	default:
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KEY\tTAGS")
+		for _, key := range run.keys {
+			t, ok := tags[key]
+			if !ok {
+				continue
+			}
+			var pairs []string
+			for _, k := range slices.Sorted(maps.Keys(t)) {
+				pairs = append(pairs, k+"="+t[k])
+			}
+			fmt.Fprintf(w, "%s\t%s\n", key, strings.Join(pairs, ","))
+		}
+		w.Flush()
+	}
+
+	if failed > 0 {
+		log.Fatalf("Failed to get the tags of %d of %d objects", failed, len(run.keys))
+	}
+}
+
+func setObjectTags(input *objectCmdInput) {
+	tags, err := parseTags(input.pairs)
+	if err != nil {
+		log.Fatalf("Invalid tags: %v", err)
+	}
+	if len(tags) > cloud.MaxObjectTags {
+		log.Fatalf("Invalid tags: an object can have at most %d tags", cloud.MaxObjectTags)
+	}
+
+	run := startObjectRun(input, true)
+	id := append(slices.Sorted(slices.Values(input.pairs)), fmt.Sprint(input.replace))
+	run.apply(input, "set tags", id, func(ctx context.Context, key string) error {
+		result, err := run.cc.TagObject(ctx, run.bucket, key, tags, &cloud.TagObjectOptions{Replace: input.replace})
+		if err == nil && !run.bulk {
+			fmt.Printf("%s now has %d tags\n", key, len(result))
+		}
+		return err
+	})
+}
+
+func removeObjectTags(input *objectCmdInput) {
+	var keys []string
+	for _, t := range input.pairs {
+		// Accept k=v as well, so that a set command line can be reused.
+		k, _, _ := strings.Cut(t, "=")
+		keys = append(keys, k)
+	}
+
+	run := startObjectRun(input, true)
+	what := "remove tags"
+	if len(keys) == 0 {
+		what = "remove all tags"
+	}
+	run.apply(input, what, slices.Sorted(slices.Values(keys)), func(ctx context.Context, key string) error {
+		if len(keys) == 0 {
+			return run.cc.PutObjectTags(ctx, run.bucket, key, nil)
+		}
+		result, err := run.cc.UntagObject(ctx, run.bucket, key, keys)
+		if err == nil && !run.bulk {
+			fmt.Printf("%s now has %d tags\n", key, len(result))
+		}
+		return err
+	})
+}
+
+func setObjectMetadata(input *objectCmdInput) {
+	metadata, err := parseTags(input.pairs)
+	if err != nil {
+		log.Fatalf("Invalid metadata: %v", err)
+	}
+	input.metadata.Metadata = metadata
+	md := input.metadata
+	if md.ContentType+md.CacheControl+md.ContentDisposition+md.ContentEncoding+md.ContentLanguage == "" && len(metadata) == 0 && len(input.remove) == 0 {
+		log.Fatalf("Nothing to set: give k=v pairs, header flags or --remove")
+	}
+
+	run := startObjectRun(input, true)
+	id := append(slices.Sorted(slices.Values(input.pairs)),
+		md.ContentType, md.CacheControl, md.ContentDisposition, md.ContentEncoding, md.ContentLanguage,
+		strings.Join(input.remove, ","))
+	run.apply(input, "set metadata", id, func(ctx context.Context, key string) error {
+		result, err := run.cc.SetObjectMetadata(ctx, run.bucket, key, md, &cloud.SetObjectMetadataOptions{Remove: input.remove})
+		if err == nil && !run.bulk {
+			jsonData, _ := json.MarshalIndent(result, "", "  ")
+			fmt.Println(string(jsonData))
+		}
+		return err
+	})
+}
+
+func init() {
+	for _, c := range []*cobra.Command{objectTagSetCmd, objectTagRmCmd, objectMetadataSetCmd} {
+		c.Flags().String("checkpoint", "", "Checkpoint file of a run over a prefix (default derived from the command)")
+		c.Flags().Bool("restart", false, "Ignore the checkpoint of a previous run and start over")
+		c.Flags().BoolP("yes", "y", false, "Don't ask for confirmation before changing a prefix")
+	}
+	for _, c := range []*cobra.Command{objectTagGetCmd, objectTagSetCmd, objectTagRmCmd, objectMetadataSetCmd} {
+		c.Flags().Int("concurrency", 8, "Number of objects handled at once")
+		c.Flags().IntP("timeout", "t", 3600, "Timeout in seconds")
+	}
+	objectTagSetCmd.Flags().Bool("replace", false, "Replace all existing tags instead of merging with them")
+
+	objectMetadataSetCmd.Flags().String("content-type", "", "Set the Content-Type header")
+	objectMetadataSetCmd.Flags().String("cache-control", "", "Set the Cache-Control header")
+	objectMetadataSetCmd.Flags().String("content-disposition", "", "Set the Content-Disposition header")
+	objectMetadataSetCmd.Flags().String("content-encoding", "", "Set the Content-Encoding header")
+	objectMetadataSetCmd.Flags().String("content-language", "", "Set the Content-Language header")
+	objectMetadataSetCmd.Flags().StringSlice("remove", nil, "Remove user metadata keys (repeatable)")
+
+	objectTagCmd.AddCommand(objectTagGetCmd, objectTagSetCmd, objectTagRmCmd)
+	objectMetadataCmd.AddCommand(objectMetadataSetCmd)
+	objectCmd.AddCommand(objectTagCmd, objectMetadataCmd)
+	rootCmd.AddCommand(objectCmd)
+}
@@ -0,0 +1,954 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+)
+
+// This is a reader for the ORC files S3 Inventory writes, which is enough of
+// the format for flat tables of primitive columns: it reads every stripe of
+// the top level struct, decoding booleans, integers, floats, strings, dates
+// and timestamps. Nested and decimal columns read as nil.
+//
+// The format is described at https://orc.apache.org/specification/ORCv1/.
+
+// ORC compression kinds.
+const (
+	orcNone = iota
+	orcZlib
+	orcSnappy
+	orcLzo
+	orcLz4
+	orcZstd
+)
+
+// ORC column type kinds.
+const (
+	orcBoolean = iota
+	orcByte
+	orcShort
+	orcInt
+	orcLong
+	orcFloat
+	orcDouble
+	orcString
+	orcBinary
+	orcTimestamp
+	orcList
+	orcMap
+	orcStruct
+	orcUnion
+	orcDecimal
+	orcDate
+	orcVarchar
+	orcChar
+	orcTimestampInstant
+)
+
+// ORC stream kinds.
+const (
+	orcPresent        = 0
+	orcData           = 1
+	orcLength         = 2
+	orcDictionaryData = 3
+	orcSecondary      = 5
+)
+
+// ORC column encoding kinds.
+const (
+	orcDirect = iota
+	orcDictionary
+	orcDirectV2
+	orcDictionaryV2
+)
+
+// orcTimestampBase is when timestamps count their seconds from.
+var orcTimestampBase = time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type orcType struct {
+	kind     uint64
+	subtypes []uint64
+	names    []string
+}
+
+type orcStripe struct {
+	offset, indexLength, dataLength, footerLength, rows uint64
+}
+
+// orcFile is an open ORC file.
+type orcFile struct {
+	r           io.ReaderAt
+	compression uint64
+	stripes     []orcStripe
+	types       []orcType
+	rows        uint64
+	zstd        *zstd.Decoder
+}
+
+// protoField is a field of a protobuf message: a varint or fixed value, or
+// the bytes of a length delimited one.
+type protoField struct {
+	number int
+	value  uint64
+	data   []byte
+}
+
+// protoFields splits a protobuf message into its fields.
+func protoFields(b []byte) ([]protoField, error) {
+	var fields []protoField
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, errors.New("corrupt protobuf")
+		}
+		b = b[n:]
+		f := protoField{number: int(key >> 3)}
+		switch key & 7 {
+		case 0:
+			f.value, n = binary.Uvarint(b)
+			if n <= 0 {
+				return nil, errors.New("corrupt protobuf")
+			}
+			b = b[n:]
+		case 1, 5:
+			size := 8
+			if key&7 == 5 {
+				size = 4
+			}
+			if len(b) < size {
+				return nil, errors.New("corrupt protobuf")
+			}
+			f.data, b = b[:size], b[size:]
+		case 2:
+			length, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < length {
+				return nil, errors.New("corrupt protobuf")
+			}
+			f.data, b = b[n:n+int(length)], b[n+int(length):]
+		default:
+			return nil, fmt.Errorf("unsupported protobuf wire type %d", key&7)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// protoVarints returns the values of a repeated varint field, which may be
+// packed.
+func protoVarints(f protoField) []uint64 {
+	if f.data == nil {
+		return []uint64{f.value}
+	}
+	var values []uint64
+	for b := f.data; len(b) > 0; {
+		v, n := binary.Uvarint(b)
+		if n <= 0 {
+			break
+		}
+		values = append(values, v)
+		b = b[n:]
+	}
+	return values
+}
+
+// openORC reads the footer of an ORC file of the given size.
+func openORC(r io.ReaderAt, size int64) (*orcFile, error) {
+	if size < 4 {
+		return nil, errors.New("not an ORC file")
+	}
+	tailSize := min(size, 16*1024)
+	tail := make([]byte, tailSize)
+	if _, err := r.ReadAt(tail, size-tailSize); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	psLength := int64(tail[len(tail)-1])
+	if psLength+1 > tailSize {
+		return nil, errors.New("not an ORC file")
+	}
+	ps, err := protoFields(tail[tailSize-1-psLength : tailSize-1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid postscript: %w", err)
+	}
+	f := &orcFile{r: r}
+	var footerLength int64
+	for _, field := range ps {
+		switch field.number {
+		case 1:
+			footerLength = int64(field.value)
+		case 2:
+			f.compression = field.value
+		case 8000:
+			if string(field.data) != "ORC" {
+				return nil, errors.New("not an ORC file")
+			}
+		}
+	}
+
+	footerEnd := size - 1 - psLength
+	if footerLength > footerEnd {
+		return nil, errors.New("invalid footer length")
+	}
+	var footer []byte
+	if start := tailSize - 1 - psLength - footerLength; start >= 0 {
+		footer = tail[start : tailSize-1-psLength]
+	} else {
+		footer = make([]byte, footerLength)
+		if _, err := r.ReadAt(footer, footerEnd-footerLength); err != nil && err != io.EOF {
+			return nil, err
+		}
+	}
+	if footer, err = f.decompress(footer); err != nil {
+		return nil, fmt.Errorf("invalid footer: %w", err)
+	}
+	fields, err := protoFields(footer)
+	if err != nil {
+		return nil, fmt.Errorf("invalid footer: %w", err)
+	}
+	for _, field := range fields {
+		switch field.number {
+		case 3:
+			stripe, err := protoFields(field.data)
+			if err != nil {
+				return nil, fmt.Errorf("invalid stripe information: %w", err)
+			}
+			var s orcStripe
+			for _, sf := range stripe {
+				switch sf.number {
+				case 1:
+					s.offset = sf.value
+				case 2:
+					s.indexLength = sf.value
+				case 3:
+					s.dataLength = sf.value
+				case 4:
+					s.footerLength = sf.value
+				case 5:
+					s.rows = sf.value
+				}
+			}
+			f.stripes = append(f.stripes, s)
+		case 4:
+			typ, err := protoFields(field.data)
+			if err != nil {
+				return nil, fmt.Errorf("invalid type: %w", err)
+			}
+			var t orcType
+			for _, tf := range typ {
+				switch tf.number {
+				case 1:
+					t.kind = tf.value
+				case 2:
+					t.subtypes = append(t.subtypes, protoVarints(tf)...)
+				case 3:
+					t.names = append(t.names, string(tf.data))
+				}
+			}
+			f.types = append(f.types, t)
+		case 6:
+			f.rows = field.value
+		}
+	}
+	if len(f.types) == 0 || f.types[0].kind != orcStruct {
+		return nil, errors.New("the ORC file has no top level struct")
+	}
+	return f, nil
+}
+
+// close releases the decoder of zstd compressed files.
+func (f *orcFile) close() {
+	if f.zstd != nil {
+		f.zstd.Close()
+	}
+}
+
+// columns returns the names of the top level columns.
+func (f *orcFile) columns() []string {
+	return f.types[0].names
+}
+
+// decompress undoes the compression of a stream, which is split into chunks
+// that are each compressed or stored as is.
+func (f *orcFile) decompress(b []byte) ([]byte, error) {
+	if f.compression == orcNone {
+		return b, nil
+	}
+	var out []byte
+	for len(b) > 0 {
+		if len(b) < 3 {
+			return nil, errors.New("truncated compression chunk")
+		}
+		header := uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16
+		length, original := int(header>>1), header&1 == 1
+		if len(b)-3 < length {
+			return nil, errors.New("truncated compression chunk")
+		}
+		chunk := b[3 : 3+length]
+		b = b[3+length:]
+		if original {
+			out = append(out, chunk...)
+			continue
+		}
+		switch f.compression {
+		case orcZlib:
+			data, err := io.ReadAll(flate.NewReader(bytes.NewReader(chunk)))
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, data...)
+		case orcSnappy:
+			data, err := s2.Decode(nil, chunk)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, data...)
+		case orcZstd:
+			if f.zstd == nil {
+				d, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+				if err != nil {
+					return nil, err
+				}
+				f.zstd = d
+			}
+			var err error
+			if out, err = f.zstd.DecodeAll(chunk, out); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unsupported ORC compression %d", f.compression)
+		}
+	}
+	return out, nil
+}
+
+// orcStripeData holds the decompressed streams of a stripe.
+type orcStripeData struct {
+	rows      int
+	streams   map[[2]uint64][]byte
+	encodings []uint64
+	dictSizes []uint64
+	location  *time.Location
+}
+
+func (s *orcStripeData) stream(column, kind uint64) []byte {
+	return s.streams[[2]uint64{column, kind}]
+}
+
+func (f *orcFile) readStripe(s orcStripe, wanted map[uint64]bool) (*orcStripeData, error) {
+	length := s.indexLength + s.dataLength + s.footerLength
+	raw := make([]byte, length)
+	if _, err := f.r.ReadAt(raw, int64(s.offset)); err != nil && err != io.EOF {
+		return nil, err
+	}
+	footer, err := f.decompress(raw[s.indexLength+s.dataLength:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid stripe footer: %w", err)
+	}
+	fields, err := protoFields(footer)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stripe footer: %w", err)
+	}
+
+	data := &orcStripeData{rows: int(s.rows), streams: map[[2]uint64][]byte{}, location: time.UTC}
+	offset := uint64(0)
+	for _, field := range fields {
+		switch field.number {
+		case 1:
+			stream, err := protoFields(field.data)
+			if err != nil {
+				return nil, fmt.Errorf("invalid stream: %w", err)
+			}
+			var kind, column, length uint64
+			for _, sf := range stream {
+				switch sf.number {
+				case 1:
+					kind = sf.value
+				case 2:
+					column = sf.value
+				case 3:
+					length = sf.value
+				}
+			}
+			if offset+length > s.indexLength+s.dataLength {
+				return nil, errors.New("stream past the end of the stripe")
+			}
+			if wanted[column] {
+				b, err := f.decompress(raw[offset : offset+length])
+				if err != nil {
+					return nil, fmt.Errorf("column %d: %w", column, err)
+				}
+				data.streams[[2]uint64{column, kind}] = b
+			}
+			offset += length
+		case 2:
+			encoding, err := protoFields(field.data)
+			if err != nil {
+				return nil, fmt.Errorf("invalid column encoding: %w", err)
+			}
+			var kind, dictSize uint64
+			for _, ef := range encoding {
+				switch ef.number {
+				case 1:
+					kind = ef.value
+				case 2:
+					dictSize = ef.value
+				}
+			}
+			data.encodings = append(data.encodings, kind)
+			data.dictSizes = append(data.dictSizes, dictSize)
+		case 3:
+			if loc, err := time.LoadLocation(string(field.data)); err == nil {
+				data.location = loc
+			}
+		}
+	}
+	return data, nil
+}
+
+// readRows calls fn with every row of the file, holding the values of the
+// top level columns. The row is reused between calls.
+func (f *orcFile) readRows(fn func(row []any) error) error {
+	root := f.types[0]
+	wanted := map[uint64]bool{}
+	for _, c := range root.subtypes {
+		wanted[c] = true
+	}
+
+	row := make([]any, len(root.subtypes))
+	for _, s := range f.stripes {
+		data, err := f.readStripe(s, wanted)
+		if err != nil {
+			return err
+		}
+		columns := make([][]any, len(root.subtypes))
+		for i, c := range root.subtypes {
+			if columns[i], err = f.readColumn(data, c); err != nil {
+				name := fmt.Sprint(c)
+				if i < len(root.names) {
+					name = root.names[i]
+				}
+				return fmt.Errorf("column %s: %w", name, err)
+			}
+		}
+		for r := 0; r < data.rows; r++ {
+			for i := range columns {
+				row[i] = columns[i][r]
+			}
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readColumn decodes the values of a column in a stripe, nil where they are
+// null.
+func (f *orcFile) readColumn(s *orcStripeData, column uint64) ([]any, error) {
+	if column >= uint64(len(f.types)) || column >= uint64(len(s.encodings)) {
+		return nil, errors.New("column out of range")
+	}
+	values := make([]any, s.rows)
+	count := s.rows
+	var present []bool
+	if b := s.stream(column, orcPresent); b != nil {
+		var err error
+		if present, err = orcBooleans(b, s.rows); err != nil {
+			return nil, err
+		}
+		count = 0
+		for _, p := range present {
+			if p {
+				count++
+			}
+		}
+	}
+
+	decoded, err := f.readValues(s, column, count)
+	if err != nil {
+		return nil, err
+	}
+	if decoded == nil {
+		return values, nil
+	}
+	for i, j := 0, 0; i < s.rows; i++ {
+		if present == nil || present[i] {
+			values[i] = decoded[j]
+			j++
+		}
+	}
+	return values, nil
+}
+
+// readValues decodes count non-null values of a column, or returns nil for
+// types it doesn't read.
+func (f *orcFile) readValues(s *orcStripeData, column uint64, count int) ([]any, error) {
+	encoding := s.encodings[column]
+	v2 := encoding == orcDirectV2 || encoding == orcDictionaryV2
+	ints := func(kind uint64, signed bool, n int) ([]int64, error) {
+		return orcIntegers(s.stream(column, kind), n, signed, v2)
+	}
+	values := make([]any, count)
+
+	switch f.types[column].kind {
+	case orcBoolean:
+		b, err := orcBooleans(s.stream(column, orcData), count)
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range b {
+			values[i] = v
+		}
+	case orcByte:
+		b, err := orcBytes(s.stream(column, orcData), count)
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range b {
+			values[i] = int64(int8(v))
+		}
+	case orcShort, orcInt, orcLong:
+		n, err := ints(orcData, true, count)
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range n {
+			values[i] = v
+		}
+	case orcFloat, orcDouble:
+		size := 8
+		if f.types[column].kind == orcFloat {
+			size = 4
+		}
+		b := s.stream(column, orcData)
+		if len(b) < size*count {
+			return nil, errors.New("truncated data")
+		}
+		for i := range values {
+			if size == 4 {
+				values[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(b[i*4:])))
+			} else {
+				values[i] = math.Float64frombits(binary.LittleEndian.Uint64(b[i*8:]))
+			}
+		}
+	case orcString, orcVarchar, orcChar, orcBinary:
+		if encoding == orcDictionary || encoding == orcDictionaryV2 {
+			indices, err := ints(orcData, false, count)
+			if err != nil {
+				return nil, err
+			}
+			dict, err := orcStrings(s.stream(column, orcDictionaryData), s.stream(column, orcLength), int(s.dictSizes[column]), v2)
+			if err != nil {
+				return nil, err
+			}
+			for i, index := range indices {
+				if index < 0 || index >= int64(len(dict)) {
+					return nil, errors.New("dictionary index out of range")
+				}
+				values[i] = dict[index]
+			}
+		} else {
+			strs, err := orcStrings(s.stream(column, orcData), s.stream(column, orcLength), count, v2)
+			if err != nil {
+				return nil, err
+			}
+			for i, v := range strs {
+				values[i] = v
+			}
+		}
+	case orcDate:
+		days, err := ints(orcData, true, count)
+		if err != nil {
+			return nil, err
+		}
+		for i, d := range days {
+			values[i] = time.Unix(d*86400, 0).UTC()
+		}
+	case orcTimestamp, orcTimestampInstant:
+		seconds, err := ints(orcData, true, count)
+		if err != nil {
+			return nil, err
+		}
+		nanos, err := ints(orcSecondary, false, count)
+		if err != nil {
+			return nil, err
+		}
+		base := orcTimestampBase.Unix()
+		if f.types[column].kind == orcTimestamp {
+			t := orcTimestampBase
+			base = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, s.location).Unix()
+		}
+		for i := range values {
+			sec, nano := seconds[i], nanos[i]
+			// Nanoseconds drop their trailing zeros, keeping their count
+			// less one in the low 3 bits.
+			zeros := nano & 7
+			nano >>= 3
+			if zeros != 0 {
+				for range zeros + 1 {
+					nano *= 10
+				}
+			}
+			if sec < 0 && nano > 999999 {
+				sec--
+			}
+			values[i] = time.Unix(base+sec, nano).UTC()
+		}
+	default:
+		return nil, nil
+	}
+	return values, nil
+}
+
+// orcStrings splits string data by a stream of lengths.
+func orcStrings(data, lengths []byte, count int, v2 bool) ([]string, error) {
+	n, err := orcIntegers(lengths, count, false, v2)
+	if err != nil {
+		return nil, err
+	}
+	strs := make([]string, count)
+	for i, length := range n {
+		if length < 0 || length > int64(len(data)) {
+			return nil, errors.New("truncated string data")
+		}
+		strs[i], data = string(data[:length]), data[length:]
+	}
+	return strs, nil
+}
+
+// orcBytes decodes a byte run length encoded stream.
+func orcBytes(b []byte, count int) ([]byte, error) {
+	out := make([]byte, 0, count)
+	for len(out) < count {
+		if len(b) == 0 {
+			return nil, errors.New("truncated byte stream")
+		}
+		header := int8(b[0])
+		if header >= 0 {
+			if len(b) < 2 {
+				return nil, errors.New("truncated byte stream")
+			}
+			for range int(header) + 3 {
+				out = append(out, b[1])
+			}
+			b = b[2:]
+		} else {
+			n := -int(header)
+			if len(b) < 1+n {
+				return nil, errors.New("truncated byte stream")
+			}
+			out = append(out, b[1:1+n]...)
+			b = b[1+n:]
+		}
+	}
+	return out[:count], nil
+}
+
+// orcBooleans decodes a boolean stream, a byte stream of bits with the most
+// significant first.
+func orcBooleans(b []byte, count int) ([]bool, error) {
+	bits, err := orcBytes(b, (count+7)/8)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]bool, count)
+	for i := range out {
+		out[i] = bits[i/8]>>(7-i%8)&1 == 1
+	}
+	return out, nil
+}
+
+func zigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+// orcVarint reads a base 128 varint, zigzag decoded when signed.
+func orcVarint(b []byte, signed bool) (int64, []byte, error) {
+	v, n := binary.Uvarint(b)
+	if n <= 0 {
+		return 0, nil, errors.New("truncated varint")
+	}
+	if signed {
+		return zigzag(v), b[n:], nil
+	}
+	return int64(v), b[n:], nil
+}
+
+// orcIntegers decodes an integer run length encoded stream of either
+// version.
+func orcIntegers(b []byte, count int, signed, v2 bool) ([]int64, error) {
+	out := make([]int64, 0, count)
+	var err error
+	for len(out) < count {
+		if len(b) == 0 {
+			return nil, errors.New("truncated integer stream")
+		}
+		if v2 {
+			out, b, err = orcIntegerRunV2(b, out, signed)
+		} else {
+			out, b, err = orcIntegerRunV1(b, out, signed)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out[:count], nil
+}
+
+func orcIntegerRunV1(b []byte, out []int64, signed bool) ([]int64, []byte, error) {
+	header := int8(b[0])
+	b = b[1:]
+	if header >= 0 {
+		if len(b) == 0 {
+			return nil, nil, errors.New("truncated integer run")
+		}
+		delta := int64(int8(b[0]))
+		base, rest, err := orcVarint(b[1:], signed)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := range int64(header) + 3 {
+			out = append(out, base+i*delta)
+		}
+		return out, rest, nil
+	}
+	for range -int(header) {
+		var v int64
+		var err error
+		if v, b, err = orcVarint(b, signed); err != nil {
+			return nil, nil, err
+		}
+		out = append(out, v)
+	}
+	return out, b, nil
+}
+
+// orcBitWidth decodes the 5 bit width of integer run length encoding v2.
+func orcBitWidth(code byte) int {
+	if code < 24 {
+		return int(code) + 1
+	}
+	return [...]int{26, 28, 30, 32, 40, 48, 56, 64}[code-24]
+}
+
+// orcClosestFixedBits rounds a width up to one bit packing supports.
+func orcClosestFixedBits(n int) int {
+	switch {
+	case n == 0:
+		return 1
+	case n <= 24:
+		return n
+	case n <= 26:
+		return 26
+	case n <= 28:
+		return 28
+	case n <= 30:
+		return 30
+	case n <= 32:
+		return 32
+	case n <= 40:
+		return 40
+	case n <= 48:
+		return 48
+	case n <= 56:
+		return 56
+	}
+	return 64
+}
+
+// orcUnpack reads count big endian values of width bits, starting on a byte
+// boundary.
+func orcUnpack(b []byte, count, width int) ([]uint64, []byte, error) {
+	bits := count * width
+	size := (bits + 7) / 8
+	if len(b) < size {
+		return nil, nil, errors.New("truncated bit packed values")
+	}
+	values := make([]uint64, count)
+	pos := 0
+	for i := range values {
+		var v uint64
+		for remaining := width; remaining > 0; {
+			current := b[pos/8]
+			available := 8 - pos%8
+			take := min(available, remaining)
+			chunk := uint64(current>>(available-take)) & (1<<take - 1)
+			v = v<<take | chunk
+			pos += take
+			remaining -= take
+		}
+		values[i] = v
+	}
+	return values, b[size:], nil
+}
+
+func orcIntegerRunV2(b []byte, out []int64, signed bool) ([]int64, []byte, error) {
+	header := b[0]
+	decode := func(v uint64) int64 {
+		if signed {
+			return zigzag(v)
+		}
+		return int64(v)
+	}
+
+	switch header >> 6 {
+	case 0: // short repeat
+		width := int(header>>3&7) + 1
+		count := int(header&7) + 3
+		if len(b) < 1+width {
+			return nil, nil, errors.New("truncated integer run")
+		}
+		var v uint64
+		for _, c := range b[1 : 1+width] {
+			v = v<<8 | uint64(c)
+		}
+		for range count {
+			out = append(out, decode(v))
+		}
+		return out, b[1+width:], nil
+
+	case 1: // direct
+		if len(b) < 2 {
+			return nil, nil, errors.New("truncated integer run")
+		}
+		width := orcBitWidth(header >> 1 & 0x1f)
+		count := (int(header&1)<<8 | int(b[1])) + 1
+		values, rest, err := orcUnpack(b[2:], count, width)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, v := range values {
+			out = append(out, decode(v))
+		}
+		return out, rest, nil
+
+	case 2: // patched base
+		if len(b) < 4 {
+			return nil, nil, errors.New("truncated integer run")
+		}
+		width := orcBitWidth(header >> 1 & 0x1f)
+		count := (int(header&1)<<8 | int(b[1])) + 1
+		baseWidth := int(b[2]>>5&7) + 1
+		patchWidth := orcBitWidth(b[2] & 0x1f)
+		patchGapWidth := int(b[3]>>5&7) + 1
+		patchCount := int(b[3] & 0x1f)
+		b = b[4:]
+		if len(b) < baseWidth {
+			return nil, nil, errors.New("truncated integer run")
+		}
+		var base int64
+		for _, c := range b[:baseWidth] {
+			base = base<<8 | int64(c)
+		}
+		b = b[baseWidth:]
+		// The base is stored as sign and magnitude.
+		if mask := int64(1) << (baseWidth*8 - 1); base&mask != 0 {
+			base = -(base &^ mask)
+		}
+
+		values, rest, err := orcUnpack(b, count, width)
+		if err != nil {
+			return nil, nil, err
+		}
+		patchBits := orcClosestFixedBits(patchWidth + patchGapWidth)
+		if patchBits > 64 {
+			return nil, nil, errors.New("invalid patch width")
+		}
+		patches, rest, err := orcUnpack(rest, patchCount, patchBits)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Patches hold the high bits of values that didn't fit the width, and
+		// the gap to the previous patch. Gaps over 255 take several entries.
+		patchMask := uint64(1)<<patchWidth - 1
+		next := 0
+		nextPatch := func() (int, uint64) {
+			gap := 0
+			for next < len(patches) {
+				g, p := int(patches[next]>>patchWidth), patches[next]&patchMask
+				next++
+				gap += g
+				if g != 255 || p != 0 {
+					return gap, p
+				}
+			}
+			return -1, 0
+		}
+		at, patch := -1, uint64(0)
+		if patchCount > 0 {
+			at, patch = nextPatch()
+		}
+		for i, v := range values {
+			if i == at {
+				v |= patch << width
+				gap, p := -1, uint64(0)
+				if next < len(patches) {
+					gap, p = nextPatch()
+				}
+				if gap >= 0 {
+					at, patch = i+gap, p
+				} else {
+					at = -1
+				}
+			}
+			out = append(out, base+int64(v))
+		}
+		return out, rest, nil
+
+	default: // delta
+		if len(b) < 2 {
+			return nil, nil, errors.New("truncated integer run")
+		}
+		width := 0
+		if code := header >> 1 & 0x1f; code != 0 {
+			width = orcBitWidth(code)
+		}
+		count := (int(header&1)<<8 | int(b[1])) + 1
+		base, rest, err := orcVarint(b[2:], signed)
+		if err != nil {
+			return nil, nil, err
+		}
+		delta, rest, err := orcVarint(rest, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		out = append(out, base)
+		if width == 0 {
+			for i := 1; i < count; i++ {
+				out = append(out, base+int64(i)*delta)
+			}
+			return out, rest, nil
+		}
+		if count == 1 {
+			return out, rest, nil
+		}
+		previous := base + delta
+		out = append(out, previous)
+		deltas, rest, err := orcUnpack(rest, count-2, width)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, d := range deltas {
+			if delta < 0 {
+				previous -= int64(d)
+			} else {
+				previous += int64(d)
+			}
+			out = append(out, previous)
+		}
+		return out, rest, nil
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"slices"
+	"testing"
+)
+
+// The encoded runs are the examples of the ORC specification.
+
+func TestORCIntegers(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   []byte
+		signed bool
+		v2     bool
+		want   []int64
+	}{
+		{
+			name: "v1 run",
+			data: []byte{0x61, 0x00, 0x07},
+			want: slices.Repeat([]int64{7}, 100),
+		},
+		{
+			name: "v1 run with delta",
+			data: []byte{0x61, 0xff, 0x64},
+			want: func() []int64 {
+				var v []int64
+				for i := int64(100); i > 0; i-- {
+					v = append(v, i)
+				}
+				return v
+			}(),
+		},
+		{
+			name: "v1 literals",
+			data: []byte{0xfb, 0x02, 0x03, 0x06, 0x07, 0x0b},
+			want: []int64{2, 3, 6, 7, 11},
+		},
+		{
+			name:   "v1 signed literals",
+			data:   []byte{0xfd, 0x03, 0x04, 0x01},
+			signed: true,
+			want:   []int64{-2, 2, -1},
+		},
+		{
+			name: "v2 short repeat",
+			data: []byte{0x0a, 0x27, 0x10},
+			v2:   true,
+			want: []int64{10000, 10000, 10000, 10000, 10000},
+		},
+		{
+			name: "v2 direct",
+			data: []byte{0x5e, 0x03, 0x5c, 0xa1, 0xab, 0x1e, 0xde, 0xad, 0xbe, 0xef},
+			v2:   true,
+			want: []int64{23713, 43806, 57005, 48879},
+		},
+		{
+			name: "v2 patched base",
+			data: []byte{
+				0x8e, 0x13, 0x2b, 0x21, 0x07, 0xd0, 0x1e, 0x00, 0x14, 0x70, 0x28, 0x32, 0x3c, 0x46,
+				0x50, 0x5a, 0x64, 0x6e, 0x78, 0x82, 0x8c, 0x96, 0xa0, 0xaa, 0xb4, 0xbe, 0xfc, 0xe8,
+			},
+			v2: true,
+			want: []int64{
+				2030, 2000, 2020, 1000000, 2040, 2050, 2060, 2070, 2080, 2090,
+				2100, 2110, 2120, 2130, 2140, 2150, 2160, 2170, 2180, 2190,
+			},
+		},
+		{
+			name: "v2 delta",
+			data: []byte{0xc6, 0x09, 0x02, 0x02, 0x22, 0x42, 0x42, 0x46},
+			v2:   true,
+			want: []int64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29},
+		},
+		{
+			name: "v2 fixed delta",
+			data: []byte{0xc0, 0x04, 0x0a, 0x04},
+			v2:   true,
+			want: []int64{10, 12, 14, 16, 18},
+		},
+	}
+	for _, tt := range tests {
+		got, err := orcIntegers(tt.data, len(tt.want), tt.signed, tt.v2)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+		}
+
+		// Every run cut short fails instead of reading past the end.
+		for n := range len(tt.data) {
+			if _, err := orcIntegers(tt.data[:n], len(tt.want), tt.signed, tt.v2); err == nil {
+				t.Errorf("%s cut to %d bytes: no error", tt.name, n)
+			}
+		}
+	}
+}
+
+func TestORCBooleans(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want []bool
+	}{
+		{"run", []byte{0x61, 0x00}, make([]bool, 800)},
+		{"literals", []byte{0xfe, 0x44, 0x45}, []bool{false, true, false, false, false, true, false, false, false, true, false, false, false, true, false, true}},
+		{"partial byte", []byte{0xff, 0xa0}, []bool{true, false, true}},
+	}
+	for _, tt := range tests {
+		got, err := orcBooleans(tt.data, len(tt.want))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+		}
+		if _, err := orcBooleans(tt.data[:len(tt.data)-1], len(tt.want)); err == nil {
+			t.Errorf("%s cut short: no error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,830 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+var explainPermissions bool
+
+type permissionsCmdInput struct {
+	principal string
+	command   []string
+	timeout   int
+}
+
+// permission is an IAM action a command needs on a resource. Note says when
+// the action is only needed in some cases.
+type permission struct {
+	Action   string
+	Resource string
+	Note     string `json:",omitempty"`
+}
+
+// permissionPlan collects the permissions of a command.
+type permissionPlan struct {
+	partition      string
+	customEndpoint bool
+	permissions    []permission
+}
+
+// permissionSpec adds the permissions a command needs, given its parsed
+// flags and arguments, to a plan.
+type permissionSpec func(p *permissionPlan, cmd *cobra.Command, args []string) error
+
+// commandPermissions holds the permission spec of every command that calls
+// AWS. Commands that are missing can't be explained.
+var commandPermissions = map[*cobra.Command]permissionSpec{}
+
+// noPermissions is the spec of commands that make no AWS calls.
+func noPermissions(p *permissionPlan, cmd *cobra.Command, args []string) error {
+	return nil
+}
+
+func (p *permissionPlan) add(action, resource, note string) {
+	perm := permission{action, resource, note}
+	for i, existing := range p.permissions {
+		if existing.Action == action && existing.Resource == resource {
+			// An unconditional need wins over a conditional one.
+			if existing.Note != "" && note == "" {
+				p.permissions[i] = perm
+			}
+			return
+		}
+	}
+	p.permissions = append(p.permissions, perm)
+}
+
+func (p *permissionPlan) bucketARN(bucket string) string {
+	return "arn:" + p.partition + ":s3:::" + bucket
+}
+
+// bucket adds an action on a bucket.
+func (p *permissionPlan) bucket(action, bucket, note string) {
+	p.add(action, p.bucketARN(bucket), note)
+}
+
+// locate adds what a bucket client needs to find the region of a bucket.
+// S3 compatible endpoints are assumed to be in the configured region.
+func (p *permissionPlan) locate(bucket string) {
+	if !p.customEndpoint {
+		p.bucket("s3:GetBucketLocation", bucket, "")
+	}
+}
+
+// objects adds actions on the objects a location names: the object itself,
+// or every object under a prefix ending in "/", which has to be listed too.
+func (p *permissionPlan) objects(bucket, prefix string, actions ...string) {
+	resource := p.bucketARN(bucket) + "/" + prefix
+	if prefix == "" || strings.HasSuffix(prefix, "/") {
+		p.bucket("s3:ListBucket", bucket, "")
+		resource += "*"
+	}
+	for _, action := range actions {
+		p.add(action, resource, "")
+	}
+}
+
+// protection adds what guardDestructive needs to read protection tags.
+func (p *permissionPlan) protection(bucket string) error {
+	s, err := loadSettings()
+	if err != nil {
+		return err
+	}
+	if len(s.Protect.Tags) > 0 {
+		p.bucket("s3:GetBucketTagging", bucket, "to check protection tags")
+	}
+	return nil
+}
+
+// kmsKey adds an action on a KMS key given by ID, ARN or alias, or on any key
+// when none is given.
+func (p *permissionPlan) kmsKey(action, key, note string) {
+	switch {
+	case key == "":
+		key = "*"
+	case strings.HasPrefix(key, "alias/"):
+		key = "arn:" + p.partition + ":kms:*:*:" + key
+	case !strings.HasPrefix(key, "arn:"):
+		key = "arn:" + p.partition + ":kms:*:*:key/" + key
+	}
+	p.add(action, key, note)
+}
+
+// planPermissions returns the permissions cmd needs to run with args.
+func planPermissions(ctx context.Context, cmd *cobra.Command, args []string) ([]permission, error) {
+	spec, ok := commandPermissions[cmd]
+	if !ok {
+		return nil, fmt.Errorf("the permissions of %s are not known", cmd.CommandPath())
+	}
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p := &permissionPlan{
+		partition:      cloud.Partition(cfg.Region),
+		customEndpoint: aws.ToString(cfg.BaseEndpoint) != "",
+	}
+	if err := spec(p, cmd, args); err != nil {
+		return nil, err
+	}
+	return p.permissions, nil
+}
+
+// policyStatement is a statement of an IAM policy document.
+type policyStatement struct {
+	Effect   string
+	Action   []string
+	Resource string
+}
+
+// policyDocument is an IAM policy document.
+type policyDocument struct {
+	Version   string
+	Statement []policyStatement
+}
+
+// permissionsPolicy turns permissions into a policy document with a
+// statement per resource.
+func permissionsPolicy(permissions []permission) *policyDocument {
+	policy := &policyDocument{Version: "2012-10-17"}
+	byResource := map[string]int{}
+	for _, perm := range permissions {
+		i, ok := byResource[perm.Resource]
+		if !ok {
+			i = len(policy.Statement)
+			byResource[perm.Resource] = i
+			policy.Statement = append(policy.Statement, policyStatement{Effect: "Allow", Resource: perm.Resource})
+		}
+		policy.Statement[i].Action = append(policy.Statement[i].Action, perm.Action)
+	}
+	return policy
+}
+
+// printPermissions prints permissions as a table, or as a policy document
+// with JSON output.
+func printPermissions(cmd *cobra.Command, permissions []permission) {
+	if jsonOutput(false) {
+		jsonData, _ := json.MarshalIndent(permissionsPolicy(permissions), "", "  ")
+		fmt.Println(string(jsonData))
+		return
+	}
+	if len(permissions) == 0 {
+		fmt.Printf("%s makes no AWS calls\n", cmd.CommandPath())
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACTION\tRESOURCE\tNOTE")
+	for _, perm := range permissions {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", perm.Action, perm.Resource, perm.Note)
+	}
+	w.Flush()
+}
+
+// explainCommand prints the permissions cmd needs instead of running it.
+func explainCommand(cmd *cobra.Command, args []string) {
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	permissions, err := planPermissions(ctx, cmd, args)
+	if err != nil {
+		log.Fatalf("Unable to explain permissions: %v", err)
+	}
+	printPermissions(cmd, permissions)
+}
+
+// permissionsCmd represents the permissions command
+var permissionsCmd = &cobra.Command{
+	Use:   "permissions",
+	Short: "Work out the IAM permissions of commands",
+}
+
+// permissionsCheckCmd represents the permissions check command
+var permissionsCheckCmd = &cobra.Command{
+	Use:   "check [--principal arn] -- <command> [args...]",
+	Short: "Check whether a principal may run a command",
+	Long: `Works out the IAM actions and resources a command line needs, as
+--explain-permissions does, and evaluates them against the policies of a user
+or role with the IAM policy simulator. The principal defaults to the caller;
+for an assumed role, the role itself is checked.
+
+The simulator evaluates identity policies, permissions boundaries and
+organization SCPs, but not bucket policies or KMS key policies. It needs
+iam:SimulatePrincipalPolicy, and S3 compatible endpoints don't offer it. The
+command exits with status 1 if an action is denied.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		principal, _ := cmd.Flags().GetString("principal")
+		timeout, _ := cmd.Flags().GetInt("timeout")
+		checkPermissions(&permissionsCmdInput{principal, args, timeout})
+	},
+}
+
+// parseCommandLine finds the command a command line runs and parses its
+// flags the way cobra would.
+func parseCommandLine(line []string) (*cobra.Command, []string, error) {
+	target, rest, err := rootCmd.Find(line)
+	if err != nil {
+		return nil, nil, err
+	}
+	if target == rootCmd {
+		return nil, nil, fmt.Errorf("unknown command %q", line[0])
+	}
+	if err := target.ParseFlags(rest); err != nil {
+		return nil, nil, err
+	}
+	args := target.Flags().Args()
+	if err := target.ValidateArgs(args); err != nil {
+		return nil, nil, err
+	}
+	if err := target.ValidateRequiredFlags(); err != nil {
+		return nil, nil, err
+	}
+	return target, args, nil
+}
+
+// simulationPrincipal returns the user or role to simulate. An assumed role
+// session stands for its role, looked up to get the role's path.
+func simulationPrincipal(ctx context.Context, cfg aws.Config, principal string) (string, error) {
+	if principal != "" {
+		return principal, nil
+	}
+	identity, err := sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", err
+	}
+	parsed, err := arn.Parse(aws.ToString(identity.Arn))
+	if err != nil {
+		return "", err
+	}
+	if parsed.Service == "iam" {
+		return parsed.String(), nil
+	}
+	name, ok := strings.CutPrefix(parsed.Resource, "assumed-role/")
+	if !ok {
+		return "", fmt.Errorf("%s can't be simulated, pass --principal", parsed)
+	}
+	name, _, _ = strings.Cut(name, "/")
+	role, err := iam.NewFromConfig(cfg).GetRole(ctx, &iam.GetRoleInput{RoleName: &name})
+	if err != nil {
+		return fmt.Sprintf("arn:%s:iam::%s:role/%s", parsed.Partition, parsed.AccountID, name), nil
+	}
+	return aws.ToString(role.Role.Arn), nil
+}
+
+// permissionResult is the simulated decision for a permission.
+type permissionResult struct {
+	permission
+	Decision string
+	Matched  []string `json:",omitempty"`
+}
+
+// simulatePermissions evaluates permissions for principal, with a call per
+// resource so that actions aren't paired with resources they don't apply to.
+func simulatePermissions(ctx context.Context, client *iam.Client, principal string, permissions []permission) ([]permissionResult, error) {
+	var resources []string
+	actions := map[string][]string{}
+	for _, perm := range permissions {
+		if _, ok := actions[perm.Resource]; !ok {
+			resources = append(resources, perm.Resource)
+		}
+		actions[perm.Resource] = append(actions[perm.Resource], perm.Action)
+	}
+
+	decisions := map[[2]string]permissionResult{}
+	for _, resource := range resources {
+		paginator := iam.NewSimulatePrincipalPolicyPaginator(client, &iam.SimulatePrincipalPolicyInput{
+			PolicySourceArn: &principal,
+			ActionNames:     actions[resource],
+			ResourceArns:    []string{resource},
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range page.EvaluationResults {
+				result := permissionResult{Decision: string(r.EvalDecision)}
+				for _, s := range r.MatchedStatements {
+					if id := aws.ToString(s.SourcePolicyId); id != "" && !slices.Contains(result.Matched, id) {
+						result.Matched = append(result.Matched, id)
+					}
+				}
+				decisions[[2]string{aws.ToString(r.EvalActionName), resource}] = result
+			}
+		}
+	}
+
+	results := make([]permissionResult, len(permissions))
+	for i, perm := range permissions {
+		results[i] = decisions[[2]string{perm.Action, perm.Resource}]
+		results[i].permission = perm
+	}
+	return results, nil
+}
+
+func checkPermissions(input *permissionsCmdInput) {
+	target, args, err := parseCommandLine(input.command)
+	if err != nil {
+		log.Fatalf("Invalid command: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), time.Duration(input.timeout)*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	permissions, err := planPermissions(ctx, target, args)
+	if err != nil {
+		log.Fatalf("Unable to work out permissions: %v", err)
+	}
+	if len(permissions) == 0 {
+		fmt.Printf("%s makes no AWS calls\n", target.CommandPath())
+		return
+	}
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+	principal, err := simulationPrincipal(ctx, cfg, input.principal)
+	if err != nil {
+		printPermissions(target, permissions)
+		log.Fatalf("Unable to find the principal to check: %v", err)
+	}
+	results, err := simulatePermissions(ctx, iam.NewFromConfig(cfg), principal, permissions)
+	if err != nil {
+		// Without the simulator, the permissions can still be checked by hand.
+		printPermissions(target, permissions)
+		log.Fatalf("Unable to simulate the permissions of %s: %v", principal, err)
+	}
+
+	denied := 0
+	for _, r := range results {
+		if r.Decision != "allowed" {
+			denied++
+		}
+	}
+
+	if jsonOutput(false) {
+		jsonData, _ := json.MarshalIndent(results, "", "  ")
+		fmt.Println(string(jsonData))
+	} else {
+		fmt.Printf("Permissions of %s for %s:\n", principal, target.CommandPath())
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ACTION\tRESOURCE\tDECISION\tMATCHED\tNOTE")
+		for _, r := range results {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Action, r.Resource, r.Decision, strings.Join(r.Matched, ","), r.Note)
+		}
+		w.Flush()
+	}
+
+	if denied > 0 {
+		log.Fatalf("%d of %d actions are denied", denied, len(results))
+	}
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&explainPermissions, "explain-permissions", false, "Print the IAM actions and resources the command needs instead of running it")
+
+	permissionsCheckCmd.Flags().String("principal", "", "ARN of the user or role to check (default the caller)")
+	permissionsCheckCmd.Flags().IntP("timeout", "t", 60, "Timeout in seconds")
+	permissionsCmd.AddCommand(permissionsCheckCmd)
+	rootCmd.AddCommand(permissionsCmd)
+
+	str := func(cmd *cobra.Command, name string) string {
+		v, _ := cmd.Flags().GetString(name)
+		return v
+	}
+	boolean := func(cmd *cobra.Command, name string) bool {
+		v, _ := cmd.Flags().GetBool(name)
+		return v
+	}
+	integer := func(cmd *cobra.Command, name string) int {
+		v, _ := cmd.Flags().GetInt(name)
+		return v
+	}
+	// bucketSpec is the spec of a command acting on the bucket named by
+	// --name with a single action.
+	bucketSpec := func(action string) permissionSpec {
+		return func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+			name := str(cmd, "name")
+			p.locate(name)
+			p.bucket(action, name, "")
+			return nil
+		}
+	}
+	// readModifyWrite is the spec of a command that updates a bucket
+	// configuration, a destructive change on protected buckets.
+	readModifyWrite := func(get, put string, destructive bool) permissionSpec {
+		return func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+			name := str(cmd, "name")
+			if destructive {
+				if err := p.protection(name); err != nil {
+					return err
+				}
+			}
+			p.locate(name)
+			if get != "" {
+				p.bucket(get, name, "")
+			}
+			p.bucket(put, name, "")
+			return nil
+		}
+	}
+
+	for _, cmd := range []*cobra.Command{historyCmd, pluginListCmd} {
+		commandPermissions[cmd] = noPermissions
+	}
+
+	commandPermissions[createCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		p.bucket("s3:CreateBucket", str(cmd, "name"), "")
+		return nil
+	}
+	commandPermissions[deleteCmd] = readModifyWrite("", "s3:DeleteBucket", true)
+	commandPermissions[listCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		if allRegions && len(regionList) == 0 {
+			p.add("ec2:DescribeRegions", "*", "")
+		}
+		p.add("s3:ListAllMyBuckets", "*", "")
+		return nil
+	}
+	commandPermissions[costEstimateCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			if allRegions && len(regionList) == 0 {
+				p.add("ec2:DescribeRegions", "*", "")
+			}
+			p.add("s3:ListAllMyBuckets", "*", "")
+			args = []string{"*"}
+		}
+		for _, bucket := range args {
+			p.locate(bucket)
+			p.bucket("s3:ListBucket", bucket, "")
+		}
+		return nil
+	}
+
+	commandPermissions[tagsGetCmd] = bucketSpec("s3:GetBucketTagging")
+	commandPermissions[tagsSetCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		if boolean(cmd, "replace") {
+			return readModifyWrite("", "s3:PutBucketTagging", true)(p, cmd, args)
+		}
+		return readModifyWrite("s3:GetBucketTagging", "s3:PutBucketTagging", false)(p, cmd, args)
+	}
+	commandPermissions[tagsUnsetCmd] = readModifyWrite("s3:GetBucketTagging", "s3:PutBucketTagging", true)
+
+	commandPermissions[corsGetCmd] = bucketSpec("s3:GetBucketCORS")
+	commandPermissions[corsPutCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		if boolean(cmd, "replace") {
+			return readModifyWrite("", "s3:PutBucketCORS", false)(p, cmd, args)
+		}
+		return readModifyWrite("s3:GetBucketCORS", "s3:PutBucketCORS", false)(p, cmd, args)
+	}
+	commandPermissions[corsDeleteCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		if str(cmd, "id") == "" {
+			return readModifyWrite("", "s3:PutBucketCORS", true)(p, cmd, args)
+		}
+		return readModifyWrite("s3:GetBucketCORS", "s3:PutBucketCORS", true)(p, cmd, args)
+	}
+
+	commandPermissions[encryptionGetCmd] = bucketSpec("s3:GetEncryptionConfiguration")
+	commandPermissions[encryptionSetCmd] = bucketSpec("s3:PutEncryptionConfiguration")
+	commandPermissions[encryptionDisableCmd] = readModifyWrite("", "s3:PutEncryptionConfiguration", true)
+	commandPermissions[encryptionScanCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		name := str(cmd, "name")
+		p.locate(name)
+		p.bucket("s3:GetEncryptionConfiguration", name, "")
+		p.bucket("s3:ListBucket", name, "")
+		p.add("s3:GetObject", p.bucketARN(name)+"/"+str(cmd, "prefix")+"*", "")
+		return nil
+	}
+
+	commandPermissions[notificationsListCmd] = bucketSpec("s3:GetBucketNotification")
+	commandPermissions[notificationsAddCmd] = readModifyWrite("s3:GetBucketNotification", "s3:PutBucketNotification", false)
+	commandPermissions[notificationsRemoveCmd] = readModifyWrite("s3:GetBucketNotification", "s3:PutBucketNotification", true)
+
+	commandPermissions[websiteShowCmd] = bucketSpec("s3:GetBucketWebsite")
+	commandPermissions[websiteEnableCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		name := str(cmd, "name")
+		p.locate(name)
+		p.bucket("s3:PutBucketWebsite", name, "")
+		if boolean(cmd, "public") {
+			p.bucket("s3:GetBucketPublicAccessBlock", name, "")
+			p.bucket("s3:PutBucketPublicAccessBlock", name, "")
+			p.bucket("s3:GetBucketPolicy", name, "")
+			p.bucket("s3:PutBucketPolicy", name, "")
+		}
+		return nil
+	}
+	commandPermissions[websiteDisableCmd] = readModifyWrite("", "s3:DeleteBucketWebsite", true)
+
+	sampleReplication := func(p *permissionPlan, cmd *cobra.Command) {
+		if source := str(cmd, "source"); integer(cmd, "sample") > 0 {
+			p.bucket("s3:ListBucket", source, "")
+			p.add("s3:GetObject", p.bucketARN(source)+"/"+str(cmd, "prefix")+"*", "")
+		}
+	}
+	commandPermissions[replicationSetupCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		source, dest := str(cmd, "source"), str(cmd, "dest")
+		for _, bucket := range []string{source, dest} {
+			p.locate(bucket)
+			p.bucket("s3:GetBucketVersioning", bucket, "")
+			p.bucket("s3:PutBucketVersioning", bucket, "if versioning is off")
+		}
+		p.bucket("s3:GetReplicationConfiguration", source, "")
+		p.bucket("s3:PutReplicationConfiguration", source, "")
+		p.add("iam:PassRole", str(cmd, "role"), "")
+		sampleReplication(p, cmd)
+		return nil
+	}
+	commandPermissions[replicationStatusCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		p.locate(str(cmd, "source"))
+		sampleReplication(p, cmd)
+		return nil
+	}
+
+	commandPermissions[uploadCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		bucket, prefix, err := cloud.ParseS3URI(args[1])
+		if err != nil {
+			return err
+		}
+		p.locate(bucket)
+		resource := p.bucketARN(bucket) + "/" + prefix
+		if info, err := os.Stat(args[0]); err == nil && info.IsDir() || prefix == "" || strings.HasSuffix(prefix, "/") {
+			resource += "*"
+		}
+		p.add("s3:PutObject", resource, "")
+		p.add("s3:AbortMultipartUpload", resource, "to clean up files uploaded in parts that fail or are interrupted")
+		if sse := str(cmd, "sse"); strings.HasPrefix(sse, "aws:kms") {
+			p.kmsKey("kms:GenerateDataKey", str(cmd, "sse-kms-key-id"), "")
+			p.kmsKey("kms:Decrypt", str(cmd, "sse-kms-key-id"), "for files uploaded in parts")
+		}
+		return nil
+	}
+	commandPermissions[downloadCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		bucket, prefix, err := cloud.ParseS3URI(args[0])
+		if err != nil {
+			return err
+		}
+		p.locate(bucket)
+		p.objects(bucket, prefix, "s3:GetObject")
+		p.kmsKey("kms:Decrypt", "", "for objects encrypted with KMS")
+		return nil
+	}
+	commandPermissions[queryCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		if !strings.HasPrefix(args[0], "s3://") {
+			return nil
+		}
+		bucket, key, err := cloud.ParseS3URI(args[0])
+		if err != nil {
+			return err
+		}
+		p.locate(bucket)
+		p.add("s3:GetObject", p.bucketARN(bucket)+"/"+key, "")
+		p.kmsKey("kms:Decrypt", "", "if the object is encrypted with KMS")
+		return nil
+	}
+
+	commandPermissions[findCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		bucket, prefix, err := cloud.ParseS3URI(args[0])
+		if err != nil {
+			return err
+		}
+		if boolean(cmd, "delete") {
+			if err := p.protection(bucket); err != nil {
+				return err
+			}
+		}
+		p.locate(bucket)
+		p.bucket("s3:ListBucket", bucket, "")
+		objects := p.bucketARN(bucket) + "/" + prefix + "*"
+		if tags, _ := cmd.Flags().GetStringSlice("tag"); len(tags) > 0 {
+			p.add("s3:GetObjectTagging", objects, "")
+		}
+		if boolean(cmd, "delete") {
+			p.add("s3:DeleteObject", objects, "")
+		}
+		return nil
+	}
+	commandPermissions[diffCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		for _, arg := range args {
+			if !strings.HasPrefix(arg, "s3://") {
+				continue
+			}
+			bucket, prefix, err := cloud.ParseS3URI(arg)
+			if err != nil {
+				return err
+			}
+			p.locate(bucket)
+			p.bucket("s3:ListBucket", bucket, "")
+			if boolean(cmd, "checksum") {
+				p.add("s3:GetObject", p.bucketARN(bucket)+"/"+prefix+"*", "")
+			}
+		}
+		return nil
+	}
+	commandPermissions[verifyCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		bucket, prefix, err := cloud.ParseS3URI(args[0])
+		if err != nil {
+			return err
+		}
+		p.locate(bucket)
+		p.bucket("s3:ListBucket", bucket, "")
+		p.add("s3:GetObject", p.bucketARN(bucket)+"/"+prefix+"*", "")
+		return nil
+	}
+	commandPermissions[watchCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		bucket, _, err := cloud.ParseS3URI(args[0])
+		if err != nil {
+			return err
+		}
+		p.locate(bucket)
+		p.bucket("s3:ListBucket", bucket, "")
+		return nil
+	}
+
+	restoreSpec := func(action string) permissionSpec {
+		return func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+			for _, uri := range args {
+				bucket, prefix, err := cloud.ParseS3URI(uri)
+				if err != nil {
+					return err
+				}
+				p.locate(bucket)
+				actions := []string{"s3:GetObject"}
+				if action != "" {
+					actions = append(actions, action)
+				}
+				p.objects(bucket, prefix, actions...)
+			}
+			if str(cmd, "download") != "" {
+				p.kmsKey("kms:Decrypt", "", "for objects encrypted with KMS")
+			}
+			return nil
+		}
+	}
+	commandPermissions[restoreRequestCmd] = restoreSpec("s3:RestoreObject")
+	commandPermissions[restoreStatusCmd] = restoreSpec("")
+
+	objectSpec := func(destructive bool, actions ...string) permissionSpec {
+		return func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+			bucket, prefix, err := cloud.ParseS3URI(args[0])
+			if err != nil {
+				return err
+			}
+			p.locate(bucket)
+			p.objects(bucket, prefix, actions...)
+			if destructive {
+				return p.protection(bucket)
+			}
+			return nil
+		}
+	}
+	commandPermissions[objectTagGetCmd] = objectSpec(false, "s3:GetObjectTagging")
+	commandPermissions[objectTagSetCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		if boolean(cmd, "replace") {
+			return objectSpec(true, "s3:PutObjectTagging")(p, cmd, args)
+		}
+		return objectSpec(true, "s3:GetObjectTagging", "s3:PutObjectTagging")(p, cmd, args)
+	}
+	// Removing the last tag deletes the tag set.
+	commandPermissions[objectTagRmCmd] = objectSpec(true, "s3:GetObjectTagging", "s3:PutObjectTagging", "s3:DeleteObjectTagging")
+	// The object is copied onto itself along with its tags.
+	commandPermissions[objectMetadataSetCmd] = objectSpec(true, "s3:GetObject", "s3:PutObject", "s3:GetObjectTagging", "s3:PutObjectTagging")
+
+	commandPermissions[exportCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		bucket, prefix, err := cloud.ParseS3URI(args[0])
+		if err != nil {
+			return err
+		}
+		p.locate(bucket)
+		if prefix != "" && !strings.HasSuffix(prefix, "/") {
+			prefix += "/"
+		}
+		p.objects(bucket, prefix, "s3:GetObject", "s3:GetObjectTagging")
+		return nil
+	}
+	commandPermissions[importCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		bucket, prefix, err := cloud.ParseS3URI(args[1])
+		if err != nil {
+			return err
+		}
+		p.locate(bucket)
+		if prefix != "" && !strings.HasSuffix(prefix, "/") {
+			prefix += "/"
+		}
+		p.objects(bucket, prefix, "s3:PutObject")
+		p.add("s3:PutObjectTagging", p.bucketARN(bucket)+"/"+prefix+"*", "for objects exported with tags")
+		p.add("s3:AbortMultipartUpload", p.bucketARN(bucket)+"/"+prefix+"*", "to clean up objects uploaded in parts that fail or are interrupted")
+		return p.protection(bucket)
+	}
+
+	// delivery adds what granting a service delivery into a bucket takes.
+	delivery := func(p *permissionPlan, cmd *cobra.Command, bucket string) {
+		p.locate(bucket)
+		if boolean(cmd, "grant") {
+			p.bucket("s3:GetBucketPolicy", bucket, "")
+			p.bucket("s3:PutBucketPolicy", bucket, "")
+		} else {
+			p.bucket("s3:GetBucketPolicy", bucket, "to check delivery is allowed")
+		}
+	}
+	commandPermissions[loggingGetCmd] = bucketSpec("s3:GetBucketLogging")
+	commandPermissions[loggingEnableCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		name := str(cmd, "name")
+		p.locate(name)
+		p.bucket("s3:PutBucketLogging", name, "")
+		delivery(p, cmd, str(cmd, "target-bucket"))
+		return nil
+	}
+	commandPermissions[loggingDisableCmd] = readModifyWrite("", "s3:PutBucketLogging", true)
+
+	commandPermissions[inventoryListCmd] = bucketSpec("s3:GetInventoryConfiguration")
+	commandPermissions[inventorySetCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		name := str(cmd, "name")
+		p.locate(name)
+		p.bucket("s3:PutInventoryConfiguration", name, "")
+		if str(cmd, "destination-account") == "" {
+			bucket, _, err := cloud.ParseS3URI(str(cmd, "destination"))
+			if err != nil {
+				return err
+			}
+			delivery(p, cmd, bucket)
+		}
+		return nil
+	}
+	commandPermissions[inventoryDeleteCmd] = readModifyWrite("", "s3:PutInventoryConfiguration", true)
+	commandPermissions[inventoryReadCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			// The destination is only known once the configuration is read.
+			name := str(cmd, "name")
+			p.locate(name)
+			p.bucket("s3:GetInventoryConfiguration", name, "")
+			p.add("s3:ListBucket", "arn:"+p.partition+":s3:::*", "on the inventory destination")
+			p.add("s3:GetObject", "arn:"+p.partition+":s3:::*/"+name+"/"+str(cmd, "id")+"/*", "on the inventory destination")
+			return nil
+		}
+		bucket, prefix, err := cloud.ParseS3URI(args[0])
+		if err != nil {
+			return err
+		}
+		p.locate(bucket)
+		prefix = strings.TrimSuffix(prefix, "/")
+		if path.Base(prefix) == "manifest.json" {
+			prefix = path.Dir(prefix)
+		}
+		// The data files are in a folder next to the one of each report.
+		if inventoryDatePattern.MatchString(path.Base(prefix)) {
+			prefix = path.Dir(prefix)
+		}
+		if prefix == "." {
+			prefix = ""
+		} else if prefix != "" {
+			prefix += "/"
+		}
+		p.objects(bucket, prefix, "s3:GetObject")
+		return nil
+	}
+
+	commandPermissions[doctorCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		p.add("sts:GetCallerIdentity", "*", "")
+		p.add("s3:ListAllMyBuckets", "*", "")
+		if bucket := str(cmd, "bucket"); bucket != "" {
+			p.locate(bucket)
+			p.bucket("s3:ListBucket", bucket, "")
+			if boolean(cmd, "write") {
+				p.add("s3:PutObject", p.bucketARN(bucket)+"/.go-cloud-cli-doctor-*", "")
+				p.add("s3:DeleteObject", p.bucketARN(bucket)+"/.go-cloud-cli-doctor-*", "")
+			}
+		}
+		return nil
+	}
+	commandPermissions[permissionsCheckCmd] = func(p *permissionPlan, cmd *cobra.Command, args []string) error {
+		principal := str(cmd, "principal")
+		if principal == "" {
+			p.add("sts:GetCallerIdentity", "*", "")
+			p.add("iam:GetRole", "arn:"+p.partition+":iam::*:role/*", "when running as an assumed role")
+			principal = "arn:" + p.partition + ":iam::*:*"
+		}
+		p.add("iam:SimulatePrincipalPolicy", principal, "")
+		return nil
+	}
+}
@@ -0,0 +1,257 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// pluginPrefix is prepended to a command name to find its plugin executable.
+const pluginPrefix = "go-cloud-cli-"
+
+// plugin is an executable on $PATH that extends the CLI with a new command.
+type plugin struct {
+	Name     string
+	Path     string
+	Shadowed []string
+	Builtin  bool
+}
+
+// pluginCmd represents the plugin command
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Inspect external subcommands",
+	Long: `Any executable named go-cloud-cli-<name> on $PATH can be run as
+"go-cloud-cli <name>". The resolved profile, region, endpoint and output format
+are passed to it in the environment.`,
+}
+
+// pluginListCmd represents the plugin list command
+var pluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the plugins found on $PATH",
+	Run: func(cmd *cobra.Command, args []string) {
+		listPlugins()
+	},
+}
+
+func listPlugins() {
+	plugins := discoverPlugins()
+	if len(plugins) == 0 {
+		fmt.Println("No plugins found on $PATH")
+		return
+	}
+
+	fmt.Println("Plugins:")
+	for _, p := range plugins {
+		fmt.Println(p.Path)
+		if p.Builtin {
+			log.Printf("Warning: %s is shadowed by the built-in %q command and will never run", p.Path, p.Name)
+		}
+		for _, s := range p.Shadowed {
+			log.Printf("Warning: %s is shadowed by %s", s, p.Path)
+		}
+	}
+}
+
+// discoverPlugins walks $PATH in order. The first executable with a given name
+// wins, later ones are recorded as shadowed, the same way the shell resolves
+// them.
+func discoverPlugins() []plugin {
+	byName := map[string]*plugin{}
+	var names []string
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name())
+			if !ok || entry.IsDir() {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			if p, seen := byName[name]; seen {
+				p.Shadowed = append(p.Shadowed, path)
+				continue
+			}
+			byName[name] = &plugin{Name: name, Path: path, Builtin: isBuiltinCommand(name)}
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	plugins := make([]plugin, 0, len(names))
+	for _, name := range names {
+		plugins = append(plugins, *byName[name])
+	}
+	return plugins
+}
+
+// pluginName returns the command name a plugin file provides.
+func pluginName(file string) (string, bool) {
+	if !strings.HasPrefix(file, pluginPrefix) {
+		return "", false
+	}
+	if runtime.GOOS == "windows" {
+		file = strings.TrimSuffix(file, filepath.Ext(file))
+	}
+	name := strings.TrimPrefix(file, pluginPrefix)
+	return name, name != ""
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return info.Mode()&0o111 != 0
+}
+
+func isBuiltinCommand(name string) bool {
+	if name == "help" || name == "completion" {
+		return true
+	}
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// runPlugin runs the plugin for args if args don't name a built-in command,
+// and returns its exit code. ok is false when there is no such plugin, so that
+// cobra can report the unknown command as usual.
+func runPlugin(args []string) (code int, ok bool) {
+	name, rest, ok := splitPluginArgs(args)
+	if !ok || isBuiltinCommand(name) {
+		return 0, false
+	}
+
+	path, err := exec.LookPath(pluginPrefix + name)
+	if err != nil {
+		return 0, false
+	}
+
+	env, err := pluginEnv()
+	if err != nil {
+		log.Fatalf("Unable to resolve configuration for plugin %s: %v", name, err)
+	}
+
+	c := exec.Command(path, rest...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	c.Env = env
+	if err := c.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode(), true
+		}
+		log.Fatalf("Failed to run plugin %s: %v", name, err)
+	}
+	return 0, true
+}
+
+// splitPluginArgs finds the command name in args, parsing any global flags
+// that come before it, and returns the arguments that follow it.
+func splitPluginArgs(args []string) (string, []string, bool) {
+	flags := rootCmd.PersistentFlags()
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || !strings.HasPrefix(arg, "-") {
+			if arg == "--" {
+				i++
+			}
+			if i >= len(args) {
+				return "", nil, false
+			}
+			if err := flags.Parse(args[:i]); err != nil {
+				return "", nil, false
+			}
+			return args[i], args[i+1:], true
+		}
+		if strings.Contains(arg, "=") {
+			continue
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		f := flags.Lookup(name)
+		if f == nil && len(name) == 1 {
+			f = flags.ShorthandLookup(name)
+		}
+		if f == nil {
+			// Not a global flag, so this can't be a plugin invocation.
+			return "", nil, false
+		}
+		if f.NoOptDefVal == "" {
+			i++
+		}
+	}
+	return "", nil, false
+}
+
+// pluginEnv returns the environment for a plugin: ours, plus the settings the
+// plugin needs to talk to the same account the way we would.
+func pluginEnv() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	env := os.Environ()
+	set := func(key, value string) {
+		if value != "" {
+			env = append(env, key+"="+value)
+		}
+	}
+	set("GO_CLOUD_CLI_PROFILE", resolvedProfile())
+	set("GO_CLOUD_CLI_REGION", cfg.Region)
+	set("GO_CLOUD_CLI_ENDPOINT_URL", resolvedEndpoint())
+	set("GO_CLOUD_CLI_OUTPUT", outputFormat)
+	set("AWS_PROFILE", resolvedProfile())
+	set("AWS_REGION", cfg.Region)
+	set("AWS_ENDPOINT_URL", resolvedEndpoint())
+
+	// An assumed role only exists in our process; hand the plugin the session
+	// rather than making it assume the role, and prompt for MFA, again.
+	if roleArn != "" {
+		creds, err := cfg.Credentials.Retrieve(ctx)
+		if err != nil {
+			return nil, err
+		}
+		set("AWS_ACCESS_KEY_ID", creds.AccessKeyID)
+		set("AWS_SECRET_ACCESS_KEY", creds.SecretAccessKey)
+		set("AWS_SESSION_TOKEN", creds.SessionToken)
+	}
+	return env, nil
+}
+
+func init() {
+	pluginCmd.AddCommand(pluginListCmd)
+	rootCmd.AddCommand(pluginCmd)
+}
@@ -0,0 +1,92 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+// grantsService reports whether a policy statement allows an AWS service
+// principal, such as logging.s3.amazonaws.com, to do anything.
+func grantsService(statement any, service string) bool {
+	s, ok := statement.(map[string]any)
+	if !ok || s["Effect"] != "Allow" {
+		return false
+	}
+	principal, _ := s["Principal"].(map[string]any)
+	switch v := principal["Service"].(type) {
+	case string:
+		return v == service
+	case []any:
+		for _, p := range v {
+			if p == service {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// deliveryAllowed reports whether the policy of a bucket lets an AWS service
+// deliver objects, such as access logs or inventory reports, into it.
+func deliveryAllowed(ctx context.Context, cc *cloud.Client, bucket, service string) (bool, error) {
+	_, statements, err := cc.BucketPolicy(ctx, bucket)
+	if err != nil {
+		return false, err
+	}
+	for _, s := range statements {
+		if grantsService(s, service) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// grantDelivery adds a statement to the policy of a bucket that lets an AWS
+// service write objects under prefix on behalf of source. The Sid of the
+// statement is sid followed by the name of source; it returns false if the
+// policy has that statement already.
+func grantDelivery(ctx context.Context, cc *cloud.Client, sid, bucket, prefix, source, service string, condition map[string]any) (bool, error) {
+	policy, statements, err := cc.BucketPolicy(ctx, bucket)
+	if err != nil {
+		return false, err
+	}
+
+	// Sids may only hold letters and digits.
+	sid += strings.Map(func(r rune) rune {
+		if 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' {
+			return r
+		}
+		return -1
+	}, source)
+	for _, s := range statements {
+		if m, ok := s.(map[string]any); ok && m["Sid"] == sid {
+			return false, nil
+		}
+	}
+
+	client, err := cc.BucketClient(ctx, bucket)
+	if err != nil {
+		return false, err
+	}
+	partition := cloud.Partition(client.Options().Region)
+	if condition == nil {
+		condition = map[string]any{}
+	}
+	condition["ArnLike"] = map[string]any{"aws:SourceArn": "arn:" + partition + ":s3:::" + source}
+	policy["Statement"] = append(statements, map[string]any{
+		"Sid":       sid,
+		"Effect":    "Allow",
+		"Principal": map[string]any{"Service": service},
+		"Action":    "s3:PutObject",
+		"Resource":  "arn:" + partition + ":s3:::" + bucket + "/" + prefix + "*",
+		"Condition": condition,
+	})
+
+	err = cc.PutBucketPolicy(ctx, bucket, policy)
+	return err == nil, err
+}
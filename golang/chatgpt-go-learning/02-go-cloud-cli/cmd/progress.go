@@ -0,0 +1,264 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+// transferStatus is how far a run of transfers got. It is printed as JSON
+// lines while the run goes on when stderr isn't a terminal, and as the
+// summary at the end.
+type transferStatus struct {
+	Files          int
+	Failed         int
+	Unfinished     int `json:",omitempty"`
+	FilesTotal     int
+	Bytes          int64
+	BytesTotal     int64
+	ElapsedSeconds float64
+	BytesPerSecond int64
+	ETASeconds     float64 `json:",omitempty"`
+}
+
+func (s transferStatus) String() string {
+	summary := fmt.Sprintf("Transferred %d of %d files, %s in %s (%s/s)", s.Files, s.FilesTotal, formatBytes(s.Bytes),
+		(time.Duration(s.ElapsedSeconds * float64(time.Second))).Round(time.Millisecond), formatBytes(s.BytesPerSecond))
+	if s.Failed > 0 {
+		summary += fmt.Sprintf(", %d failed", s.Failed)
+	}
+	if s.Unfinished > 0 {
+		summary += fmt.Sprintf(", %d unfinished", s.Unfinished)
+	}
+	return summary
+}
+
+// fileProgress counts the bytes of one file moved so far.
+type fileProgress struct {
+	name  string
+	size  int64
+	done  atomic.Int64
+	total *atomic.Int64
+}
+
+// Add counts n more bytes.
+func (f *fileProgress) Add(n int64) {
+	f.done.Add(n)
+	f.total.Add(n)
+}
+
+// Set moves the count back or forward to n, for when the SDK rewinds a body
+// to sign or retry a request.
+func (f *fileProgress) Set(n int64) {
+	f.total.Add(n - f.done.Swap(n))
+}
+
+// transferProgress reports the progress of a run of transfers on stderr:
+// as a bar for every file in flight and one for the whole run on a
+// terminal, or as a JSON line every few seconds otherwise. While it runs,
+// log output goes through it so that messages don't tear the bars.
+type transferProgress struct {
+	start      time.Time
+	filesTotal int
+	bytesTotal int64
+	tty        bool
+	bytes      atomic.Int64
+
+	mu      sync.Mutex
+	active  []*fileProgress
+	files   int
+	failed  int
+	drawn   int
+	logOut  io.Writer
+	stopped chan struct{}
+	done    chan struct{}
+}
+
+func newTransferProgress(jobs []cloud.Transfer) *transferProgress {
+	p := &transferProgress{
+		start:      time.Now(),
+		filesTotal: len(jobs),
+		tty:        term.IsTerminal(int(os.Stderr.Fd())),
+		logOut:     log.Writer(),
+		stopped:    make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	for _, job := range jobs {
+		p.bytesTotal += job.Size
+	}
+
+	interval := 5 * time.Second
+	if p.tty {
+		interval = 200 * time.Millisecond
+		log.SetOutput(p)
+	}
+	go func() {
+		defer close(p.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stopped:
+				return
+			case <-ticker.C:
+				p.mu.Lock()
+				p.draw()
+				p.mu.Unlock()
+			}
+		}
+	}()
+	return p
+}
+
+// begin starts counting a file.
+func (p *transferProgress) begin(job cloud.Transfer) *fileProgress {
+	f := &fileProgress{name: job.Key, size: job.Size, total: &p.bytes}
+	p.mu.Lock()
+	p.active = append(p.active, f)
+	p.mu.Unlock()
+	return f
+}
+
+// end stops counting a file. The bytes of a file that didn't make it are
+// taken off the total again.
+func (p *transferProgress) end(f *fileProgress, ok bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, a := range p.active {
+		if a == f {
+			p.active = append(p.active[:i], p.active[i+1:]...)
+			break
+		}
+	}
+	if !ok {
+		f.Set(0)
+		return
+	}
+	p.files++
+}
+
+// fail counts a file that failed for a reason other than the run stopping.
+func (p *transferProgress) fail() {
+	p.mu.Lock()
+	p.failed++
+	p.mu.Unlock()
+}
+
+// stop clears the bars, puts the log output back and returns the summary.
+func (p *transferProgress) stop() transferStatus {
+	close(p.stopped)
+	<-p.done
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clear()
+	if p.tty {
+		log.SetOutput(p.logOut)
+	}
+	status := p.status()
+	status.Unfinished = status.FilesTotal - status.Files - status.Failed
+	status.ETASeconds = 0
+	return status
+}
+
+// Write writes log output above the bars.
+func (p *transferProgress) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clear()
+	n, err := p.logOut.Write(b)
+	p.draw()
+	return n, err
+}
+
+func (p *transferProgress) status() transferStatus {
+	elapsed := time.Since(p.start).Seconds()
+	bytes := p.bytes.Load()
+	rate := float64(bytes) / max(elapsed, 0.001)
+	status := transferStatus{
+		Files:          p.files,
+		Failed:         p.failed,
+		FilesTotal:     p.filesTotal,
+		Bytes:          bytes,
+		BytesTotal:     p.bytesTotal,
+		ElapsedSeconds: elapsed,
+		BytesPerSecond: int64(rate),
+	}
+	if rate > 0 && bytes < p.bytesTotal {
+		status.ETASeconds = float64(p.bytesTotal-bytes) / rate
+	}
+	return status
+}
+
+// clear erases the bars drawn last. The caller holds p.mu.
+func (p *transferProgress) clear() {
+	if p.drawn > 0 {
+		fmt.Fprintf(os.Stderr, "\x1b[%dA\x1b[J", p.drawn)
+		p.drawn = 0
+	}
+}
+
+// draw redraws the bars, or prints a JSON line when stderr isn't a
+// terminal. The caller holds p.mu.
+func (p *transferProgress) draw() {
+	status := p.status()
+	if !p.tty {
+		data, _ := json.Marshal(status)
+		fmt.Fprintln(os.Stderr, string(data))
+		return
+	}
+
+	width, _, err := term.GetSize(int(os.Stderr.Fd()))
+	if err != nil || width < 40 {
+		width = 80
+	}
+
+	var lines []string
+	for _, f := range p.active {
+		lines = append(lines, fmt.Sprintf("%s %s / %s  %s",
+			progressBar(f.done.Load(), f.size), formatBytes(f.done.Load()), formatBytes(f.size), f.name))
+	}
+	total := fmt.Sprintf("%s %d/%d files  %s / %s  %s/s", progressBar(status.Bytes, status.BytesTotal),
+		status.Files, status.FilesTotal, formatBytes(status.Bytes), formatBytes(status.BytesTotal), formatBytes(status.BytesPerSecond))
+	if status.ETASeconds > 0 {
+		total += "  ETA " + (time.Duration(math.Ceil(status.ETASeconds)) * time.Second).String()
+	}
+	if status.Failed > 0 {
+		total += fmt.Sprintf("  %d failed", status.Failed)
+	}
+	lines = append(lines, total)
+
+	p.clear()
+	for _, line := range lines {
+		if r := []rune(line); len(r) > width-1 {
+			line = string(r[:width-1])
+		}
+		fmt.Fprintln(os.Stderr, line)
+	}
+	p.drawn = len(lines)
+}
+
+// progressBar renders done out of size as a bar and a percentage.
+func progressBar(done, size int64) string {
+	const width = 20
+	fraction := 1.0
+	if size > 0 {
+		fraction = min(float64(done)/float64(size), 1)
+	}
+	filled := int(fraction * width)
+	return fmt.Sprintf("[%s%s] %3d%%", strings.Repeat("#", filled), strings.Repeat(".", width-filled), int(fraction*100))
+}
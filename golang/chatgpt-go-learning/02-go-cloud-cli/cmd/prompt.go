@@ -0,0 +1,28 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var stdin = bufio.NewReader(os.Stdin)
+
+// ask prints a prompt on the terminal and returns the line typed in reply,
+// without surrounding spaces.
+func ask(format string, args ...any) string {
+	fmt.Fprintf(os.Stderr, format, args...)
+	answer, _ := stdin.ReadString('\n')
+	return strings.TrimSpace(answer)
+}
+
+// confirm asks a yes/no question on the terminal and reports whether the
+// answer was yes. Anything else, including EOF, is a no.
+func confirm(format string, args ...any) bool {
+	answer := strings.ToLower(ask(format+" [y/N]: ", args...))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,115 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+var (
+	readOnly         bool
+	iKnowWhatImDoing bool
+)
+
+// readOnlyMiddleware fails every mutating API call before it is sent, so that
+// --read-only holds for every command, including ones added later.
+func readOnlyMiddleware(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("ReadOnly",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			if operation := awsmiddleware.GetOperationName(ctx); isMutating(operation) {
+				return middleware.InitializeOutput{}, middleware.Metadata{}, fmt.Errorf("%s is not allowed with --read-only", operation)
+			}
+			return next.HandleInitialize(ctx, in)
+		}), middleware.After)
+}
+
+// protectionReason returns why a bucket is protected by the configuration
+// file, or "" if it isn't. Tags are only fetched when the configuration
+// protects buckets by tag.
+func protectionReason(bucket string) (string, error) {
+	s, err := loadSettings()
+	if err != nil {
+		return "", err
+	}
+
+	for _, p := range s.Protect.Buckets {
+		if ok, _ := path.Match(p, bucket); ok {
+			return fmt.Sprintf("name matches %s", p), nil
+		}
+	}
+	if len(s.Protect.Tags) == 0 {
+		return "", nil
+	}
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	tags, err := cloud.New(cfg).BucketTags(ctx, bucket)
+	if err != nil {
+		return "", err
+	}
+
+	protected, _ := parseTags(s.Protect.Tags)
+	for k, v := range protected {
+		if got, ok := tags[k]; ok && got == v {
+			return fmt.Sprintf("tagged %s=%s", k, v), nil
+		}
+	}
+	return "", nil
+}
+
+// setsProtectionTag reports whether tags set any of the tag keys the
+// configuration file protects buckets by. If the configuration can't be read,
+// it assumes they do.
+func setsProtectionTag(tags map[string]string) bool {
+	s, err := loadSettings()
+	if err != nil {
+		return true
+	}
+	protected, _ := parseTags(s.Protect.Tags)
+	for k := range tags {
+		if _, ok := protected[k]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// guardDestructive stops a destructive command on a protected bucket unless
+// the user passed --i-know-what-im-doing and types the bucket name. If the
+// protection can't be checked, the command is stopped as well.
+func guardDestructive(bucket string) {
+	reason, err := protectionReason(bucket)
+	if err != nil {
+		log.Fatalf("Unable to check whether %s is protected: %v", bucket, err)
+	}
+	if reason == "" {
+		return
+	}
+	if !iKnowWhatImDoing {
+		log.Fatalf("%s is protected (%s), refusing without --i-know-what-im-doing", bucket, reason)
+	}
+	if ask("%s is protected (%s). Type the bucket name to continue: ", bucket, reason) != bucket {
+		log.Fatalf("Aborted")
+	}
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&readOnly, "read-only", false, "Refuse every API call that changes state")
+	rootCmd.PersistentFlags().BoolVar(&iKnowWhatImDoing, "i-know-what-im-doing", false, "Allow destructive commands on protected buckets")
+}
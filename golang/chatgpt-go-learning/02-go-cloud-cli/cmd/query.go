@@ -0,0 +1,461 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bufio"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+type queryCmdInput struct {
+	source       string
+	sql          string
+	inputFormat  string
+	compression  string
+	csvHeader    string
+	delimiter    string
+	jsonType     string
+	outputFormat string
+	stats        bool
+	timeout      int
+}
+
+// queryCmd represents the query command
+var queryCmd = &cobra.Command{
+	Use:   "query <s3://bucket/key or file> <sql>",
+	Short: "Run an S3 Select query against a CSV, JSON or Parquet object",
+	Long: `Runs a SQL query against a single object with S3 Select and streams the
+matching records to stdout as they arrive, for example:
+
+  go-cloud-cli query s3://logs/2025/01/app.csv.gz "SELECT * FROM s3object s WHERE s.status='ERR'"
+
+The input format and compression are guessed from the key (.csv, .json,
+.jsonl, .ndjson, .parquet, optionally followed by .gz or .bz2) unless given.
+Records are written as CSV for CSV input and as JSON lines otherwise.
+
+A local file is queried offline with a built-in evaluator, which supports
+SELECT with columns or *, FROM s3object with an alias, WHERE with comparisons,
+LIKE, IS NULL, AND, OR and NOT, and LIMIT. It reads CSV and JSON but not
+Parquet.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		inputFormat, _ := cmd.Flags().GetString("input-format")
+		compression, _ := cmd.Flags().GetString("compression")
+		csvHeader, _ := cmd.Flags().GetString("csv-header")
+		delimiter, _ := cmd.Flags().GetString("delimiter")
+		jsonType, _ := cmd.Flags().GetString("json-type")
+		outputFormat, _ := cmd.Flags().GetString("output-format")
+		stats, _ := cmd.Flags().GetBool("stats")
+		timeout, _ := cmd.Flags().GetInt("timeout")
+		query(&queryCmdInput{args[0], args[1], inputFormat, compression, csvHeader, delimiter, jsonType, outputFormat, stats, timeout})
+	},
+}
+
+// resolveQueryFormats fills in the input format, compression and output format
+// that weren't given from the name of the source, and validates them.
+func resolveQueryFormats(input *queryCmdInput) error {
+	name := strings.ToLower(input.source)
+	if input.compression == "" {
+		input.compression = "none"
+		for ext, c := range map[string]string{".gz": "gzip", ".gzip": "gzip", ".bz2": "bzip2"} {
+			if trimmed, ok := strings.CutSuffix(name, ext); ok {
+				input.compression, name = c, trimmed
+			}
+		}
+	}
+	if input.inputFormat == "" {
+		switch {
+		case strings.HasSuffix(name, ".csv"):
+			input.inputFormat = "csv"
+		case strings.HasSuffix(name, ".json"), strings.HasSuffix(name, ".jsonl"), strings.HasSuffix(name, ".ndjson"):
+			input.inputFormat = "json"
+		case strings.HasSuffix(name, ".parquet"):
+			input.inputFormat = "parquet"
+		default:
+			return fmt.Errorf("unable to tell the format of %s, use --input-format", input.source)
+		}
+	}
+	if input.outputFormat == "" {
+		input.outputFormat = "json"
+		if input.inputFormat == "csv" {
+			input.outputFormat = "csv"
+		}
+	}
+
+	for flag, check := range map[string]struct {
+		value   string
+		allowed []string
+	}{
+		"--input-format":  {input.inputFormat, []string{"csv", "json", "parquet"}},
+		"--compression":   {input.compression, []string{"none", "gzip", "bzip2"}},
+		"--csv-header":    {input.csvHeader, []string{"use", "ignore", "none"}},
+		"--json-type":     {input.jsonType, []string{"lines", "document"}},
+		"--output-format": {input.outputFormat, []string{"csv", "json"}},
+	} {
+		if !containsFold(check.allowed, check.value) {
+			return fmt.Errorf("invalid %s %q, expected one of %s", flag, check.value, strings.Join(check.allowed, ", "))
+		}
+	}
+	if strings.EqualFold(input.inputFormat, "parquet") && !strings.EqualFold(input.compression, "none") {
+		return fmt.Errorf("parquet objects are compressed internally, --compression must be none")
+	}
+	if len([]rune(input.delimiter)) != 1 {
+		return fmt.Errorf("invalid --delimiter %q, expected a single character", input.delimiter)
+	}
+	return nil
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectSerialization builds the S3 Select input and output formats.
+func selectSerialization(input *queryCmdInput) (*types.InputSerialization, *types.OutputSerialization) {
+	in := &types.InputSerialization{CompressionType: types.CompressionType(strings.ToUpper(input.compression))}
+	switch strings.ToLower(input.inputFormat) {
+	case "csv":
+		in.CSV = &types.CSVInput{
+			FileHeaderInfo: types.FileHeaderInfo(strings.ToUpper(input.csvHeader)),
+			FieldDelimiter: aws.String(input.delimiter),
+		}
+	case "json":
+		in.JSON = &types.JSONInput{Type: types.JSONType(strings.ToUpper(input.jsonType))}
+	default:
+		in.Parquet = &types.ParquetInput{}
+	}
+
+	out := &types.OutputSerialization{JSON: &types.JSONOutput{RecordDelimiter: aws.String("\n")}}
+	if strings.EqualFold(input.outputFormat, "csv") {
+		out = &types.OutputSerialization{CSV: &types.CSVOutput{FieldDelimiter: aws.String(input.delimiter)}}
+	}
+	return in, out
+}
+
+// selectObject runs the query with S3 Select and copies the records to w as
+// the event stream delivers them.
+func selectObject(ctx context.Context, client *s3.Client, bucket, key string, input *queryCmdInput, w io.Writer) (*types.Stats, error) {
+	in, out := selectSerialization(input)
+	result, err := client.SelectObjectContent(ctx, &s3.SelectObjectContentInput{
+		Bucket:              &bucket,
+		Key:                 &key,
+		Expression:          &input.sql,
+		ExpressionType:      types.ExpressionTypeSql,
+		InputSerialization:  in,
+		OutputSerialization: out,
+	})
+	if err != nil {
+		return nil, err
+	}
+	stream := result.GetStream()
+	defer stream.Close()
+
+	var stats *types.Stats
+	ended := false
+	for event := range stream.Events() {
+		switch e := event.(type) {
+		case *types.SelectObjectContentEventStreamMemberRecords:
+			if _, err := w.Write(e.Value.Payload); err != nil {
+				return nil, err
+			}
+		case *types.SelectObjectContentEventStreamMemberStats:
+			stats = e.Value.Details
+		case *types.SelectObjectContentEventStreamMemberEnd:
+			ended = true
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+	// Without the End event the results may be incomplete.
+	if !ended {
+		return nil, errors.New("the result stream ended before the query finished")
+	}
+	return stats, nil
+}
+
+// selectWriter writes the records of a local query in the output format.
+type selectWriter struct {
+	csv  *csv.Writer
+	json *bufio.Writer
+}
+
+func newSelectWriter(w io.Writer, input *queryCmdInput) *selectWriter {
+	if strings.EqualFold(input.outputFormat, "csv") {
+		cw := csv.NewWriter(w)
+		cw.Comma = []rune(input.delimiter)[0]
+		return &selectWriter{csv: cw}
+	}
+	return &selectWriter{json: bufio.NewWriter(w)}
+}
+
+// write projects a record and writes it.
+func (sw *selectWriter) write(q *selectQuery, r *selectRecord) error {
+	var names []string
+	var values []any
+	if q.columns == nil {
+		names, values = r.names, r.row
+	} else {
+		for _, c := range q.columns {
+			names = append(names, c.name)
+			values = append(values, c.expr.eval(r))
+		}
+	}
+
+	if sw.csv != nil {
+		row := make([]string, len(values))
+		for i, v := range values {
+			row[i] = formatSelectValue(v)
+		}
+		return sw.csv.Write(row)
+	}
+
+	// Keep the columns in order, which a map wouldn't.
+	sw.json.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			sw.json.WriteByte(',')
+		}
+		k, _ := json.Marshal(name)
+		v, err := json.Marshal(values[i])
+		if err != nil {
+			return err
+		}
+		sw.json.Write(k)
+		sw.json.WriteByte(':')
+		sw.json.Write(v)
+	}
+	sw.json.WriteString("}\n")
+	return nil
+}
+
+func (sw *selectWriter) flush() error {
+	if sw.csv != nil {
+		sw.csv.Flush()
+		return sw.csv.Error()
+	}
+	return sw.json.Flush()
+}
+
+// selectRecords calls fn for every record of a local CSV or JSON input until
+// fn returns false.
+func selectRecords(r io.Reader, input *queryCmdInput, fn func(*selectRecord) bool) error {
+	if strings.EqualFold(input.inputFormat, "csv") {
+		cr := csv.NewReader(r)
+		cr.Comma = []rune(input.delimiter)[0]
+		cr.FieldsPerRecord = -1
+		cr.ReuseRecord = true
+
+		var header []string
+		if !strings.EqualFold(input.csvHeader, "none") {
+			first, err := cr.Read()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if strings.EqualFold(input.csvHeader, "use") {
+				header = append([]string(nil), first...)
+			}
+		}
+		for {
+			row, err := cr.Read()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			rec := &selectRecord{fields: map[string]any{}}
+			for i, v := range row {
+				pos := "_" + strconv.Itoa(i+1)
+				name := pos
+				if i < len(header) {
+					name = header[i]
+					rec.fields[name] = v
+				}
+				rec.fields[pos] = v
+				rec.names = append(rec.names, name)
+				rec.row = append(rec.row, v)
+			}
+			if !fn(rec) {
+				return nil
+			}
+		}
+	}
+
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	emit := func(v any) bool {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			obj = map[string]any{"_1": v}
+		}
+		rec := &selectRecord{fields: obj, names: slices.Sorted(maps.Keys(obj))}
+		for _, k := range rec.names {
+			rec.row = append(rec.row, obj[k])
+		}
+		return fn(rec)
+	}
+	for {
+		var v any
+		err := dec.Decode(&v)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// A document holding an array is queried element by element, like
+		// FROM s3object[*].
+		if arr, ok := v.([]any); ok && strings.EqualFold(input.jsonType, "document") {
+			for _, item := range arr {
+				if !emit(item) {
+					return nil
+				}
+			}
+			continue
+		}
+		if !emit(v) {
+			return nil
+		}
+	}
+}
+
+// selectLocal runs the query against a local file with the built-in
+// evaluator.
+func selectLocal(path string, input *queryCmdInput, w io.Writer) error {
+	if strings.EqualFold(input.inputFormat, "parquet") {
+		return errors.New("parquet files can only be queried on S3")
+	}
+	q, err := parseSelect(input.sql)
+	if err != nil {
+		return fmt.Errorf("unsupported query: %w", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = bufio.NewReader(f)
+	switch strings.ToLower(input.compression) {
+	case "gzip":
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	case "bzip2":
+		r = bzip2.NewReader(r)
+	}
+
+	sw := newSelectWriter(w, input)
+	returned := 0
+	var writeErr error
+	err = selectRecords(r, input, func(rec *selectRecord) bool {
+		if q.limit >= 0 && returned >= q.limit {
+			return false
+		}
+		if !q.matches(rec) {
+			return true
+		}
+		returned++
+		writeErr = sw.write(q, rec)
+		return writeErr == nil
+	})
+	return errors.Join(err, writeErr, sw.flush())
+}
+
+func query(input *queryCmdInput) {
+	if err := resolveQueryFormats(input); err != nil {
+		log.Fatalf("Invalid input: %v", err)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	if !strings.HasPrefix(input.source, "s3://") {
+		if err := selectLocal(input.source, input, out); err != nil {
+			out.Flush()
+			log.Fatalf("Query failed: %v", err)
+		}
+		return
+	}
+
+	bucket, key, err := cloud.ParseS3URI(input.source)
+	if err != nil || key == "" || strings.HasSuffix(key, "/") {
+		log.Fatalf("Invalid source %s, expected s3://bucket/key", input.source)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeoutCause(ctx, time.Duration(input.timeout)*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+	cc := cloud.New(cfg)
+
+	s3client, err := cc.BucketClient(ctx, bucket)
+	if err != nil {
+		log.Fatalf("Unable to locate bucket %s: %v", bucket, err)
+	}
+
+	stats, err := selectObject(ctx, s3client, bucket, key, input, out)
+	if err != nil {
+		out.Flush()
+		log.Fatalf("Query failed: %v", err)
+	}
+	if input.stats && stats != nil {
+		out.Flush()
+		log.Printf("Scanned %s, processed %s, returned %s",
+			formatBytes(aws.ToInt64(stats.BytesScanned)),
+			formatBytes(aws.ToInt64(stats.BytesProcessed)),
+			formatBytes(aws.ToInt64(stats.BytesReturned)))
+	}
+}
+
+func init() {
+	queryCmd.Flags().String("input-format", "", "Format of the object: csv, json or parquet (default guessed from the name)")
+	queryCmd.Flags().String("compression", "", "Compression of the object: none, gzip or bzip2 (default guessed from the name)")
+	queryCmd.Flags().String("csv-header", "use", "First line of CSV input: use (as column names), ignore or none")
+	queryCmd.Flags().String("delimiter", ",", "Field delimiter of CSV input and output")
+	queryCmd.Flags().String("json-type", "lines", "Layout of JSON input: lines or document")
+	queryCmd.Flags().String("output-format", "", "Format of the records: csv or json (default csv for CSV input, json otherwise)")
+	queryCmd.Flags().Bool("stats", false, "Print the bytes scanned, processed and returned")
+	queryCmd.Flags().IntP("timeout", "t", 300, "Timeout in seconds")
+	rootCmd.AddCommand(queryCmd)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+var (
+	allRegions        bool
+	regionList        []string
+	regionConcurrency int
+)
+
+// resolveRegions returns the regions a command should fan out to, or nil if
+// neither --regions nor --all-regions was given.
+func resolveRegions(ctx context.Context, cfg aws.Config) ([]string, error) {
+	if len(regionList) > 0 {
+		regions := make([]string, 0, len(regionList))
+		for _, r := range regionList {
+			if r = strings.TrimSpace(r); r != "" {
+				regions = append(regions, r)
+			}
+		}
+		return regions, nil
+	}
+	if allRegions {
+		return cloud.New(cfg).DiscoverRegions(ctx)
+	}
+	return nil, nil
+}
+
+// regionCommands are the commands that fan out to the regions given with
+// --regions or --all-regions. They are the ones that start from the buckets of
+// the account. The others, find and diff included, are given a bucket, which
+// lives in a single region that is looked up, so there is nothing to fan out;
+// they refuse the flags rather than ignore them.
+var regionCommands = map[*cobra.Command]bool{
+	listCmd:         true,
+	costEstimateCmd: true,
+}
+
+// checkRegionFlags fails if a region flag is given to a command that doesn't
+// fan out to regions.
+func checkRegionFlags(cmd *cobra.Command) error {
+	if regionCommands[cmd] {
+		return nil
+	}
+	for _, name := range []string{"regions", "all-regions", "region-concurrency"} {
+		if cmd.Flags().Changed(name) {
+			return fmt.Errorf("%s looks up the region of every bucket it is given, --%s only applies to list and cost estimate", cmd.CommandPath(), name)
+		}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&allRegions, "all-regions", false, "Run list and cost estimate against every enabled region; commands given a bucket use its region")
+	rootCmd.PersistentFlags().StringSliceVar(&regionList, "regions", nil, "Comma separated list of regions to run list and cost estimate against; commands given a bucket use its region")
+	rootCmd.PersistentFlags().IntVar(&regionConcurrency, "region-concurrency", 4, "Maximum number of regions queried at once")
+}
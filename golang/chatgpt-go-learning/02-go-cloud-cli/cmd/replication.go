@@ -0,0 +1,182 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+type replicationCmdInput struct {
+	source       string
+	destination  string
+	role         string
+	ruleID       string
+	prefix       string
+	storageClass string
+	sample       int
+	yes          bool
+}
+
+// replicationCmd represents the replication command
+var replicationCmd = &cobra.Command{
+	Use:   "replication",
+	Short: "Configure and inspect bucket replication",
+}
+
+// replicationSetupCmd represents the replication setup command
+var replicationSetupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Replicate a bucket into another bucket",
+	Long: `Checks that both buckets exist and have versioning enabled, enables versioning
+where it is missing after asking for confirmation, and adds a replication rule
+to the source bucket. Existing rules are kept. Rules written in the older
+prefix-only form are converted to filters, after asking, since S3 doesn't
+accept a configuration that mixes both.
+
+Only objects written after the rule is in place are replicated. The replication
+status of a sample of objects is printed at the end; objects that were already
+in the bucket show as NOT REPLICATED and stay that way.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		source, _ := cmd.Flags().GetString("source")
+		destination, _ := cmd.Flags().GetString("dest")
+		role, _ := cmd.Flags().GetString("role")
+		ruleID, _ := cmd.Flags().GetString("rule-id")
+		prefix, _ := cmd.Flags().GetString("prefix")
+		storageClass, _ := cmd.Flags().GetString("storage-class")
+		sample, _ := cmd.Flags().GetInt("sample")
+		yes, _ := cmd.Flags().GetBool("yes")
+		setupReplication(&replicationCmdInput{source, destination, role, ruleID, prefix, storageClass, sample, yes})
+	},
+}
+
+// replicationStatusCmd represents the replication status command
+var replicationStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the replication status of a sample of objects",
+	Run: func(cmd *cobra.Command, args []string) {
+		source, _ := cmd.Flags().GetString("source")
+		prefix, _ := cmd.Flags().GetString("prefix")
+		sample, _ := cmd.Flags().GetInt("sample")
+		showReplicationStatus(&replicationCmdInput{source: source, prefix: prefix, sample: sample})
+	},
+}
+
+func setupReplication(input *replicationCmdInput) {
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 60*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+	cc := cloud.New(cfg)
+
+	plan, err := cc.PlanReplication(ctx, input.source, input.destination, &cloud.ReplicationOptions{
+		Role:         input.role,
+		RuleID:       input.ruleID,
+		Prefix:       input.prefix,
+		StorageClass: input.storageClass,
+	})
+	if err != nil {
+		log.Fatalf("Unable to set up replication: %v", err)
+	}
+	if plan.SourceRegion == plan.DestinationRegion {
+		log.Printf("Note: both buckets are in %s, this sets up same-region replication", plan.SourceRegion)
+	}
+
+	if !input.yes {
+		for _, bucket := range plan.Unversioned {
+			if !confirm("Versioning is not enabled on %s, which replication requires. Enable it? It cannot be turned off again, only suspended.", bucket) {
+				log.Fatalf("Aborted: versioning is not enabled on %s", bucket)
+			}
+		}
+		if plan.ReplacedRole != "" && !confirm("%s replicates with role %s; switch every rule to %s?", input.source, plan.ReplacedRole, input.role) {
+			log.Fatalf("Aborted")
+		}
+		if plan.PrefixOnly > 0 && !confirm("%s has %d replication rules without a filter, which can't be mixed with the new rule. Convert them to filters, replicating delete markers as before?", input.source, plan.PrefixOnly) {
+			log.Fatalf("Aborted: S3 rejects a replication configuration that mixes prefix-only rules with filtered ones")
+		}
+	}
+
+	if err := cc.ApplyReplication(ctx, plan); err != nil {
+		log.Fatalf("Failed to set up replication: %v", err)
+	}
+	for _, bucket := range plan.Unversioned {
+		log.Printf("Enabled versioning on %s", bucket)
+	}
+
+	fmt.Printf("Replication rule %s from %s to %s is in place\n", plan.RuleID, input.source, input.destination)
+	if input.sample > 0 {
+		log.Printf("Objects already in %s are not replicated and show as NOT REPLICATED; check new writes with replication status", input.source)
+	}
+
+	samples, err := cc.ReplicationStatus(ctx, input.source, input.prefix, input.sample)
+	if err != nil {
+		log.Fatalf("Failed to sample replication status: %v", err)
+	}
+	printReplicationSamples(samples)
+}
+
+func printReplicationSamples(samples []cloud.ReplicationSample) {
+	if jsonOutput(false) {
+		jsonData, _ := json.MarshalIndent(samples, "", "  ")
+		fmt.Println(string(jsonData))
+		return
+	}
+	if len(samples) == 0 {
+		fmt.Println("No objects to sample")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tREPLICATION STATUS")
+	for _, s := range samples {
+		fmt.Fprintf(w, "%s\t%s\n", s.Key, s.Status)
+	}
+	w.Flush()
+}
+
+func showReplicationStatus(input *replicationCmdInput) {
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 60*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+
+	samples, err := cloud.New(cfg).ReplicationStatus(ctx, input.source, input.prefix, input.sample)
+	if err != nil {
+		log.Fatalf("Failed to sample replication status: %v", err)
+	}
+	printReplicationSamples(samples)
+}
+
+func init() {
+	replicationCmd.PersistentFlags().String("source", "", "Bucket to replicate from")
+	replicationCmd.MarkPersistentFlagRequired("source")
+	replicationCmd.PersistentFlags().String("prefix", "", "Only replicate, or sample, keys with this prefix")
+	replicationCmd.PersistentFlags().Int("sample", 10, "Number of objects to show the replication status of")
+
+	replicationSetupCmd.Flags().String("dest", "", "Bucket to replicate to")
+	replicationSetupCmd.MarkFlagRequired("dest")
+	replicationSetupCmd.Flags().String("role", "", "ARN of the IAM role S3 assumes to replicate objects")
+	replicationSetupCmd.MarkFlagRequired("role")
+	replicationSetupCmd.Flags().String("rule-id", "", "Id of the replication rule (default replicate-to-<dest>)")
+	replicationSetupCmd.Flags().String("storage-class", "", "Storage class of the replicas")
+	replicationSetupCmd.Flags().BoolP("yes", "y", false, "Don't ask for confirmation before enabling versioning or converting rules")
+
+	replicationCmd.AddCommand(replicationSetupCmd, replicationStatusCmd)
+	rootCmd.AddCommand(replicationCmd)
+}
@@ -0,0 +1,418 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+type restoreCmdInput struct {
+	uris        []string
+	days        int
+	tier        string
+	wait        bool
+	interval    time.Duration
+	download    string
+	concurrency int
+	timeout     int
+}
+
+// restoreObject is an object and its restore state. Objects in other storage
+// classes are available right away.
+type restoreObject struct {
+	Bucket       string
+	Key          string
+	StorageClass string
+	State        string
+	Expiry       *time.Time `json:",omitempty"`
+	Error        string     `json:",omitempty"`
+	Size         int64      `json:"-"`
+}
+
+// restoreCmd represents the restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore archived objects from Glacier and Deep Archive",
+	Long: `Objects in the GLACIER and DEEP_ARCHIVE storage classes cannot be downloaded
+until a temporary copy has been restored. A restore takes minutes to hours
+depending on the tier.
+
+Both subcommands take objects as s3://bucket/key, or s3://bucket/prefix/ to
+cover every archived object under a prefix.`,
+}
+
+// restoreRequestCmd represents the restore request command
+var restoreRequestCmd = &cobra.Command{
+	Use:   "request s3://bucket/key-or-prefix...",
+	Short: "Request temporary copies of archived objects",
+	Long: `Requests a temporary copy of every archived object given. Objects that are
+already being restored are left alone, and requesting an object that is already
+restored extends its copy to --days from now.
+
+Tiers trade speed for cost: Expedited takes minutes and isn't available for
+DEEP_ARCHIVE, Standard takes hours and Bulk is the cheapest and slowest.
+
+With --wait the command polls until every copy is ready, and --download then
+downloads them, keeping their keys as paths under the directory.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		requestRestore(restoreFlags(cmd, args))
+	},
+}
+
+// restoreStatusCmd represents the restore status command
+var restoreStatusCmd = &cobra.Command{
+	Use:   "status s3://bucket/key-or-prefix...",
+	Short: "Show the restore state of archived objects",
+	Long: `Prints the restore state of every object given: archived, in-progress,
+restored (with the date the copy expires) or available for objects that were
+never archived. With --wait the command polls until no restore is in progress.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		showRestoreStatus(restoreFlags(cmd, args))
+	},
+}
+
+func restoreFlags(cmd *cobra.Command, args []string) *restoreCmdInput {
+	days, _ := cmd.Flags().GetInt("days")
+	tier, _ := cmd.Flags().GetString("tier")
+	wait, _ := cmd.Flags().GetBool("wait")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	download, _ := cmd.Flags().GetString("download")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	timeout, _ := cmd.Flags().GetInt("timeout")
+	return &restoreCmdInput{args, days, tier, wait, interval, download, concurrency, timeout}
+}
+
+// resolveRestoreTargets expands the arguments into objects. Prefixes only
+// cover archived objects, named keys are taken as they are.
+func resolveRestoreTargets(ctx context.Context, cc *cloud.Client, uris []string, concurrency int) ([]*restoreObject, error) {
+	var objects []*restoreObject
+	seen := map[string]bool{}
+	add := func(bucket, key string) {
+		if !seen[bucket+"/"+key] {
+			seen[bucket+"/"+key] = true
+			objects = append(objects, &restoreObject{Bucket: bucket, Key: key})
+		}
+	}
+
+	for _, uri := range uris {
+		bucket, prefix, err := cloud.ParseS3URI(uri)
+		if err != nil {
+			return nil, err
+		}
+		if prefix != "" && !strings.HasSuffix(prefix, "/") {
+			add(bucket, prefix)
+			continue
+		}
+		listed, err := cc.ListObjects(ctx, bucket, prefix, &cloud.ListObjectsOptions{Concurrency: concurrency})
+		if err != nil {
+			return nil, fmt.Errorf("unable to list %s: %w", uri, err)
+		}
+		for _, o := range listed {
+			if slices.Contains(cloud.ArchivedClasses, types.StorageClass(o.StorageClass)) {
+				add(bucket, aws.ToString(o.Key))
+			}
+		}
+	}
+	return objects, nil
+}
+
+// forEachObject runs fn for every object on a pool of concurrency workers and
+// returns how many failed. The error of a failed object is kept on it.
+func forEachObject(ctx context.Context, objects []*restoreObject, concurrency int, fn func(context.Context, *restoreObject) error) int {
+	queue := make(chan *restoreObject)
+	var mu sync.Mutex
+	failed := 0
+
+	var wg sync.WaitGroup
+	for range max(concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for o := range queue {
+				err := fn(ctx, o)
+				o.Error = ""
+				if err != nil {
+					o.Error = err.Error()
+					mu.Lock()
+					failed++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, o := range objects {
+		queue <- o
+	}
+	close(queue)
+	wg.Wait()
+
+	return failed
+}
+
+// refreshRestoreState reads the storage class and restore state of an object.
+func refreshRestoreState(ctx context.Context, cc *cloud.Client, o *restoreObject) error {
+	state, err := cc.ObjectRestoreState(ctx, o.Bucket, o.Key)
+	if err != nil {
+		return err
+	}
+	o.StorageClass, o.Size, o.State, o.Expiry = state.StorageClass, state.Size, state.State, state.Expiry
+	return nil
+}
+
+// refreshRestoreStates refreshes every object and logs the ones that failed.
+func refreshRestoreStates(ctx context.Context, cc *cloud.Client, objects []*restoreObject, input *restoreCmdInput) {
+	ctx, cancel := context.WithTimeoutCause(ctx, time.Duration(input.timeout)*time.Second, errors.New("Timeout"))
+	defer cancel()
+	forEachObject(ctx, objects, input.concurrency, func(ctx context.Context, o *restoreObject) error {
+		return refreshRestoreState(ctx, cc, o)
+	})
+	for _, o := range objects {
+		if o.Error != "" {
+			log.Printf("Unable to read s3://%s/%s: %s", o.Bucket, o.Key, o.Error)
+		}
+	}
+}
+
+// countRestoreStates counts the objects in each state. Objects that couldn't
+// be read are counted under "error".
+func countRestoreStates(objects []*restoreObject) map[string]int {
+	counts := map[string]int{}
+	for _, o := range objects {
+		if o.Error != "" {
+			counts["error"]++
+		} else {
+			counts[o.State]++
+		}
+	}
+	return counts
+}
+
+// waitForRestores polls the objects until none of them is being restored
+// any more, or the user interrupts.
+func waitForRestores(ctx context.Context, cc *cloud.Client, objects []*restoreObject, input *restoreCmdInput) error {
+	ticker := time.NewTicker(input.interval)
+	defer ticker.Stop()
+
+	for {
+		counts := countRestoreStates(objects)
+		if counts[cloud.RestoreInProgress] == 0 {
+			return nil
+		}
+		log.Printf("%d of %d objects still being restored, checking again in %s",
+			counts[cloud.RestoreInProgress], len(objects), input.interval)
+
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-ticker.C:
+		}
+		refreshRestoreStates(ctx, cc, objects, input)
+	}
+}
+
+// downloadRestored downloads the objects that can be read, keeping their keys
+// as paths under dir.
+func downloadRestored(ctx context.Context, cc *cloud.Client, objects []*restoreObject, input *restoreCmdInput) {
+	var jobs []cloud.Transfer
+	for _, o := range objects {
+		if o.Error == "" && (o.State == cloud.RestoreRestored || o.State == cloud.RestoreAvailable) {
+			path, err := cloud.LocalPath(input.download, o.Key)
+			if err != nil {
+				log.Fatalf("Unable to download restored objects: %v", err)
+			}
+			jobs = append(jobs, cloud.Transfer{
+				Bucket: o.Bucket,
+				Key:    o.Key,
+				Path:   path,
+				Size:   o.Size,
+			})
+		}
+	}
+	if len(jobs) < len(objects) {
+		log.Printf("Skipping %d objects that are not restored", len(objects)-len(jobs))
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, time.Duration(input.timeout)*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	status := runTransfers(ctx, jobs, input.concurrency, func(ctx context.Context, job cloud.Transfer, progress *fileProgress) error {
+		return downloadFile(ctx, cc, job, &cloud.DownloadOptions{Progress: progress})
+	})
+	// The restore status is the output of the command.
+	reportTransfers(ctx, status, "downloads", false)
+	log.Printf("Downloaded %d objects to %s", len(jobs), input.download)
+}
+
+// prepareRestore validates the flags and resolves the objects to work on.
+func prepareRestore(ctx context.Context, input *restoreCmdInput) (*cloud.Client, []*restoreObject) {
+	if input.wait && input.interval < time.Second {
+		log.Fatalf("Invalid --interval %s, it must be at least 1s", input.interval)
+	}
+	if input.download != "" && !input.wait {
+		log.Fatalf("--download needs --wait")
+	}
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+	cc := cloud.New(cfg)
+
+	listCtx, cancel := context.WithTimeoutCause(ctx, time.Duration(input.timeout)*time.Second, errors.New("Timeout"))
+	defer cancel()
+	objects, err := resolveRestoreTargets(listCtx, cc, input.uris, input.concurrency)
+	if err != nil {
+		log.Fatalf("Invalid location: %v", err)
+	}
+	if len(objects) == 0 {
+		log.Fatalf("No archived objects found")
+	}
+	// Check the keys before restoring anything rather than after the wait.
+	if input.download != "" {
+		for _, o := range objects {
+			if _, err := cloud.LocalPath(input.download, o.Key); err != nil {
+				log.Fatalf("Unable to download restored objects: %v", err)
+			}
+		}
+	}
+
+	refreshRestoreStates(ctx, cc, objects, input)
+	return cc, objects
+}
+
+// finishRestore waits for the restores and downloads the objects, when
+// asked to.
+func finishRestore(ctx context.Context, cc *cloud.Client, objects []*restoreObject, input *restoreCmdInput) {
+	if !input.wait {
+		return
+	}
+	if err := waitForRestores(ctx, cc, objects, input); err != nil {
+		log.Fatalf("Stopped waiting: %v", err)
+	}
+	counts := countRestoreStates(objects)
+	log.Printf("%d restored, %d available, %d archived, %d unreadable",
+		counts[cloud.RestoreRestored], counts[cloud.RestoreAvailable], counts[cloud.RestoreArchived], counts["error"])
+	if input.download != "" {
+		downloadRestored(ctx, cc, objects, input)
+	}
+}
+
+func requestRestore(input *restoreCmdInput) {
+	tier, err := cloud.RestoreTier(input.tier)
+	if err != nil {
+		log.Fatalf("Invalid --tier: %v", err)
+	}
+	if input.days < 1 {
+		log.Fatalf("Invalid --days %d, it must be at least 1", input.days)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	cc, objects := prepareRestore(ctx, input)
+
+	var pending []*restoreObject
+	for _, o := range objects {
+		switch {
+		case o.Error != "":
+		case o.State == cloud.RestoreAvailable:
+			log.Printf("s3://%s/%s is in %s and needs no restore", o.Bucket, o.Key, o.StorageClass)
+		case o.State == cloud.RestoreInProgress:
+			log.Printf("s3://%s/%s is already being restored", o.Bucket, o.Key)
+		default:
+			pending = append(pending, o)
+		}
+	}
+
+	requestCtx, cancel := context.WithTimeoutCause(ctx, time.Duration(input.timeout)*time.Second, errors.New("Timeout"))
+	defer cancel()
+	failed := forEachObject(requestCtx, pending, input.concurrency, func(ctx context.Context, o *restoreObject) error {
+		err := cc.RestoreObject(ctx, o.Bucket, o.Key, &cloud.RestoreObjectOptions{Days: input.days, Tier: tier})
+		if err != nil {
+			return err
+		}
+		o.State = cloud.RestoreInProgress
+		if o.Expiry != nil {
+			// S3 extends the existing copy without restoring it again.
+			o.State = cloud.RestoreRestored
+		}
+		return nil
+	})
+	for _, o := range pending {
+		if o.Error != "" {
+			log.Printf("Unable to restore s3://%s/%s: %s", o.Bucket, o.Key, o.Error)
+		}
+	}
+	if len(pending) > 0 {
+		log.Printf("Requested %d of %d restores for %d days with the %s tier", len(pending)-failed, len(pending), input.days, tier)
+	}
+
+	finishRestore(ctx, cc, objects, input)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func showRestoreStatus(input *restoreCmdInput) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	cc, objects := prepareRestore(ctx, input)
+	finishRestore(ctx, cc, objects, input)
+
+	if jsonOutput(false) {
+		jsonData, _ := json.MarshalIndent(objects, "", "  ")
+		fmt.Println(string(jsonData))
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BUCKET\tKEY\tCLASS\tSTATE\tEXPIRY")
+	for _, o := range objects {
+		state, expiry := o.State, ""
+		if o.Error != "" {
+			state = "error (" + o.Error + ")"
+		}
+		if o.Expiry != nil {
+			expiry = o.Expiry.Local().Format(time.DateTime)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", o.Bucket, o.Key, o.StorageClass, state, expiry)
+	}
+	w.Flush()
+}
+
+func init() {
+	for _, c := range []*cobra.Command{restoreRequestCmd, restoreStatusCmd} {
+		c.Flags().Bool("wait", false, "Wait until no restore is in progress")
+		c.Flags().Duration("interval", 5*time.Minute, "Time between checks while waiting")
+		c.Flags().String("download", "", "Download the restored objects into this directory once ready, needs --wait")
+		c.Flags().Int("concurrency", 8, "Number of objects handled at once")
+		c.Flags().IntP("timeout", "t", 600, "Timeout of each step in seconds")
+	}
+	restoreRequestCmd.Flags().Int("days", 7, "Number of days the restored copies are kept")
+	restoreRequestCmd.Flags().String("tier", "Standard", "Retrieval tier: Expedited, Standard or Bulk")
+	restoreCmd.AddCommand(restoreRequestCmd)
+	restoreCmd.AddCommand(restoreStatusCmd)
+	rootCmd.AddCommand(restoreCmd)
+}
@@ -0,0 +1,596 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// This file evaluates the subset of S3 Select SQL that the query command
+// supports on local files:
+//
+//	SELECT * | expr [AS name], ... FROM s3object[[*]] [[AS] alias]
+//	[WHERE condition] [LIMIT n]
+//
+// Expressions are column references such as s.status, s._1 or s.a.b for
+// nested JSON, string and number literals, TRUE, FALSE and NULL. Conditions
+// combine comparisons (=, !=, <>, <, <=, >, >=), [NOT] LIKE, IS [NOT] NULL,
+// AND, OR, NOT and parentheses. Unlike S3 Select, values that both look like
+// numbers compare as numbers without a CAST.
+
+// selectRecord is a row of the input. fields maps column names, and _1, _2...
+// for CSV, to values; row keeps the columns of a CSV row in order for
+// SELECT *.
+type selectRecord struct {
+	fields map[string]any
+	names  []string
+	row    []any
+}
+
+// field looks up a column, falling back to a case-insensitive match for
+// unquoted names the way S3 Select does.
+func (r *selectRecord) field(name string, quoted bool) (any, bool) {
+	if v, ok := r.fields[name]; ok {
+		return v, true
+	}
+	if !quoted {
+		for k, v := range r.fields {
+			if strings.EqualFold(k, name) {
+				return v, true
+			}
+		}
+	}
+	return nil, false
+}
+
+type selectExpr interface {
+	eval(r *selectRecord) any
+}
+
+type selectLiteral struct{ value any }
+
+func (e selectLiteral) eval(*selectRecord) any { return e.value }
+
+type selectColumnRef struct {
+	path   []string
+	quoted bool
+}
+
+func (e selectColumnRef) eval(r *selectRecord) any {
+	v, ok := r.field(e.path[0], e.quoted)
+	if !ok {
+		return nil
+	}
+	for _, name := range e.path[1:] {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[name]
+	}
+	return v
+}
+
+type selectCompare struct {
+	op          string
+	left, right selectExpr
+}
+
+func (e selectCompare) eval(r *selectRecord) any {
+	l, rv := e.left.eval(r), e.right.eval(r)
+	if l == nil || rv == nil {
+		return nil
+	}
+	c, ok := compareValues(l, rv)
+	if !ok {
+		return nil
+	}
+	switch e.op {
+	case "=":
+		return c == 0
+	case "!=", "<>":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	default:
+		return c >= 0
+	}
+}
+
+type selectLogical struct {
+	op          string
+	left, right selectExpr
+}
+
+// eval follows SQL three-valued logic: NULL is unknown.
+func (e selectLogical) eval(r *selectRecord) any {
+	l, lok := e.left.eval(r).(bool)
+	if e.op == "AND" && lok && !l {
+		return false
+	}
+	if e.op == "OR" && lok && l {
+		return true
+	}
+	rv, rok := e.right.eval(r).(bool)
+	if !lok || !rok {
+		if e.op == "AND" && rok && !rv || e.op == "OR" && rok && rv {
+			return rv
+		}
+		return nil
+	}
+	if e.op == "AND" {
+		return l && rv
+	}
+	return l || rv
+}
+
+type selectNot struct{ expr selectExpr }
+
+func (e selectNot) eval(r *selectRecord) any {
+	if b, ok := e.expr.eval(r).(bool); ok {
+		return !b
+	}
+	return nil
+}
+
+type selectLike struct {
+	expr    selectExpr
+	pattern *regexp.Regexp
+	not     bool
+}
+
+func (e selectLike) eval(r *selectRecord) any {
+	v := e.expr.eval(r)
+	if v == nil {
+		return nil
+	}
+	return e.pattern.MatchString(formatSelectValue(v)) != e.not
+}
+
+type selectIsNull struct {
+	expr selectExpr
+	not  bool
+}
+
+func (e selectIsNull) eval(r *selectRecord) any {
+	return (e.expr.eval(r) == nil) != e.not
+}
+
+// selectNumber returns v as a number, if it is one or is a string holding one.
+func selectNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// compareValues orders two non-NULL values: numerically if both are numbers,
+// as strings otherwise. Booleans only compare with booleans.
+func compareValues(a, b any) (int, bool) {
+	if x, ok := selectNumber(a); ok {
+		if y, ok := selectNumber(b); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	ab, aIsBool := a.(bool)
+	bb, bIsBool := b.(bool)
+	if aIsBool || bIsBool {
+		if aIsBool && bIsBool && ab == bb {
+			return 0, true
+		}
+		return 1, aIsBool && bIsBool
+	}
+	return strings.Compare(formatSelectValue(a), formatSelectValue(b)), true
+}
+
+// formatSelectValue renders a value the way it appears in CSV output.
+func formatSelectValue(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case map[string]any, []any:
+		data, _ := json.Marshal(x)
+		return string(data)
+	}
+	return fmt.Sprint(v)
+}
+
+// selectColumn is a projected column and the name it has in JSON output.
+type selectColumn struct {
+	name string
+	expr selectExpr
+}
+
+// selectQuery is a parsed query. columns is nil for SELECT *, where is nil
+// without a WHERE clause and limit is -1 without a LIMIT.
+type selectQuery struct {
+	columns []selectColumn
+	where   selectExpr
+	limit   int
+}
+
+// matches reports whether a record passes the WHERE clause.
+func (q *selectQuery) matches(r *selectRecord) bool {
+	if q.where == nil {
+		return true
+	}
+	b, _ := q.where.eval(r).(bool)
+	return b
+}
+
+type selectToken struct {
+	kind string // ident, quoted, string, number or op
+	text string
+}
+
+// tokenizeSelect splits a query into tokens.
+func tokenizeSelect(sql string) ([]selectToken, error) {
+	var tokens []selectToken
+	runes := []rune(sql)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '\'' || c == '"':
+			var sb strings.Builder
+			j := i + 1
+			for ; j < len(runes); j++ {
+				if runes[j] == c {
+					// A doubled quote is an escaped quote.
+					if j+1 < len(runes) && runes[j+1] == c {
+						sb.WriteRune(c)
+						j++
+						continue
+					}
+					break
+				}
+				sb.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated %c at offset %d", c, i)
+			}
+			kind := "string"
+			if c == '"' {
+				kind = "quoted"
+			}
+			tokens = append(tokens, selectToken{kind, sb.String()})
+			i = j + 1
+		case unicode.IsDigit(c) || c == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1]):
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.' || runes[j] == 'e' || runes[j] == 'E') {
+				j++
+			}
+			tokens = append(tokens, selectToken{"number", string(runes[i:j])})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, selectToken{"ident", string(runes[i:j])})
+			i = j
+		default:
+			op := string(c)
+			if i+1 < len(runes) {
+				if two := string(runes[i : i+2]); two == "<=" || two == ">=" || two == "!=" || two == "<>" {
+					op = two
+				}
+			}
+			if !strings.Contains("=<>!(),.*[];", op[:1]) || op == "!" {
+				return nil, fmt.Errorf("unexpected %q at offset %d", c, i)
+			}
+			tokens = append(tokens, selectToken{"op", op})
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
+
+// selectParser is a recursive descent parser over the tokens of a query.
+type selectParser struct {
+	tokens []selectToken
+	pos    int
+	alias  string
+}
+
+func (p *selectParser) peek() selectToken {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return selectToken{}
+}
+
+// keyword consumes the next token if it is the given keyword.
+func (p *selectParser) keyword(kw string) bool {
+	if t := p.peek(); t.kind == "ident" && strings.EqualFold(t.text, kw) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// op consumes the next token if it is the given operator.
+func (p *selectParser) op(op string) bool {
+	if t := p.peek(); t.kind == "op" && t.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *selectParser) errorf(format string, args ...any) error {
+	near := "end of query"
+	if t := p.peek(); t.kind != "" {
+		near = strconv.Quote(t.text)
+	}
+	return fmt.Errorf(format+" near %s", append(args, near)...)
+}
+
+// selectKeywords can't be used as aliases or column names without quotes.
+var selectKeywords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "LIMIT": true, "AS": true, "AND": true,
+	"OR": true, "NOT": true, "LIKE": true, "IS": true, "NULL": true, "TRUE": true, "FALSE": true,
+}
+
+var selectComparisons = map[string]bool{"=": true, "!=": true, "<>": true, "<": true, "<=": true, ">": true, ">=": true}
+
+// parseSelect parses a query of the supported subset.
+func parseSelect(sql string) (*selectQuery, error) {
+	tokens, err := tokenizeSelect(sql)
+	if err != nil {
+		return nil, err
+	}
+	p := &selectParser{tokens: tokens}
+	q := &selectQuery{limit: -1}
+
+	if !p.keyword("SELECT") {
+		return nil, p.errorf("expected SELECT")
+	}
+
+	// The projection refers to the alias declared after it, so it is parsed
+	// once FROM has been read.
+	start := p.pos
+	for depth := 0; p.pos < len(p.tokens); p.pos++ {
+		t := p.peek()
+		if t.kind == "op" && t.text == "(" {
+			depth++
+		} else if t.kind == "op" && t.text == ")" {
+			depth--
+		} else if depth == 0 && t.kind == "ident" && strings.EqualFold(t.text, "FROM") {
+			break
+		}
+	}
+	end := p.pos
+
+	if !p.keyword("FROM") {
+		return nil, p.errorf("expected FROM")
+	}
+	if t := p.peek(); t.kind != "ident" || !strings.EqualFold(t.text, "s3object") {
+		return nil, p.errorf("expected s3object")
+	}
+	p.pos++
+	if p.op("[") {
+		if !p.op("*") || !p.op("]") {
+			return nil, p.errorf("expected [*]")
+		}
+	}
+	p.keyword("AS")
+	if t := p.peek(); (t.kind == "ident" && !selectKeywords[strings.ToUpper(t.text)]) || t.kind == "quoted" {
+		p.alias = t.text
+		p.pos++
+	}
+
+	if p.keyword("WHERE") {
+		if q.where, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.keyword("LIMIT") {
+		t := p.peek()
+		n, err := strconv.Atoi(t.text)
+		if t.kind != "number" || err != nil || n < 0 {
+			return nil, p.errorf("expected a row count after LIMIT")
+		}
+		q.limit = n
+		p.pos++
+	}
+	p.op(";")
+	if p.pos != len(p.tokens) {
+		return nil, p.errorf("unexpected input")
+	}
+
+	proj := &selectParser{tokens: p.tokens[start:end], alias: p.alias}
+	if proj.op("*") {
+		if proj.pos != len(proj.tokens) {
+			return nil, proj.errorf("expected FROM")
+		}
+		return q, nil
+	}
+	for {
+		expr, err := proj.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		col := selectColumn{name: "_" + strconv.Itoa(len(q.columns)+1), expr: expr}
+		if ref, ok := expr.(selectColumnRef); ok {
+			col.name = ref.path[len(ref.path)-1]
+		}
+		if proj.keyword("AS") {
+			t := proj.peek()
+			if t.kind != "ident" && t.kind != "quoted" {
+				return nil, proj.errorf("expected a name after AS")
+			}
+			col.name = t.text
+			proj.pos++
+		}
+		q.columns = append(q.columns, col)
+		if !proj.op(",") {
+			break
+		}
+	}
+	if proj.pos != len(proj.tokens) {
+		return nil, proj.errorf("expected FROM")
+	}
+	return q, nil
+}
+
+func (p *selectParser) parseOr() (selectExpr, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.keyword("OR") {
+		var right selectExpr
+		right, err = p.parseAnd()
+		left = selectLogical{"OR", left, right}
+	}
+	return left, err
+}
+
+func (p *selectParser) parseAnd() (selectExpr, error) {
+	left, err := p.parseNot()
+	for err == nil && p.keyword("AND") {
+		var right selectExpr
+		right, err = p.parseNot()
+		left = selectLogical{"AND", left, right}
+	}
+	return left, err
+}
+
+func (p *selectParser) parseNot() (selectExpr, error) {
+	if p.keyword("NOT") {
+		expr, err := p.parseNot()
+		return selectNot{expr}, err
+	}
+	return p.parseComparison()
+}
+
+func (p *selectParser) parseComparison() (selectExpr, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind == "op" && selectComparisons[t.text] {
+		p.pos++
+		right, err := p.parseOperand()
+		return selectCompare{t.text, left, right}, err
+	}
+
+	if p.keyword("IS") {
+		not := p.keyword("NOT")
+		if !p.keyword("NULL") {
+			return nil, p.errorf("expected NULL")
+		}
+		return selectIsNull{left, not}, nil
+	}
+
+	not := p.keyword("NOT")
+	if p.keyword("LIKE") {
+		t := p.peek()
+		if t.kind != "string" {
+			return nil, p.errorf("expected a string pattern after LIKE")
+		}
+		p.pos++
+		var sb strings.Builder
+		sb.WriteString("(?s)^")
+		for _, c := range t.text {
+			switch c {
+			case '%':
+				sb.WriteString(".*")
+			case '_':
+				sb.WriteString(".")
+			default:
+				sb.WriteString(regexp.QuoteMeta(string(c)))
+			}
+		}
+		sb.WriteString("$")
+		return selectLike{left, regexp.MustCompile(sb.String()), not}, nil
+	}
+	if not {
+		return nil, p.errorf("expected LIKE")
+	}
+	return left, nil
+}
+
+func (p *selectParser) parseOperand() (selectExpr, error) {
+	t := p.peek()
+	switch {
+	case t.kind == "op" && t.text == "(":
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.op(")") {
+			return nil, p.errorf("expected )")
+		}
+		return expr, nil
+	case t.kind == "string":
+		p.pos++
+		return selectLiteral{t.text}, nil
+	case t.kind == "number":
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number")
+		}
+		p.pos++
+		return selectLiteral{f}, nil
+	case t.kind == "ident" && strings.EqualFold(t.text, "NULL"):
+		p.pos++
+		return selectLiteral{nil}, nil
+	case t.kind == "ident" && (strings.EqualFold(t.text, "TRUE") || strings.EqualFold(t.text, "FALSE")):
+		p.pos++
+		return selectLiteral{strings.EqualFold(t.text, "TRUE")}, nil
+	case t.kind == "quoted" || t.kind == "ident" && !selectKeywords[strings.ToUpper(t.text)]:
+		return p.parseColumnRef()
+	}
+	return nil, p.errorf("expected a column or a value")
+}
+
+// parseColumnRef parses a dotted column reference, dropping the table alias.
+func (p *selectParser) parseColumnRef() (selectExpr, error) {
+	var path []string
+	var quoted []bool
+	for {
+		t := p.peek()
+		if t.kind != "ident" && t.kind != "quoted" {
+			return nil, p.errorf("expected a column name")
+		}
+		p.pos++
+		path = append(path, t.text)
+		quoted = append(quoted, t.kind == "quoted")
+		if !p.op(".") {
+			break
+		}
+	}
+	if len(path) > 1 && !quoted[0] && (strings.EqualFold(path[0], p.alias) || strings.EqualFold(path[0], "s3object")) {
+		path, quoted = path[1:], quoted[1:]
+	}
+	return selectColumnRef{path, quoted[0]}, nil
+}
@@ -0,0 +1,297 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseSelect(t *testing.T) {
+	tests := []struct {
+		sql     string
+		columns []string // nil for SELECT *
+		where   bool
+		limit   int
+		err     string
+	}{
+		{sql: "SELECT * FROM s3object", limit: -1},
+		{sql: "select * from S3Object[*] s;", limit: -1},
+		{sql: "SELECT s.name, s.size FROM s3object s", columns: []string{"name", "size"}, limit: -1},
+		{sql: "SELECT s.a.b, 'x', s._1 AS first FROM s3object AS s", columns: []string{"b", "_2", "first"}, limit: -1},
+		{sql: `SELECT "Name" AS "Full Name" FROM s3object`, columns: []string{"Full Name"}, limit: -1},
+		{sql: "SELECT * FROM s3object s WHERE s.status = 'ERR' LIMIT 5", where: true, limit: 5},
+		{sql: "SELECT * FROM s3object WHERE (a = 1 OR b = 2) AND NOT c LIKE 'x%'", where: true, limit: -1},
+		{sql: "SELECT * FROM s3object LIMIT 0", limit: 0},
+
+		{sql: "", err: "expected SELECT"},
+		{sql: "DELETE FROM s3object", err: "expected SELECT"},
+		{sql: "SELECT *", err: "expected FROM"},
+		{sql: "SELECT * FROM table1", err: "expected s3object"},
+		{sql: "SELECT * FROM s3object[1]", err: "expected [*]"},
+		{sql: "SELECT * FROM s3object LIMIT -1", err: "expected a row count"},
+		{sql: "SELECT * FROM s3object LIMIT x", err: "expected a row count"},
+		{sql: "SELECT * FROM s3object WHERE", err: "expected a column or a value"},
+		{sql: "SELECT * FROM s3object WHERE a = 'open", err: "unterminated"},
+		{sql: "SELECT * FROM s3object WHERE a LIKE b", err: "expected a string pattern"},
+		{sql: "SELECT * FROM s3object WHERE a IS 1", err: "expected NULL"},
+		{sql: "SELECT * FROM s3object WHERE a NOT = 1", err: "expected LIKE"},
+		{sql: "SELECT * FROM s3object WHERE (a = 1", err: "expected )"},
+		{sql: "SELECT * FROM s3object WHERE a = 1 b", err: "unexpected input"},
+		{sql: "SELECT a b FROM s3object", err: "expected FROM"},
+		{sql: "SELECT a AS FROM s3object", err: "expected a name after AS"},
+		{sql: "SELECT * FROM s3object WHERE a = 1 & b", err: "unexpected"},
+	}
+	for _, tt := range tests {
+		q, err := parseSelect(tt.sql)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("parseSelect(%q) error = %v, want %q", tt.sql, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseSelect(%q): %v", tt.sql, err)
+			continue
+		}
+		var columns []string
+		for _, c := range q.columns {
+			columns = append(columns, c.name)
+		}
+		if !slices.Equal(columns, tt.columns) || (q.columns == nil) != (tt.columns == nil) {
+			t.Errorf("parseSelect(%q) columns = %q, want %q", tt.sql, columns, tt.columns)
+		}
+		if (q.where != nil) != tt.where {
+			t.Errorf("parseSelect(%q) has WHERE = %v, want %v", tt.sql, q.where != nil, tt.where)
+		}
+		if q.limit != tt.limit {
+			t.Errorf("parseSelect(%q) limit = %d, want %d", tt.sql, q.limit, tt.limit)
+		}
+	}
+}
+
+func TestSelectWhere(t *testing.T) {
+	record := &selectRecord{fields: map[string]any{
+		"Name":   "report_2025.csv",
+		"status": "ERR",
+		"size":   "1500",
+		"count":  float64(9),
+		"empty":  "",
+		"null":   nil,
+		"active": true,
+		"meta":   map[string]any{"owner": "ops", "tags": map[string]any{"env": "prod"}},
+		"_1":     "first",
+	}}
+	tests := []struct {
+		where string
+		want  any // true, false or nil for unknown
+	}{
+		{"s.status = 'ERR'", true},
+		{"s.status <> 'ERR'", false},
+		{"s.status != 'OK'", true},
+		{"s.STATUS = 'ERR'", true},
+		{`s."STATUS" = 'ERR'`, nil},
+		{`s."status" = 'ERR'`, true},
+		{"s._1 = 'first'", true},
+
+		// Strings holding numbers compare as numbers.
+		{"s.size > 200", true},
+		{"s.size >= 1500", true},
+		{"s.size < 1500.5", true},
+		{"s.count <= 9", true},
+		{"s.count = '9'", true},
+		{"s.status > 'EAA'", true},
+		{"s.active = TRUE", true},
+		{"s.active = 'true'", nil},
+
+		{"s.meta.owner = 'ops'", true},
+		{"s.meta.tags.env = 'prod'", true},
+		{"s.meta.missing.env = 'prod'", nil},
+		{"s.status.x = 'ERR'", nil},
+
+		{"s.Name LIKE 'report%'", true},
+		{"s.Name LIKE '%.csv'", true},
+		{"s.Name LIKE 'report_2025.csv'", true},
+		{"s.Name LIKE 'report_____.csv'", true},
+		{"s.Name LIKE 'report____.csv'", false},
+		{"s.Name LIKE 'REPORT%'", false},
+		{"s.Name NOT LIKE '%.json'", true},
+		{"s.Name LIKE 'report.2025%'", false},
+		{"s.status LIKE 'E(R'", false},
+		{"s.empty LIKE '%'", true},
+		{"s.missing LIKE '%'", nil},
+		{"s.missing NOT LIKE '%'", nil},
+
+		// NULL and missing fields are unknown in comparisons.
+		{"s.missing = 'x'", nil},
+		{"s.missing != 'x'", nil},
+		{"s.null = NULL", nil},
+		{"s.missing IS NULL", true},
+		{"s.null IS NULL", true},
+		{"s.empty IS NULL", false},
+		{"s.empty IS NOT NULL", true},
+		{"s.missing IS NOT NULL", false},
+
+		// Three-valued logic.
+		{"NOT s.missing = 'x'", nil},
+		{"s.missing = 'x' AND s.status = 'OK'", false},
+		{"s.missing = 'x' AND s.status = 'ERR'", nil},
+		{"s.missing = 'x' OR s.status = 'ERR'", true},
+		{"s.missing = 'x' OR s.status = 'OK'", nil},
+		{"s.status = 'OK' OR s.size > 1000 AND s.count = 9", true},
+		{"(s.status = 'OK' OR s.size > 1000) AND s.count = 8", false},
+		{"NOT (s.status = 'OK')", true},
+	}
+	for _, tt := range tests {
+		q, err := parseSelect("SELECT * FROM s3object s WHERE " + tt.where)
+		if err != nil {
+			t.Errorf("%s: %v", tt.where, err)
+			continue
+		}
+		if got := q.where.eval(record); got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.where, got, tt.want)
+		}
+		if want, _ := tt.want.(bool); q.matches(record) != want {
+			t.Errorf("%s matches = %v, want %v", tt.where, !want, want)
+		}
+	}
+}
+
+func TestSelectLocal(t *testing.T) {
+	const csvInput = "name,status,size\napp.log,ERR,1500\ndb.log,OK,20\nweb.log,ERR,\n"
+	const jsonLines = `{"name":"app.log","status":"ERR","size":1500,"meta":{"owner":"ops"}}
+{"name":"db.log","status":"OK","size":20}
+{"name":"web.log","status":"ERR"}
+`
+	tests := []struct {
+		name  string
+		data  string
+		input queryCmdInput
+		sql   string
+		want  string
+	}{
+		{
+			name:  "csv with header",
+			data:  csvInput,
+			input: queryCmdInput{inputFormat: "csv", csvHeader: "use", outputFormat: "csv"},
+			sql:   "SELECT s.name, s.size FROM s3object s WHERE s.status = 'ERR'",
+			want:  "app.log,1500\nweb.log,\n",
+		},
+		{
+			name:  "csv select star keeps the columns in order",
+			data:  csvInput,
+			input: queryCmdInput{inputFormat: "csv", csvHeader: "use", outputFormat: "csv"},
+			sql:   "SELECT * FROM s3object s WHERE s.size > 100",
+			want:  "app.log,ERR,1500\n",
+		},
+		{
+			name:  "csv header ignored",
+			data:  csvInput,
+			input: queryCmdInput{inputFormat: "csv", csvHeader: "ignore", outputFormat: "csv"},
+			sql:   "SELECT s._1 FROM s3object s WHERE s._2 = 'OK'",
+			want:  "db.log\n",
+		},
+		{
+			name:  "csv without header reads the first row",
+			data:  csvInput,
+			input: queryCmdInput{inputFormat: "csv", csvHeader: "none", outputFormat: "csv"},
+			sql:   "SELECT s._3 FROM s3object s LIMIT 2",
+			want:  "size\n1500\n",
+		},
+		{
+			name:  "csv with another delimiter to json",
+			data:  "name;size\na;1\nb;2\n",
+			input: queryCmdInput{inputFormat: "csv", csvHeader: "use", delimiter: ";", outputFormat: "json"},
+			sql:   "SELECT s.name AS n FROM s3object s WHERE s.size = 2",
+			want:  "{\"n\":\"b\"}\n",
+		},
+		{
+			name:  "json lines",
+			data:  jsonLines,
+			input: queryCmdInput{inputFormat: "json", jsonType: "lines", outputFormat: "json"},
+			sql:   "SELECT s.name, s.meta.owner FROM s3object s WHERE s.status = 'ERR'",
+			want:  "{\"name\":\"app.log\",\"owner\":\"ops\"}\n{\"name\":\"web.log\",\"owner\":null}\n",
+		},
+		{
+			name:  "json lines missing field",
+			data:  jsonLines,
+			input: queryCmdInput{inputFormat: "json", jsonType: "lines", outputFormat: "json"},
+			sql:   "SELECT s.name FROM s3object s WHERE s.size IS NULL",
+			want:  "{\"name\":\"web.log\"}\n",
+		},
+		{
+			name:  "json lines select star sorts the keys",
+			data:  jsonLines,
+			input: queryCmdInput{inputFormat: "json", jsonType: "lines", outputFormat: "json"},
+			sql:   "SELECT * FROM s3object s WHERE s.size < 100",
+			want:  "{\"name\":\"db.log\",\"size\":20,\"status\":\"OK\"}\n",
+		},
+		{
+			name:  "json lines limit",
+			data:  jsonLines,
+			input: queryCmdInput{inputFormat: "json", jsonType: "lines", outputFormat: "csv"},
+			sql:   "SELECT s.name FROM s3object s WHERE s.name LIKE '%.log' LIMIT 2",
+			want:  "app.log\ndb.log\n",
+		},
+		{
+			name:  "json document array",
+			data:  `[{"id":1,"ok":true},{"id":2,"ok":false}]`,
+			input: queryCmdInput{inputFormat: "json", jsonType: "document", outputFormat: "json"},
+			sql:   "SELECT s.id FROM s3object[*] s WHERE s.ok = FALSE",
+			want:  "{\"id\":2}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "input")
+			if err := os.WriteFile(path, []byte(tt.data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			input := tt.input
+			input.sql = tt.sql
+			if input.delimiter == "" {
+				input.delimiter = ","
+			}
+
+			var out bytes.Buffer
+			if err := selectLocal(path, &input, &out); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("got %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectLocalErrors(t *testing.T) {
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte("{\"a\":1}\n{\"a\":"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		path  string
+		input queryCmdInput
+		err   string
+	}{
+		{"parquet", bad, queryCmdInput{inputFormat: "parquet", sql: "SELECT * FROM s3object"}, "parquet"},
+		{"bad query", bad, queryCmdInput{inputFormat: "json", sql: "SELECT FROM s3object"}, "unsupported query"},
+		{"truncated json", bad, queryCmdInput{inputFormat: "json", jsonType: "lines", sql: "SELECT * FROM s3object"}, "unexpected EOF"},
+		{"missing file", filepath.Join(dir, "missing"), queryCmdInput{inputFormat: "json", sql: "SELECT * FROM s3object"}, "no such file"},
+	}
+	for _, tt := range tests {
+		input := tt.input
+		input.delimiter, input.outputFormat = ",", "json"
+		err := selectLocal(tt.path, &input, &bytes.Buffer{})
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
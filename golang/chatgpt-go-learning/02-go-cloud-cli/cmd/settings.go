@@ -0,0 +1,95 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+var cfgFile string
+
+// settings is the configuration file. For example:
+//
+//	protect:
+//	  buckets: ["prod-*", "billing"]
+//	  tags: ["protect=true"]
+type settings struct {
+	Protect struct {
+		// Buckets are glob patterns of protected bucket names.
+		Buckets []string `yaml:"buckets"`
+		// Tags are k=v tags that mark a bucket as protected.
+		Tags []string `yaml:"tags"`
+	} `yaml:"protect"`
+}
+
+var (
+	settingsOnce   sync.Once
+	loadedSettings *settings
+	settingsErr    error
+)
+
+// settingsPath returns the configuration file, and whether the user asked for
+// it explicitly, in which case it has to exist.
+func settingsPath() (string, bool) {
+	if cfgFile != "" {
+		return cfgFile, true
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", false
+	}
+	return filepath.Join(home, ".go-cloud-cli.yaml"), false
+}
+
+// loadSettings reads the configuration file once. A missing default file is
+// the same as an empty one.
+func loadSettings() (*settings, error) {
+	settingsOnce.Do(func() {
+		loadedSettings = &settings{}
+
+		file, explicit := settingsPath()
+		if file == "" {
+			return
+		}
+		data, err := os.ReadFile(file)
+		if os.IsNotExist(err) && !explicit {
+			return
+		}
+		if err != nil {
+			settingsErr = err
+			return
+		}
+
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(loadedSettings); err != nil && !errors.Is(err, io.EOF) {
+			settingsErr = fmt.Errorf("%s: %w", file, err)
+			return
+		}
+
+		for _, p := range loadedSettings.Protect.Buckets {
+			if _, err := path.Match(p, ""); err != nil {
+				settingsErr = fmt.Errorf("%s: invalid bucket pattern %q: %w", file, p, err)
+				return
+			}
+		}
+		if _, err := parseTags(loadedSettings.Protect.Tags); err != nil {
+			settingsErr = fmt.Errorf("%s: %w", file, err)
+		}
+	})
+	return loadedSettings, settingsErr
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.go-cloud-cli.yaml)")
+}
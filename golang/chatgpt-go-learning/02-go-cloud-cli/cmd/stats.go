@@ -0,0 +1,27 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+// lifecycleAges are the object ages, in days, that storage statistics are
+// bucketed by. They match the transitions we recommend.
+var lifecycleAges = []int{30, 90, 180}
+
+// minTransitionSize is the smallest object that is worth transitioning. The
+// infrequent access classes bill anything smaller as 128 KiB.
+const minTransitionSize = 128 * 1024
+
+// collectBucketStats aggregates the objects of bucket by storage class, with
+// the bytes lifecycle transitions would move.
+func collectBucketStats(ctx context.Context, cc *cloud.Client, bucket string) (*cloud.BucketStats, error) {
+	return cc.BucketStats(ctx, bucket, &cloud.BucketStatsOptions{
+		Ages:        lifecycleAges,
+		MinAgedSize: minTransitionSize,
+	})
+}
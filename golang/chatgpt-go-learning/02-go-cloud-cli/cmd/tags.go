@@ -0,0 +1,154 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+type tagsCmdInput struct {
+	name    string
+	tags    []string
+	replace bool
+}
+
+// tagsCmd represents the tags command
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "Manage the tags of a bucket",
+}
+
+// tagsGetCmd represents the tags get command
+var tagsGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Print the tags of a bucket",
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		getBucketTags(&tagsCmdInput{name: name})
+	},
+}
+
+// tagsSetCmd represents the tags set command
+var tagsSetCmd = &cobra.Command{
+	Use:   "set k=v...",
+	Short: "Set tags on a bucket",
+	Long: `Sets the given tags on a bucket and keeps its other tags. Use --replace to
+drop the tags that aren't given.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		replace, _ := cmd.Flags().GetBool("replace")
+		setBucketTags(&tagsCmdInput{name, args, replace})
+	},
+}
+
+// tagsUnsetCmd represents the tags unset command
+var tagsUnsetCmd = &cobra.Command{
+	Use:   "unset k...",
+	Short: "Remove tags from a bucket",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		unsetBucketTags(&tagsCmdInput{name: name, tags: args})
+	},
+}
+
+func getBucketTags(input *tagsCmdInput) {
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+
+	tags, err := cloud.New(cfg).BucketTags(ctx, input.name)
+	if err != nil {
+		log.Fatalf("Failed to get tags: %v", err)
+	}
+
+	if jsonOutput(false) {
+		jsonData, _ := json.MarshalIndent(tags, "", "  ")
+		fmt.Println(string(jsonData))
+		return
+	}
+	for _, k := range slices.Sorted(maps.Keys(tags)) {
+		fmt.Printf("%s=%s\n", k, tags[k])
+	}
+}
+
+func setBucketTags(input *tagsCmdInput) {
+	tags, err := parseTags(input.tags)
+	if err != nil {
+		log.Fatalf("Invalid tags: %v", err)
+	}
+	if input.replace || setsProtectionTag(tags) {
+		// Replacing the tags could drop a tag that protects the bucket, and
+		// setting one could change it.
+		guardDestructive(input.name)
+	}
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+
+	tags, err = cloud.New(cfg).TagBucket(ctx, input.name, tags, &cloud.TagBucketOptions{Replace: input.replace})
+	if err != nil {
+		log.Fatalf("Failed to set tags: %v", err)
+	}
+
+	fmt.Printf("%s now has %d tags\n", input.name, len(tags))
+}
+
+func unsetBucketTags(input *tagsCmdInput) {
+	guardDestructive(input.name)
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+
+	var keys []string
+	for _, t := range input.tags {
+		// Accept k=v as well, so that a set command line can be reused.
+		k, _, _ := strings.Cut(t, "=")
+		keys = append(keys, k)
+	}
+
+	tags, err := cloud.New(cfg).UntagBucket(ctx, input.name, keys)
+	if err != nil {
+		log.Fatalf("Failed to unset tags: %v", err)
+	}
+
+	fmt.Printf("%s now has %d tags\n", input.name, len(tags))
+}
+
+func init() {
+	tagsCmd.PersistentFlags().StringP("name", "n", "", "Bucket Name")
+	tagsCmd.MarkPersistentFlagRequired("name")
+
+	tagsSetCmd.Flags().Bool("replace", false, "Replace all existing tags instead of merging with them")
+
+	tagsCmd.AddCommand(tagsGetCmd, tagsSetCmd, tagsUnsetCmd)
+	rootCmd.AddCommand(tagsCmd)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go/middleware"
+	"golang.org/x/time/rate"
+)
+
+var (
+	maxBandwidth         string
+	maxRequestsPerSecond float64
+)
+
+var (
+	limitersOnce     sync.Once
+	bandwidthLimiter *rate.Limiter
+	requestLimiter   *rate.Limiter
+	limitersErr      error
+)
+
+// limiters returns the token buckets shared by every transfer worker. A nil
+// limiter means no limit was asked for. An invalid limit is reported on
+// every call, not only the first.
+func limiters() (*rate.Limiter, *rate.Limiter, error) {
+	limitersOnce.Do(func() {
+		if maxBandwidth != "" {
+			bytesPerSecond, err := parseSize(strings.TrimSuffix(maxBandwidth, "/s"))
+			if err != nil || bytesPerSecond <= 0 {
+				limitersErr = fmt.Errorf("invalid --max-bandwidth %q", maxBandwidth)
+				return
+			}
+			// Small bursts keep the rate smooth; reads are split to fit.
+			burst := int(min(bytesPerSecond, 256*1024))
+			bandwidthLimiter = rate.NewLimiter(rate.Limit(bytesPerSecond), burst)
+		}
+		if maxRequestsPerSecond > 0 {
+			requestLimiter = rate.NewLimiter(rate.Limit(maxRequestsPerSecond), max(1, int(maxRequestsPerSecond)))
+		}
+	})
+	return bandwidthLimiter, requestLimiter, limitersErr
+}
+
+// requestRateMiddleware waits for the shared request limiter before every API
+// call made by any client.
+func requestRateMiddleware(limiter *rate.Limiter) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		return stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("RequestRateLimit",
+			func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+				if err := limiter.Wait(ctx); err != nil {
+					return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+				}
+				return next.HandleFinalize(ctx, in)
+			}), middleware.Before)
+	}
+}
+
+// throttledBody reads from r no faster than the shared bandwidth limiter
+// allows.
+type throttledBody struct {
+	ctx     context.Context
+	r       io.ReadCloser
+	limiter *rate.Limiter
+}
+
+func (t *throttledBody) Read(p []byte) (int, error) {
+	if t.limiter != nil && len(p) > t.limiter.Burst() {
+		p = p[:t.limiter.Burst()]
+	}
+
+	n, err := t.r.Read(p)
+	if n > 0 && t.limiter != nil {
+		if werr := t.limiter.WaitN(t.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+func (t *throttledBody) Close() error {
+	return t.r.Close()
+}
+
+// throttledHTTPClient wraps the bodies of requests and responses, so that
+// the limit applies to what is actually sent and received by all workers
+// together, whatever the SDK reads locally to sign or checksum a request.
+// GetBody is wrapped as well, since net/http uses it to resend a body.
+type throttledHTTPClient struct {
+	client  aws.HTTPClient
+	limiter *rate.Limiter
+}
+
+func (c *throttledHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if req.Body != nil && req.Body != http.NoBody {
+		req.Body = &throttledBody{req.Context(), req.Body, c.limiter}
+	}
+	if getBody := req.GetBody; getBody != nil {
+		ctx := req.Context()
+		req.GetBody = func() (io.ReadCloser, error) {
+			body, err := getBody()
+			if err != nil || body == http.NoBody {
+				return body, err
+			}
+			return &throttledBody{ctx, body, c.limiter}, nil
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return resp, err
+	}
+	resp.Body = &throttledBody{req.Context(), resp.Body, c.limiter}
+	return resp, nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&maxBandwidth, "max-bandwidth", "", "Limit the combined bandwidth of all transfers, e.g. 20MB/s")
+	rootCmd.PersistentFlags().Float64Var(&maxRequestsPerSecond, "max-requests-per-second", 0, "Limit the rate of API requests")
+}
@@ -0,0 +1,176 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"golang.org/x/time/rate"
+)
+
+// resetLimiters lets the next limiters call read the flags again.
+func resetLimiters() {
+	limitersOnce = sync.Once{}
+	bandwidthLimiter, requestLimiter, limitersErr = nil, nil, nil
+}
+
+func TestLimitersKeepError(t *testing.T) {
+	t.Cleanup(func() {
+		maxBandwidth = ""
+		resetLimiters()
+	})
+	resetLimiters()
+	maxBandwidth = "lots/s"
+
+	for i := range 2 {
+		if _, _, err := limiters(); err == nil {
+			t.Fatalf("call %d accepted an invalid --max-bandwidth", i+1)
+		}
+	}
+}
+
+// recordingClient keeps the last request it was asked to send.
+type recordingClient struct{ req *http.Request }
+
+func (c *recordingClient) Do(req *http.Request) (*http.Response, error) {
+	c.req = req
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func TestThrottledClientWrapsResentBodies(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPut, "http://example.com", strings.NewReader("data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	inner := &recordingClient{}
+	client := &throttledHTTPClient{inner, rate.NewLimiter(rate.Inf, 1)}
+	if _, err := client.Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := inner.req.Body.(*throttledBody); !ok {
+		t.Errorf("Body is %T, want *throttledBody", inner.req.Body)
+	}
+	body, err := inner.req.GetBody()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := body.(*throttledBody); !ok {
+		t.Errorf("GetBody returned %T, want *throttledBody", body)
+	}
+	if data, _ := io.ReadAll(body); string(data) != "data" {
+		t.Errorf("GetBody read %q, want %q", data, "data")
+	}
+}
+
+// useThrottledS3 points --endpoint-url at a server that answers every S3
+// request with body, sets the limit flags and returns a client built from
+// loadConfig, the way every command gets one.
+func useThrottledS3(t *testing.T, body []byte, bandwidth string, requestsPerSecond float64) *s3.Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "aws-config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "aws-credentials"))
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIABASE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "base-secret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	t.Setenv("AWS_REGION", "us-east-1")
+
+	savedEndpoint, savedBandwidth, savedRequests := endpointURL, maxBandwidth, maxRequestsPerSecond
+	t.Cleanup(func() {
+		endpointURL, maxBandwidth, maxRequestsPerSecond = savedEndpoint, savedBandwidth, savedRequests
+		resetLimiters()
+	})
+	endpointURL, maxBandwidth, maxRequestsPerSecond = srv.URL, bandwidth, requestsPerSecond
+	resetLimiters()
+
+	cfg, err := loadConfig(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s3.NewFromConfig(cfg, func(o *s3.Options) { o.UsePathStyle = true })
+}
+
+func TestRequestLimitPacesRequests(t *testing.T) {
+	tests := []struct {
+		rate     float64
+		requests int
+		// The limiter starts with a burst of rate requests, so the rest
+		// take at least (requests - rate) / rate seconds.
+		atLeast time.Duration
+	}{
+		{20, 30, 500 * time.Millisecond},
+		{2.5, 4, 800 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		client := useThrottledS3(t, nil, "", tt.rate)
+
+		start := time.Now()
+		for range tt.requests {
+			if _, err := client.HeadBucket(context.Background(), &s3.HeadBucketInput{Bucket: aws.String("docs")}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if elapsed := time.Since(start); elapsed < tt.atLeast {
+			t.Errorf("%d requests at %g/s took %s, want at least %s", tt.requests, tt.rate, elapsed, tt.atLeast)
+		}
+	}
+}
+
+func TestBandwidthLimitPacesBodies(t *testing.T) {
+	const limit = 128 * 1024
+	data := bytes.Repeat([]byte("x"), limit*3/2)
+
+	// The limiter starts with a burst of one second's worth of bytes, so
+	// half a second's worth is left to wait for.
+	const atLeast = 500 * time.Millisecond
+
+	client := useThrottledS3(t, data, "128KiB/s", 0)
+	start := time.Now()
+	result, err := client.GetObject(context.Background(), &s3.GetObjectInput{Bucket: aws.String("docs"), Key: aws.String("big")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(result.Body)
+	result.Body.Close()
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("downloaded %d bytes with error %v, want %d", len(got), err, len(data))
+	}
+	if elapsed := time.Since(start); elapsed < atLeast {
+		t.Errorf("downloading %d bytes at %d/s took %s, want at least %s", len(data), limit, elapsed, atLeast)
+	}
+
+	client = useThrottledS3(t, nil, "128KiB/s", 0)
+	start = time.Now()
+	_, err = client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String("docs"),
+		Key:    aws.String("big"),
+		Body:   bytes.NewReader(data),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < atLeast {
+		t.Errorf("uploading %d bytes at %d/s took %s, want at least %s", len(data), limit, elapsed, atLeast)
+	}
+}
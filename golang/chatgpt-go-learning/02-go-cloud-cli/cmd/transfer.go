@@ -0,0 +1,112 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+// runTransfers runs fn for every job on a pool of concurrency workers,
+// reporting their progress as they go, and returns how far they got.
+// Failures are logged and don't stop other jobs. Once ctx is done no more
+// jobs are started, and the ones in flight are left to clean up after
+// themselves.
+func runTransfers(ctx context.Context, jobs []cloud.Transfer, concurrency int, fn func(context.Context, cloud.Transfer, *fileProgress) error) transferStatus {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	progress := newTransferProgress(jobs)
+	queue := make(chan cloud.Transfer)
+
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				f := progress.begin(job)
+				err := fn(ctx, job, f)
+				progress.end(f, err == nil)
+				var mpErr *cloud.MultipartError
+				switch {
+				case err == nil:
+				case ctx.Err() == nil:
+					log.Printf("Failed to transfer %s: %v", job.Key, err)
+					progress.fail()
+				case errors.As(err, &mpErr) && mpErr.AbortErr == nil:
+					log.Printf("Aborted the multipart upload of %s", job.Key)
+				case errors.As(err, &mpErr):
+					log.Print(err)
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for _, job := range jobs {
+		select {
+		case queue <- job:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	return progress.stop()
+}
+
+// reportTransfers logs the summary of a run of transfers, and prints it as
+// JSON if asked to, then exits with an error if any of them failed or the run
+// was stopped before the end.
+func reportTransfers(ctx context.Context, status transferStatus, what string, printJSON bool) {
+	log.Print(status)
+	if printJSON {
+		jsonData, _ := json.MarshalIndent(status, "", "  ")
+		fmt.Println(string(jsonData))
+	}
+
+	if ctx.Err() != nil {
+		cause := context.Cause(ctx)
+		if errors.Is(cause, context.Canceled) {
+			cause = errors.New("Interrupted")
+		}
+		log.Fatalf("%v: %d of %d %s didn't finish", cause, status.Unfinished, status.FilesTotal, what)
+	}
+	if status.Failed > 0 {
+		log.Fatalf("%d of %d %s failed", status.Failed, status.FilesTotal, what)
+	}
+}
+
+// transferContext returns the context of a run of transfers, which ends at
+// the timeout or when the user presses Ctrl-C.
+func transferContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	ctx, cancel := context.WithTimeoutCause(ctx, timeout, errors.New("Timeout"))
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// downloadFile downloads a file of a run of transfers, warning about objects
+// that can't be verified.
+func downloadFile(ctx context.Context, cc *cloud.Client, job cloud.Transfer, opts *cloud.DownloadOptions) error {
+	verified, err := cc.DownloadFile(ctx, job, opts)
+	if err == nil && !verified {
+		log.Printf("Warning: %s has no full object checksum, it was not verified", job.Key)
+	}
+	return err
+}
@@ -0,0 +1,159 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+type uploadCmdInput struct {
+	source      string
+	destination string
+	checksum    string
+	sse         string
+	kmsKeyID    string
+	customerKey string
+	partSize    string
+	concurrency int
+	timeout     int
+}
+
+// uploadCmd represents the upload command
+var uploadCmd = &cobra.Command{
+	Use:   "upload <file or directory> s3://bucket/key",
+	Short: "Upload a file or a directory to S3",
+	Long: `Uploads a file, or every file in a directory, with a CRC32C or SHA256
+checksum that S3 verifies before storing the object.
+
+Objects can be encrypted with --sse, or with a customer-provided key read from
+--sse-c-key-file. S3 doesn't keep customer-provided keys: downloading the
+objects needs the same key file.
+
+Files larger than --part-size are uploaded in parts. Pressing Ctrl-C stops
+starting new files and aborts the multipart uploads in flight, so that no
+parts are left behind. Uploading over existing objects of a protected bucket
+needs --i-know-what-im-doing.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		checksum, _ := cmd.Flags().GetString("checksum")
+		sse, _ := cmd.Flags().GetString("sse")
+		kmsKeyID, _ := cmd.Flags().GetString("sse-kms-key-id")
+		customerKey, _ := cmd.Flags().GetString("sse-c-key-file")
+		partSize, _ := cmd.Flags().GetString("part-size")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		timeout, _ := cmd.Flags().GetInt("timeout")
+		upload(&uploadCmdInput{
+			source:      args[0],
+			destination: args[1],
+			checksum:    checksum,
+			sse:         sse,
+			kmsKeyID:    kmsKeyID,
+			customerKey: customerKey,
+			partSize:    partSize,
+			concurrency: concurrency,
+			timeout:     timeout,
+		})
+	},
+}
+
+func upload(input *uploadCmdInput) {
+	algorithm, err := cloud.ChecksumAlgorithm(input.checksum)
+	if err != nil {
+		log.Fatalf("Invalid checksum: %v", err)
+	}
+	enc, err := newObjectEncryption(input.sse, input.kmsKeyID, input.customerKey)
+	if err != nil {
+		log.Fatalf("Invalid encryption: %v", err)
+	}
+	partSize, err := parseSize(input.partSize)
+	if err != nil || partSize < cloud.MinPartSize {
+		log.Fatalf("Invalid --part-size %q, it must be at least 5MiB", input.partSize)
+	}
+	bucket, prefix, err := cloud.ParseS3URI(input.destination)
+	if err != nil {
+		log.Fatalf("Invalid destination: %v", err)
+	}
+	jobs, err := cloud.PlanUpload(input.source, bucket, prefix)
+	if err != nil {
+		log.Fatalf("Unable to read %s: %v", input.source, err)
+	}
+
+	ctx, cancel := transferContext(time.Duration(input.timeout) * time.Second)
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+	cc := cloud.New(cfg)
+
+	if _, err := cc.BucketClient(ctx, bucket); err != nil {
+		log.Fatalf("Unable to locate bucket %s: %v", bucket, err)
+	}
+	// Replacing objects is destructive, adding new ones isn't. The listing is
+	// only needed on protected buckets.
+	if reason, err := protectionReason(bucket); err != nil || reason != "" {
+		overwrites, err := uploadOverwrites(ctx, cc, bucket, prefix, jobs)
+		if err != nil {
+			log.Fatalf("Unable to list %s: %v", input.destination, err)
+		}
+		if overwrites {
+			guardDestructive(bucket)
+		}
+	}
+
+	status := runTransfers(ctx, jobs, input.concurrency, func(ctx context.Context, job cloud.Transfer, progress *fileProgress) error {
+		return cc.UploadFile(ctx, job, &cloud.UploadOptions{
+			ChecksumAlgorithm: algorithm,
+			PartSize:          partSize,
+			Encryption:        enc,
+			Progress:          progress,
+		})
+	})
+	reportTransfers(ctx, status, "uploads", jsonOutput(false))
+
+	log.Printf("Uploaded %d files to s3://%s/%s", len(jobs), bucket, prefix)
+}
+
+// errOverwrite stops the listing at the first object an upload would replace.
+var errOverwrite = errors.New("overwrite")
+
+// uploadOverwrites reports whether any of the jobs would replace an existing
+// object. Every job key starts with prefix, so only the prefix is listed.
+func uploadOverwrites(ctx context.Context, cc *cloud.Client, bucket, prefix string, jobs []cloud.Transfer) (bool, error) {
+	keys := map[string]bool{}
+	for _, job := range jobs {
+		keys[job.Key] = true
+	}
+	err := cc.WalkObjects(ctx, bucket, prefix, func(o types.Object) error {
+		if keys[aws.ToString(o.Key)] {
+			return errOverwrite
+		}
+		return nil
+	})
+	if errors.Is(err, errOverwrite) {
+		return true, nil
+	}
+	return false, err
+}
+
+func init() {
+	uploadCmd.Flags().String("checksum", "crc32c", "Checksum algorithm: crc32c or sha256")
+	uploadCmd.Flags().String("sse", "", "Server-side encryption: AES256, aws:kms or aws:kms:dsse")
+	uploadCmd.Flags().String("sse-kms-key-id", "", "KMS key ID or ARN for --sse aws:kms")
+	uploadCmd.Flags().String("sse-c-key-file", "", "File holding a 256 bit customer-provided key (SSE-C)")
+	uploadCmd.Flags().String("part-size", "64MiB", "Upload files larger than this in parts of this size")
+	uploadCmd.Flags().Int("concurrency", 4, "Number of files uploaded at once")
+	uploadCmd.Flags().IntP("timeout", "t", 3600, "Timeout in seconds")
+	rootCmd.AddCommand(uploadCmd)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+type verifyCmdInput struct {
+	uri         string
+	against     string
+	concurrency int
+	timeout     int
+}
+
+// verifyCmd represents the verify command
+var verifyCmd = &cobra.Command{
+	Use:   "verify s3://bucket/prefix --against ./dir",
+	Short: "Verify objects against a local directory",
+	Long: `Compares the checksums S3 stored for the objects under a prefix with the
+files in a local directory and prints a JSON report of mismatched files, files
+missing from the bucket and objects with no local counterpart.
+
+Objects uploaded without a flexible checksum are compared by MD5 when their
+ETag allows it, and reported as unverifiable otherwise. The command exits with
+a non-zero status if anything did not match.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		against, _ := cmd.Flags().GetString("against")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		timeout, _ := cmd.Flags().GetInt("timeout")
+		verify(&verifyCmdInput{
+			uri:         args[0],
+			against:     against,
+			concurrency: concurrency,
+			timeout:     timeout,
+		})
+	},
+}
+
+func verify(input *verifyCmdInput) {
+	bucket, prefix, err := cloud.ParseS3URI(input.uri)
+	if err != nil {
+		log.Fatalf("Invalid location: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), time.Duration(input.timeout)*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+
+	report, err := cloud.New(cfg).Verify(ctx, bucket, prefix, input.against, &cloud.VerifyOptions{Concurrency: input.concurrency})
+	if err != nil {
+		log.Fatalf("Unable to verify %s: %v", input.uri, err)
+	}
+
+	jsonData, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(jsonData))
+
+	if report.Failed() {
+		log.Fatalf("Verification failed: %d mismatched, %d missing, %d extra",
+			len(report.Mismatched), len(report.Missing), len(report.Extra))
+	}
+}
+
+func init() {
+	verifyCmd.Flags().String("against", "", "Local directory to verify the objects against")
+	verifyCmd.MarkFlagRequired("against")
+	verifyCmd.Flags().Int("concurrency", 8, "Number of objects checked at once")
+	verifyCmd.Flags().IntP("timeout", "t", 3600, "Timeout in seconds")
+	rootCmd.AddCommand(verifyCmd)
+}
@@ -0,0 +1,337 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+type watchCmdInput struct {
+	uri      string
+	interval time.Duration
+	exec     string
+	initial  bool
+	timeout  int
+}
+
+// snapshotEntry is what a snapshot remembers of an object.
+type snapshotEntry struct {
+	Key          string
+	Size         int64
+	ETag         string
+	LastModified time.Time
+}
+
+// watchEvent is a change between two polls. It is printed as a JSON line and
+// is what --exec templates are rendered with. Deleted events carry the object
+// as it was last seen.
+type watchEvent struct {
+	Event  string
+	Bucket string
+	snapshotEntry
+}
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch s3://bucket/prefix",
+	Short: "Poll a prefix and stream the objects added, modified and deleted",
+	Long: `Lists a prefix every interval and prints a JSON line for every object added,
+modified or deleted since the previous listing. The first listing is the
+baseline and emits nothing unless --initial is given.
+
+--exec runs a command for each event instead. The command is a Go template with
+the fields Event (added, modified or deleted), Bucket, Key, Size, ETag and
+LastModified, for example:
+
+  go-cloud-cli watch s3://artifacts/builds/ --exec 'echo "{{.Event}}" "{{.Key}}"'
+
+The command is split into words before the fields are filled in and runs
+without a shell, so each field stays a single argument whatever the key holds.
+Keys are chosen by whoever writes to the prefix; a command that passes them to
+a shell must quote them with shellquote, e.g. sh -c "... {{shellquote .Key}}".
+
+Listings are kept on disk, not in memory, so prefixes of any size can be
+watched.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		interval, _ := cmd.Flags().GetDuration("interval")
+		execTemplate, _ := cmd.Flags().GetString("exec")
+		initial, _ := cmd.Flags().GetBool("initial")
+		timeout, _ := cmd.Flags().GetInt("timeout")
+		watch(&watchCmdInput{args[0], interval, execTemplate, initial, timeout})
+	},
+}
+
+// snapshotReader walks a snapshot file in key order. entry is nil once the
+// snapshot is exhausted.
+type snapshotReader struct {
+	scanner *bufio.Scanner
+	entry   *snapshotEntry
+}
+
+func newSnapshotReader(r io.Reader) (*snapshotReader, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	s := &snapshotReader{scanner: scanner}
+	return s, s.next()
+}
+
+func (s *snapshotReader) next() error {
+	if !s.scanner.Scan() {
+		s.entry = nil
+		return s.scanner.Err()
+	}
+	s.entry = &snapshotEntry{}
+	return json.Unmarshal(s.scanner.Bytes(), s.entry)
+}
+
+// watcher polls a prefix. Each poll streams the listing, which S3 returns in
+// key order, against the previous snapshot like a merge join, so memory use
+// doesn't depend on the number of objects.
+type watcher struct {
+	cc       *cloud.Client
+	bucket   string
+	prefix   string
+	dir      string
+	snapshot string
+}
+
+// poll lists the prefix and writes the changes since the previous poll to a
+// file, which the caller reads and removes. The snapshot only moves forward
+// when the whole listing succeeded, so a failed poll is simply retried.
+func (w *watcher) poll(ctx context.Context) (*os.File, error) {
+	var previous io.Reader = strings.NewReader("")
+	if w.snapshot != "" {
+		f, err := os.Open(w.snapshot)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		previous = f
+	}
+	prev, err := newSnapshotReader(previous)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := os.CreateTemp(w.dir, "snapshot-*")
+	if err != nil {
+		return nil, err
+	}
+	events, err := os.CreateTemp(w.dir, "events-*")
+	if err != nil {
+		next.Close()
+		os.Remove(next.Name())
+		return nil, err
+	}
+	fail := func(err error) (*os.File, error) {
+		next.Close()
+		events.Close()
+		os.Remove(next.Name())
+		os.Remove(events.Name())
+		return nil, err
+	}
+
+	nextOut := bufio.NewWriter(next)
+	eventsOut := bufio.NewWriter(events)
+	snapshotEnc := json.NewEncoder(nextOut)
+	eventEnc := json.NewEncoder(eventsOut)
+	emit := func(event string, e snapshotEntry) error {
+		return eventEnc.Encode(watchEvent{event, w.bucket, e})
+	}
+
+	err = w.cc.WalkObjects(ctx, w.bucket, w.prefix, func(o types.Object) error {
+		cur := snapshotEntry{
+			Key:          aws.ToString(o.Key),
+			Size:         aws.ToInt64(o.Size),
+			ETag:         strings.Trim(aws.ToString(o.ETag), `"`),
+			LastModified: aws.ToTime(o.LastModified),
+		}
+
+		for prev.entry != nil && prev.entry.Key < cur.Key {
+			if err := emit("deleted", *prev.entry); err != nil {
+				return err
+			}
+			if err := prev.next(); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		switch {
+		case prev.entry == nil || prev.entry.Key != cur.Key:
+			err = emit("added", cur)
+		case prev.entry.ETag != cur.ETag || prev.entry.Size != cur.Size || !prev.entry.LastModified.Equal(cur.LastModified):
+			err = emit("modified", cur)
+			if err == nil {
+				err = prev.next()
+			}
+		default:
+			err = prev.next()
+		}
+		if err != nil {
+			return err
+		}
+		return snapshotEnc.Encode(cur)
+	})
+	if err != nil {
+		return fail(err)
+	}
+	for prev.entry != nil {
+		if err := emit("deleted", *prev.entry); err != nil {
+			return fail(err)
+		}
+		if err := prev.next(); err != nil {
+			return fail(err)
+		}
+	}
+
+	if err := nextOut.Flush(); err != nil {
+		return fail(err)
+	}
+	if err := eventsOut.Flush(); err != nil {
+		return fail(err)
+	}
+	if err := next.Close(); err != nil {
+		return fail(err)
+	}
+	if _, err := events.Seek(0, io.SeekStart); err != nil {
+		return fail(err)
+	}
+
+	if w.snapshot == "" {
+		w.snapshot = filepath.Join(w.dir, "snapshot")
+	}
+	if err := os.Rename(next.Name(), w.snapshot); err != nil {
+		return fail(err)
+	}
+	return events, nil
+}
+
+// dispatchEvents prints the events of a poll as JSON lines, or runs the
+// --exec command for each of them.
+func dispatchEvents(events io.Reader, command execCommand) error {
+	if command == nil {
+		_, err := io.Copy(os.Stdout, events)
+		return err
+	}
+
+	scanner := bufio.NewScanner(events)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event watchEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return err
+		}
+		if err := command.run(event); err != nil {
+			log.Printf("Command for %s %s failed: %v", event.Event, event.Key, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func watch(input *watchCmdInput) {
+	bucket, prefix, err := cloud.ParseS3URI(input.uri)
+	if err != nil {
+		log.Fatalf("Invalid location: %v", err)
+	}
+	if input.interval < time.Second {
+		log.Fatalf("Invalid --interval %s, it must be at least 1s", input.interval)
+	}
+
+	var command execCommand
+	if input.exec != "" {
+		command, err = parseExec(input.exec)
+		if err != nil {
+			log.Fatalf("Invalid --exec template: %v", err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+	cc := cloud.New(cfg)
+
+	if _, err := cc.BucketClient(ctx, bucket); err != nil {
+		log.Fatalf("Unable to locate bucket %s: %v", bucket, err)
+	}
+
+	dir, err := os.MkdirTemp("", "go-cloud-cli-watch-")
+	if err != nil {
+		log.Fatalf("Unable to create snapshot directory: %v", err)
+	}
+
+	w := &watcher{cc: cc, bucket: bucket, prefix: prefix, dir: dir}
+	err = w.run(ctx, input, command)
+	// Fatal exits skip the deferred clean-up.
+	os.RemoveAll(dir)
+	if err != nil {
+		log.Fatalf("Unable to emit events: %v", err)
+	}
+}
+
+// run polls every interval until ctx is done, and returns the first error
+// emitting events. Listing errors are logged and retried.
+func (w *watcher) run(ctx context.Context, input *watchCmdInput, command execCommand) error {
+	baseline := !input.initial
+	ticker := time.NewTicker(input.interval)
+	defer ticker.Stop()
+
+	for {
+		pollCtx, cancel := context.WithTimeoutCause(ctx, time.Duration(input.timeout)*time.Second, errors.New("Timeout"))
+		events, err := w.poll(pollCtx)
+		cancel()
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			log.Printf("Unable to list %s, retrying: %v", input.uri, err)
+		} else {
+			if !baseline {
+				err = dispatchEvents(events, command)
+			}
+			events.Close()
+			os.Remove(events.Name())
+			if err != nil {
+				return err
+			}
+			baseline = false
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func init() {
+	watchCmd.Flags().Duration("interval", 10*time.Second, "Time between listings")
+	watchCmd.Flags().String("exec", "", "Run a templated command for each event")
+	watchCmd.Flags().Bool("initial", false, "Report the objects present at start as added")
+	watchCmd.Flags().IntP("timeout", "t", 300, "Timeout of each listing in seconds")
+	rootCmd.AddCommand(watchCmd)
+}
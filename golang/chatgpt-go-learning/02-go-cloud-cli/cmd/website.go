@@ -0,0 +1,169 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ammarlakis/go-cloud-cli/pkg/cloud"
+)
+
+type websiteCmdInput struct {
+	name          string
+	index         string
+	errorDocument string
+	public        bool
+	yes           bool
+}
+
+// websiteCmd represents the website command
+var websiteCmd = &cobra.Command{
+	Use:   "website",
+	Short: "Manage static website hosting for a bucket",
+}
+
+// websiteEnableCmd represents the website enable command
+var websiteEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Host a static website from a bucket",
+	Long: `Configures the index and error documents of a bucket and prints its website
+endpoint. With --public, the bucket's public access block is relaxed and a
+policy granting everyone read access to its objects is applied, after asking
+for confirmation. On a protected bucket --public needs --i-know-what-im-doing.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		index, _ := cmd.Flags().GetString("index")
+		errorDocument, _ := cmd.Flags().GetString("error")
+		public, _ := cmd.Flags().GetBool("public")
+		yes, _ := cmd.Flags().GetBool("yes")
+		enableWebsite(&websiteCmdInput{name, index, errorDocument, public, yes})
+	},
+}
+
+// websiteDisableCmd represents the website disable command
+var websiteDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Stop hosting a static website from a bucket",
+	Long: `Removes the website configuration of a bucket. A public read policy applied
+by "website enable --public" is left in place and has to be removed separately.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		disableWebsite(&websiteCmdInput{name: name})
+	},
+}
+
+// websiteShowCmd represents the website show command
+var websiteShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the website configuration of a bucket",
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		showWebsite(&websiteCmdInput{name: name})
+	},
+}
+
+func enableWebsite(input *websiteCmdInput) {
+	// Making every object public is destructive, hosting alone isn't.
+	if input.public {
+		guardDestructive(input.name)
+	}
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+	cc := cloud.New(cfg)
+
+	website, err := cc.EnableWebsite(ctx, input.name, &cloud.EnableWebsiteOptions{
+		IndexDocument: input.index,
+		ErrorDocument: input.errorDocument,
+	})
+	if err != nil {
+		log.Fatalf("Failed to enable website hosting: %v", err)
+	}
+
+	if input.public {
+		if input.yes || confirm("Make every object in %s readable by anyone on the internet?", input.name) {
+			if err := cc.AllowPublicRead(ctx, input.name); err != nil {
+				log.Fatalf("Failed to make %s public: %v", input.name, err)
+			}
+		} else {
+			log.Printf("Skipping the public read policy; the website will return 403 until objects are readable")
+		}
+	}
+
+	fmt.Printf("Website hosting enabled for %s\n", input.name)
+	fmt.Println(website.Endpoint)
+}
+
+func disableWebsite(input *websiteCmdInput) {
+	guardDestructive(input.name)
+
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+
+	if err := cloud.New(cfg).DisableWebsite(ctx, input.name); err != nil {
+		log.Fatalf("Failed to disable website hosting: %v", err)
+	}
+
+	fmt.Printf("Website hosting disabled for %s\n", input.name)
+}
+
+func showWebsite(input *websiteCmdInput) {
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 10*time.Second, errors.New("Timeout"))
+	defer cancel()
+
+	cfg, err := loadConfig(ctx)
+	if err != nil {
+		log.Fatalf("Unable to load SDK config: %v", err)
+	}
+
+	website, err := cloud.New(cfg).Website(ctx, input.name)
+	if err != nil {
+		log.Fatalf("Failed to get website configuration: %v", err)
+	}
+
+	if jsonOutput(false) {
+		jsonData, _ := json.MarshalIndent(website, "", "  ")
+		fmt.Println(string(jsonData))
+		return
+	}
+	if !website.Enabled {
+		fmt.Printf("Website hosting is disabled for %s\n", input.name)
+		return
+	}
+	fmt.Printf("Index document: %s\n", website.IndexDocument)
+	if website.ErrorDocument != "" {
+		fmt.Printf("Error document: %s\n", website.ErrorDocument)
+	}
+	fmt.Printf("Endpoint: %s\n", website.Endpoint)
+}
+
+func init() {
+	websiteCmd.PersistentFlags().StringP("name", "n", "", "Bucket Name")
+	websiteCmd.MarkPersistentFlagRequired("name")
+
+	websiteEnableCmd.Flags().String("index", "index.html", "Document served for requests to a directory")
+	websiteEnableCmd.Flags().String("error", "", "Document served when an error occurs")
+	websiteEnableCmd.Flags().Bool("public", false, "Apply a public read bucket policy")
+	websiteEnableCmd.Flags().BoolP("yes", "y", false, "Don't ask for confirmation before making the bucket public")
+
+	websiteCmd.AddCommand(websiteEnableCmd, websiteDisableCmd, websiteShowCmd)
+	rootCmd.AddCommand(websiteCmd)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// CreateBucketOptions configures CreateBucket.
+type CreateBucketOptions struct {
+	// Region to create the bucket in. Defaults to the region of the config.
+	Region string
+}
+
+// DeleteBucketOptions configures DeleteBucket.
+type DeleteBucketOptions struct{}
+
+// ListBucketsOptions configures ListBuckets.
+type ListBucketsOptions struct {
+	// Regions to list the buckets of, concurrently. If empty, every bucket is
+	// listed with a single call.
+	Regions []string
+	// Concurrency is the number of regions listed at once. Defaults to 1.
+	Concurrency int
+}
+
+// CreateBucket creates a bucket. Outside us-east-1 S3 needs the region as a
+// location constraint.
+func (c *Client) CreateBucket(ctx context.Context, name string, opts *CreateBucketOptions) error {
+	if opts == nil {
+		opts = &CreateBucketOptions{}
+	}
+
+	cfg := c.cfg.Copy()
+	if opts.Region != "" {
+		cfg.Region = opts.Region
+	}
+	input := &s3.CreateBucketInput{Bucket: &name}
+	if cfg.Region != "" && cfg.Region != "us-east-1" && !c.customEndpoint() {
+		input.CreateBucketConfiguration = &types.CreateBucketConfiguration{
+			LocationConstraint: types.BucketLocationConstraint(cfg.Region),
+		}
+	}
+
+	_, err := c.newS3Client(cfg).CreateBucket(ctx, input)
+	return err
+}
+
+// DeleteBucket deletes an empty bucket.
+func (c *Client) DeleteBucket(ctx context.Context, name string, opts *DeleteBucketOptions) error {
+	client, err := c.BucketClient(ctx, name)
+	if err != nil {
+		return err
+	}
+	_, err = client.DeleteBucket(ctx, &s3.DeleteBucketInput{Bucket: &name})
+	return err
+}
+
+// ListBuckets lists the buckets of the account. With Regions set, only the
+// buckets of those regions are listed and a *RegionsError is returned along
+// with the buckets of the regions that succeeded if some fail.
+func (c *Client) ListBuckets(ctx context.Context, opts *ListBucketsOptions) ([]RegionRow[types.Bucket], error) {
+	if opts == nil {
+		opts = &ListBucketsOptions{}
+	}
+
+	if len(opts.Regions) == 0 {
+		buckets, err := listBuckets(ctx, c.S3(), nil)
+		if err != nil {
+			return nil, err
+		}
+		rows := make([]RegionRow[types.Bucket], len(buckets))
+		for i, b := range buckets {
+			rows[i] = RegionRow[types.Bucket]{aws.ToString(b.BucketRegion), b}
+		}
+		return rows, nil
+	}
+
+	return FanOutRegions(ctx, c.cfg, opts.Regions, opts.Concurrency, func(ctx context.Context, cfg aws.Config) ([]types.Bucket, error) {
+		return listBuckets(ctx, c.newS3Client(cfg), aws.String(cfg.Region))
+	})
+}
+
+func listBuckets(ctx context.Context, client *s3.Client, region *string) ([]types.Bucket, error) {
+	var buckets []types.Bucket
+	paginator := s3.NewListBucketsPaginator(client, &s3.ListBucketsInput{BucketRegion: region})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, page.Buckets...)
+	}
+	return buckets, nil
+}
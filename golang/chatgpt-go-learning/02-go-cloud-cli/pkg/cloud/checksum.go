@@ -0,0 +1,115 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// ChecksumAlgorithm returns the S3 algorithm for crc32c or sha256, ignoring
+// case. These are the algorithms transfers can verify end to end.
+func ChecksumAlgorithm(name string) (types.ChecksumAlgorithm, error) {
+	switch strings.ToUpper(name) {
+	case "CRC32C":
+		return types.ChecksumAlgorithmCrc32c, nil
+	case "SHA256":
+		return types.ChecksumAlgorithmSha256, nil
+	default:
+		return "", fmt.Errorf("unsupported checksum algorithm %q, expected crc32c or sha256", name)
+	}
+}
+
+func newChecksumHash(algorithm types.ChecksumAlgorithm) hash.Hash {
+	if algorithm == types.ChecksumAlgorithmSha256 {
+		return sha256.New()
+	}
+	return crc32.New(crc32.MakeTable(crc32.Castagnoli))
+}
+
+// encodeChecksum renders a hash the way S3 returns it: base64 of the digest.
+func encodeChecksum(h hash.Hash) string {
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// FileChecksum computes the checksum of a local file the way S3 reports it.
+func FileChecksum(path string, algorithm types.ChecksumAlgorithm) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := newChecksumHash(algorithm)
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return encodeChecksum(h), nil
+}
+
+// FileMD5 returns the hex MD5 of a local file, which is what the ETag of an
+// object uploaded in a single part without KMS encryption holds.
+func FileMD5(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// StoredChecksum picks the checksum S3 holds for an object. Checksums of
+// multipart uploads are checksums of the part checksums, marked with a "-N"
+// suffix, and can't be compared with the checksum of the whole file.
+func StoredChecksum(crc32c, sha256 *string) (types.ChecksumAlgorithm, string, bool) {
+	for _, c := range []struct {
+		algorithm types.ChecksumAlgorithm
+		value     *string
+	}{
+		{types.ChecksumAlgorithmSha256, sha256},
+		{types.ChecksumAlgorithmCrc32c, crc32c},
+	} {
+		if c.value != nil && *c.value != "" && !strings.Contains(*c.value, "-") {
+			return c.algorithm, *c.value, true
+		}
+	}
+	return "", "", false
+}
+
+// ObjectChecksum returns the full object checksum S3 holds for an object, as
+// picked by StoredChecksum. ok is false if it has none that a file can be
+// compared with.
+func (c *Client) ObjectChecksum(ctx context.Context, bucket, key string) (algorithm types.ChecksumAlgorithm, value string, ok bool, err error) {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return "", "", false, err
+	}
+	head, err := client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:       &bucket,
+		Key:          &key,
+		ChecksumMode: types.ChecksumModeEnabled,
+	})
+	if err != nil {
+		return "", "", false, err
+	}
+	algorithm, value, ok = StoredChecksum(head.ChecksumCRC32C, head.ChecksumSHA256)
+	return algorithm, value, ok, nil
+}
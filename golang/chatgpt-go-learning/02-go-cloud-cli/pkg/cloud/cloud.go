@@ -0,0 +1,107 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+
+// Package cloud exposes the operations of go-cloud-cli as a Go API. Every
+// function takes a context and returns its errors instead of exiting, so it can
+// be used from other programs.
+//
+//	cfg, err := config.LoadDefaultConfig(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	buckets, err := cloud.New(cfg).ListBuckets(ctx, nil)
+package cloud
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// Client runs operations with an AWS config. It is safe for concurrent use.
+type Client struct {
+	cfg aws.Config
+
+	mu      sync.Mutex
+	buckets map[string]*s3.Client
+}
+
+// New returns a Client for cfg. When cfg has a base endpoint, buckets are
+// addressed path style and assumed to live in cfg.Region, as S3 compatible
+// stores expect.
+func New(cfg aws.Config) *Client {
+	return &Client{cfg: cfg, buckets: map[string]*s3.Client{}}
+}
+
+// Config returns the AWS config of the client.
+func (c *Client) Config() aws.Config {
+	return c.cfg
+}
+
+func (c *Client) customEndpoint() bool {
+	return aws.ToString(c.cfg.BaseEndpoint) != ""
+}
+
+func (c *Client) newS3Client(cfg aws.Config) *s3.Client {
+	return s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if c.customEndpoint() {
+			o.UsePathStyle = true
+		}
+	})
+}
+
+// S3 returns an S3 client for the region of the config.
+func (c *Client) S3() *s3.Client {
+	return c.newS3Client(c.cfg)
+}
+
+// BucketRegion returns the region a bucket lives in.
+func (c *Client) BucketRegion(ctx context.Context, bucket string) (string, error) {
+	result, err := c.S3().GetBucketLocation(ctx, &s3.GetBucketLocationInput{
+		Bucket: &bucket,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	switch result.LocationConstraint {
+	case "":
+		return "us-east-1", nil
+	case types.BucketLocationConstraintEu:
+		return "eu-west-1", nil
+	default:
+		return string(result.LocationConstraint), nil
+	}
+}
+
+// BucketClient returns an S3 client pointed at the region of bucket, so that
+// object level calls aren't redirected. Clients are cached per bucket.
+func (c *Client) BucketClient(ctx context.Context, bucket string) (*s3.Client, error) {
+	c.mu.Lock()
+	client, ok := c.buckets[bucket]
+	c.mu.Unlock()
+	if ok {
+		return client, nil
+	}
+
+	if c.customEndpoint() {
+		client = c.S3()
+	} else {
+		region, err := c.BucketRegion(ctx, bucket)
+		if err != nil {
+			return nil, err
+		}
+		regionCfg := c.cfg.Copy()
+		regionCfg.Region = region
+		client = c.newS3Client(regionCfg)
+	}
+
+	c.mu.Lock()
+	c.buckets[bucket] = client
+	c.mu.Unlock()
+	return client, nil
+}
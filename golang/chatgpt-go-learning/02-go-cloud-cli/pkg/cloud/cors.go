@@ -0,0 +1,277 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// MaxCORSRules is the most CORS rules S3 allows on a bucket.
+const MaxCORSRules = 100
+
+// corsMethods are the only methods S3 accepts in a CORS rule.
+var corsMethods = []string{"GET", "PUT", "POST", "DELETE", "HEAD"}
+
+// CORSRule is a CORS rule of a bucket.
+type CORSRule struct {
+	ID             string   `yaml:"id,omitempty" json:"ID,omitempty"`
+	AllowedOrigins []string `yaml:"allowedOrigins" json:"AllowedOrigins"`
+	AllowedMethods []string `yaml:"allowedMethods" json:"AllowedMethods"`
+	AllowedHeaders []string `yaml:"allowedHeaders,omitempty" json:"AllowedHeaders,omitempty"`
+	ExposeHeaders  []string `yaml:"exposeHeaders,omitempty" json:"ExposeHeaders,omitempty"`
+	MaxAgeSeconds  int32    `yaml:"maxAgeSeconds,omitempty" json:"MaxAgeSeconds,omitempty"`
+}
+
+// PutCORSOptions configures PutCORSRules.
+type PutCORSOptions struct {
+	// Replace drops the existing rules instead of merging with them.
+	Replace bool
+}
+
+// DeleteCORSOptions configures DeleteCORS.
+type DeleteCORSOptions struct {
+	// ID deletes only the rule with this ID instead of every rule.
+	ID string
+}
+
+// Validate catches the mistakes S3 would reject the whole configuration for,
+// with a message that says what is wrong.
+func (r CORSRule) Validate() error {
+	var errs []error
+
+	if len(r.ID) > 255 {
+		errs = append(errs, errors.New("id is longer than 255 characters"))
+	}
+
+	if len(r.AllowedMethods) == 0 {
+		errs = append(errs, errors.New("allowedMethods is empty"))
+	}
+	for _, m := range r.AllowedMethods {
+		if !slices.Contains(corsMethods, m) {
+			errs = append(errs, fmt.Errorf("method %q is not one of %s", m, strings.Join(corsMethods, ", ")))
+		}
+	}
+
+	if len(r.AllowedOrigins) == 0 {
+		errs = append(errs, errors.New("allowedOrigins is empty"))
+	}
+	for _, o := range r.AllowedOrigins {
+		if err := validateCORSOrigin(o); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, h := range r.AllowedHeaders {
+		if strings.Count(h, "*") > 1 || !validHeaderName(strings.ReplaceAll(h, "*", "x")) {
+			errs = append(errs, fmt.Errorf("allowed header %q is not a valid header name", h))
+		}
+	}
+	for _, h := range r.ExposeHeaders {
+		if strings.Contains(h, "*") || !validHeaderName(h) {
+			errs = append(errs, fmt.Errorf("expose header %q is not a valid header name, wildcards are not allowed", h))
+		}
+	}
+
+	if r.MaxAgeSeconds < 0 {
+		errs = append(errs, errors.New("maxAgeSeconds is negative"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func validateCORSOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	if strings.Count(origin, "*") > 1 {
+		return fmt.Errorf("origin %q has more than one wildcard", origin)
+	}
+	u, err := url.Parse(strings.Replace(origin, "*", "wildcard", 1))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("origin %q is not a scheme://host origin", origin)
+	}
+	if u.Path != "" && u.Path != "/" {
+		return fmt.Errorf("origin %q must not have a path", origin)
+	}
+	return nil
+}
+
+// validHeaderName reports whether h is an RFC 7230 token.
+func validHeaderName(h string) bool {
+	if h == "" {
+		return false
+	}
+	for _, c := range h {
+		if c > 0x7e || c <= ' ' || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, c) {
+			return false
+		}
+	}
+	return true
+}
+
+func fromS3CORSRule(r types.CORSRule) CORSRule {
+	return CORSRule{
+		ID:             aws.ToString(r.ID),
+		AllowedOrigins: r.AllowedOrigins,
+		AllowedMethods: r.AllowedMethods,
+		AllowedHeaders: r.AllowedHeaders,
+		ExposeHeaders:  r.ExposeHeaders,
+		MaxAgeSeconds:  aws.ToInt32(r.MaxAgeSeconds),
+	}
+}
+
+func toS3CORSRule(r CORSRule) types.CORSRule {
+	rule := types.CORSRule{
+		AllowedOrigins: r.AllowedOrigins,
+		AllowedMethods: r.AllowedMethods,
+		AllowedHeaders: r.AllowedHeaders,
+		ExposeHeaders:  r.ExposeHeaders,
+	}
+	if r.ID != "" {
+		rule.ID = aws.String(r.ID)
+	}
+	if r.MaxAgeSeconds > 0 {
+		rule.MaxAgeSeconds = aws.Int32(r.MaxAgeSeconds)
+	}
+	return rule
+}
+
+// mergeCORSRules adds rules to existing. A rule replaces the existing rule
+// with the same ID; rules without an ID are added unless an identical rule
+// already exists.
+func mergeCORSRules(existing, rules []CORSRule) []CORSRule {
+	merged := slices.Clone(existing)
+	for _, rule := range rules {
+		i := slices.IndexFunc(merged, func(r CORSRule) bool {
+			if rule.ID != "" {
+				return r.ID == rule.ID
+			}
+			return reflect.DeepEqual(r, rule)
+		})
+		if i >= 0 {
+			merged[i] = rule
+		} else {
+			merged = append(merged, rule)
+		}
+	}
+	return merged
+}
+
+// CORSRules returns the CORS rules of a bucket, or none if it has no CORS
+// configuration.
+func (c *Client) CORSRules(ctx context.Context, bucket string) ([]CORSRule, error) {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.GetBucketCors(ctx, &s3.GetBucketCorsInput{Bucket: &bucket})
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchCORSConfiguration" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]CORSRule, 0, len(result.CORSRules))
+	for _, r := range result.CORSRules {
+		rules = append(rules, fromS3CORSRule(r))
+	}
+	return rules, nil
+}
+
+// PutCORSRules validates rules and adds them to the CORS configuration of a
+// bucket. A rule replaces an existing rule with the same ID and other
+// existing rules are kept unless opts.Replace is set. It returns the
+// resulting rules.
+func (c *Client) PutCORSRules(ctx context.Context, bucket string, rules []CORSRule, opts *PutCORSOptions) ([]CORSRule, error) {
+	if opts == nil {
+		opts = &PutCORSOptions{}
+	}
+	var errs []error
+	for i, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("rule %d: %w", i+1, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	if !opts.Replace {
+		existing, err := c.CORSRules(ctx, bucket)
+		if err != nil {
+			return nil, err
+		}
+		rules = mergeCORSRules(existing, rules)
+	}
+	if len(rules) > MaxCORSRules {
+		return nil, fmt.Errorf("a bucket can have at most %d CORS rules, this would set %d", MaxCORSRules, len(rules))
+	}
+
+	if err := c.writeCORSRules(ctx, bucket, rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// DeleteCORS deletes the CORS configuration of a bucket, or only the rule
+// with opts.ID, and returns the remaining rules.
+func (c *Client) DeleteCORS(ctx context.Context, bucket string, opts *DeleteCORSOptions) ([]CORSRule, error) {
+	if opts == nil {
+		opts = &DeleteCORSOptions{}
+	}
+
+	var rules []CORSRule
+	if opts.ID != "" {
+		existing, err := c.CORSRules(ctx, bucket)
+		if err != nil {
+			return nil, err
+		}
+		rules = slices.DeleteFunc(existing, func(r CORSRule) bool { return r.ID == opts.ID })
+		if len(rules) == len(existing) {
+			return nil, fmt.Errorf("%s has no CORS rule with id %s", bucket, opts.ID)
+		}
+	}
+
+	if err := c.writeCORSRules(ctx, bucket, rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// writeCORSRules replaces the CORS configuration of a bucket, deleting it
+// when no rules are left.
+func (c *Client) writeCORSRules(ctx context.Context, bucket string, rules []CORSRule) error {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		_, err := client.DeleteBucketCors(ctx, &s3.DeleteBucketCorsInput{Bucket: &bucket})
+		return err
+	}
+
+	s3Rules := make([]types.CORSRule, 0, len(rules))
+	for _, r := range rules {
+		s3Rules = append(s3Rules, toS3CORSRule(r))
+	}
+	_, err = client.PutBucketCors(ctx, &s3.PutBucketCorsInput{
+		Bucket:            &bucket,
+		CORSConfiguration: &types.CORSConfiguration{CORSRules: s3Rules},
+	})
+	return err
+}
@@ -0,0 +1,104 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCORSRuleValidate(t *testing.T) {
+	valid := CORSRule{
+		AllowedOrigins: []string{"https://example.com"},
+		AllowedMethods: []string{"GET", "HEAD"},
+	}
+	with := func(f func(r *CORSRule)) CORSRule {
+		r := valid
+		f(&r)
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		rule    CORSRule
+		wantErr string
+	}{
+		{"minimal", valid, ""},
+		{"any origin", with(func(r *CORSRule) { r.AllowedOrigins = []string{"*"} }), ""},
+		{"subdomain wildcard", with(func(r *CORSRule) { r.AllowedOrigins = []string{"https://*.example.com"} }), ""},
+		{"origin with port", with(func(r *CORSRule) { r.AllowedOrigins = []string{"http://localhost:3000"} }), ""},
+		{"origin with trailing slash", with(func(r *CORSRule) { r.AllowedOrigins = []string{"https://example.com/"} }), ""},
+		{"every method", with(func(r *CORSRule) { r.AllowedMethods = []string{"GET", "PUT", "POST", "DELETE", "HEAD"} }), ""},
+		{"header wildcard", with(func(r *CORSRule) { r.AllowedHeaders = []string{"*", "x-amz-*"} }), ""},
+		{"expose header", with(func(r *CORSRule) { r.ExposeHeaders = []string{"ETag"} }), ""},
+
+		{"no origins", with(func(r *CORSRule) { r.AllowedOrigins = nil }), "allowedOrigins is empty"},
+		{"bare host", with(func(r *CORSRule) { r.AllowedOrigins = []string{"example.com"} }), "not a scheme://host origin"},
+		{"two wildcards", with(func(r *CORSRule) { r.AllowedOrigins = []string{"https://*.*.example.com"} }), "more than one wildcard"},
+		{"origin with path", with(func(r *CORSRule) { r.AllowedOrigins = []string{"https://example.com/app"} }), "must not have a path"},
+		{"no methods", with(func(r *CORSRule) { r.AllowedMethods = nil }), "allowedMethods is empty"},
+		{"lowercase method", with(func(r *CORSRule) { r.AllowedMethods = []string{"get"} }), `method "get"`},
+		{"unsupported method", with(func(r *CORSRule) { r.AllowedMethods = []string{"PATCH"} }), `method "PATCH"`},
+		{"two header wildcards", with(func(r *CORSRule) { r.AllowedHeaders = []string{"x-*-*"} }), `allowed header "x-*-*"`},
+		{"expose wildcard", with(func(r *CORSRule) { r.ExposeHeaders = []string{"*"} }), "wildcards are not allowed"},
+		{"header with space", with(func(r *CORSRule) { r.ExposeHeaders = []string{"Content Type"} }), `expose header "Content Type"`},
+		{"negative max age", with(func(r *CORSRule) { r.MaxAgeSeconds = -1 }), "maxAgeSeconds is negative"},
+		{"long id", with(func(r *CORSRule) { r.ID = strings.Repeat("x", 256) }), "longer than 255"},
+	}
+	for _, tt := range tests {
+		err := tt.rule.Validate()
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error %v, want one containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+
+	// Every mistake is reported, not just the first.
+	err := CORSRule{AllowedMethods: []string{"PATCH"}, MaxAgeSeconds: -1}.Validate()
+	for _, want := range []string{"allowedOrigins is empty", `method "PATCH"`, "maxAgeSeconds is negative"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("error %v, want one containing %q", err, want)
+		}
+	}
+}
+
+func TestMergeCORSRules(t *testing.T) {
+	get := CORSRule{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}}
+	put := CORSRule{AllowedOrigins: []string{"https://example.com"}, AllowedMethods: []string{"PUT"}}
+	app := CORSRule{ID: "app", AllowedOrigins: []string{"https://app.example.com"}, AllowedMethods: []string{"GET"}}
+	appPost := CORSRule{ID: "app", AllowedOrigins: []string{"https://app.example.com"}, AllowedMethods: []string{"POST"}}
+	getCached := CORSRule{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}, MaxAgeSeconds: 60}
+
+	tests := []struct {
+		name     string
+		existing []CORSRule
+		rules    []CORSRule
+		want     []CORSRule
+	}{
+		{"into nothing", nil, []CORSRule{get}, []CORSRule{get}},
+		{"new rule is added", []CORSRule{get}, []CORSRule{put}, []CORSRule{get, put}},
+		{"identical rule is not duplicated", []CORSRule{get, put}, []CORSRule{get}, []CORSRule{get, put}},
+		{"duplicates in the rules", nil, []CORSRule{put, put}, []CORSRule{put}},
+		{"rule differing in one field is added", []CORSRule{get}, []CORSRule{getCached}, []CORSRule{get, getCached}},
+		{"same id replaces in place", []CORSRule{app, get}, []CORSRule{appPost}, []CORSRule{appPost, get}},
+		{"id rule next to identical rule without id", []CORSRule{get}, []CORSRule{app}, []CORSRule{get, app}},
+		{"nothing to add", []CORSRule{get}, nil, []CORSRule{get}},
+	}
+	for _, tt := range tests {
+		existing := append([]CORSRule(nil), tt.existing...)
+		got := mergeCORSRules(existing, tt.rules)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+		if !reflect.DeepEqual(existing, tt.existing) {
+			t.Errorf("%s: existing rules changed to %+v", tt.name, existing)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// BucketEncryption is the default encryption of a bucket. SSE is empty for a
+// bucket without a default encryption configuration.
+type BucketEncryption struct {
+	Bucket    string
+	SSE       string
+	KMSKeyID  string `json:",omitempty"`
+	BucketKey bool
+}
+
+// SetBucketEncryptionOptions configures SetBucketEncryption.
+type SetBucketEncryptionOptions struct {
+	// KMSKeyID is the KMS key for aws:kms and aws:kms:dsse. Defaults to the
+	// AWS managed aws/s3 key.
+	KMSKeyID string
+	// BucketKey enables an S3 Bucket Key, which reduces KMS requests.
+	BucketKey bool
+}
+
+// ServerSideEncryption returns the S3 encryption type for a name such as
+// AES256 or aws:kms, ignoring case.
+func ServerSideEncryption(name string) (types.ServerSideEncryption, error) {
+	var valid []string
+	for _, v := range types.ServerSideEncryption("").Values() {
+		if strings.EqualFold(name, string(v)) {
+			return v, nil
+		}
+		valid = append(valid, string(v))
+	}
+	return "", fmt.Errorf("unsupported encryption %q, expected one of %s", name, strings.Join(valid, ", "))
+}
+
+// IsKMS reports whether sse encrypts with a KMS key.
+func IsKMS(sse types.ServerSideEncryption) bool {
+	return sse == types.ServerSideEncryptionAwsKms || sse == types.ServerSideEncryptionAwsKmsDsse
+}
+
+// BucketEncryption returns the default encryption of a bucket.
+func (c *Client) BucketEncryption(ctx context.Context, bucket string) (*BucketEncryption, error) {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+	enc := &BucketEncryption{Bucket: bucket}
+
+	result, err := client.GetBucketEncryption(ctx, &s3.GetBucketEncryptionInput{Bucket: &bucket})
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "ServerSideEncryptionConfigurationNotFoundError" {
+		return enc, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if result.ServerSideEncryptionConfiguration != nil {
+		for _, r := range result.ServerSideEncryptionConfiguration.Rules {
+			if d := r.ApplyServerSideEncryptionByDefault; d != nil {
+				enc.SSE = string(d.SSEAlgorithm)
+				enc.KMSKeyID = aws.ToString(d.KMSMasterKeyID)
+				enc.BucketKey = aws.ToBool(r.BucketKeyEnabled)
+			}
+		}
+	}
+	return enc, nil
+}
+
+// SetBucketEncryption sets the default encryption of a bucket.
+func (c *Client) SetBucketEncryption(ctx context.Context, bucket string, sse types.ServerSideEncryption, opts *SetBucketEncryptionOptions) error {
+	if opts == nil {
+		opts = &SetBucketEncryptionOptions{}
+	}
+	if opts.KMSKeyID != "" && !IsKMS(sse) {
+		return fmt.Errorf("a KMS key needs aws:kms or aws:kms:dsse, not %s", sse)
+	}
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	rule := types.ServerSideEncryptionRule{
+		ApplyServerSideEncryptionByDefault: &types.ServerSideEncryptionByDefault{SSEAlgorithm: sse},
+	}
+	if opts.KMSKeyID != "" {
+		rule.ApplyServerSideEncryptionByDefault.KMSMasterKeyID = &opts.KMSKeyID
+	}
+	if IsKMS(sse) {
+		rule.BucketKeyEnabled = aws.Bool(opts.BucketKey)
+	}
+
+	_, err = client.PutBucketEncryption(ctx, &s3.PutBucketEncryptionInput{
+		Bucket: &bucket,
+		ServerSideEncryptionConfiguration: &types.ServerSideEncryptionConfiguration{
+			Rules: []types.ServerSideEncryptionRule{rule},
+		},
+	})
+	return err
+}
+
+// DisableBucketEncryption removes the default encryption configuration of a
+// bucket. S3 still encrypts new objects with SSE-S3.
+func (c *Client) DisableBucketEncryption(ctx context.Context, bucket string) error {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	_, err = client.DeleteBucketEncryption(ctx, &s3.DeleteBucketEncryptionInput{Bucket: &bucket})
+	return err
+}
+
+// ScanEncryptionOptions configures ScanEncryption.
+type ScanEncryptionOptions struct {
+	// Sample is the most objects checked. Defaults to 100.
+	Sample int
+	// Concurrency is the number of objects checked at once. Defaults to 1.
+	Concurrency int
+}
+
+// EncryptionScan is the result of ScanEncryption.
+type EncryptionScan struct {
+	// Objects is the number of objects under the prefix, of which Sampled
+	// were checked.
+	Objects int
+	Sampled int
+	// ByType counts the encrypted objects by encryption, SSE-C included.
+	ByType      map[string]int
+	Unencrypted []string
+	Unreadable  []ObjectError
+}
+
+// ScanEncryption checks how a sample of the objects under prefix, picked
+// uniformly at random, are encrypted. Objects that can't be read are
+// reported rather than failing the scan.
+func (c *Client) ScanEncryption(ctx context.Context, bucket, prefix string, opts *ScanEncryptionOptions) (*EncryptionScan, error) {
+	if opts == nil {
+		opts = &ScanEncryptionOptions{}
+	}
+	n := opts.Sample
+	if n <= 0 {
+		n = 100
+	}
+	keys, seen, err := c.sampleKeys(ctx, bucket, prefix, n)
+	if err != nil {
+		return nil, err
+	}
+
+	scan := &EncryptionScan{Objects: seen, Sampled: len(keys), ByType: map[string]int{}}
+	queue := make(chan string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for range max(opts.Concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range queue {
+				head, err := c.HeadObject(ctx, bucket, key)
+
+				mu.Lock()
+				switch {
+				case err != nil:
+					scan.Unreadable = append(scan.Unreadable, ObjectError{Key: key, Message: err.Error()})
+				case head.SSECustomerAlgorithm != nil:
+					scan.ByType["SSE-C"]++
+				case head.ServerSideEncryption == "":
+					scan.Unencrypted = append(scan.Unencrypted, key)
+				default:
+					scan.ByType[string(head.ServerSideEncryption)]++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, key := range keys {
+		queue <- key
+	}
+	close(queue)
+	wg.Wait()
+
+	slices.Sort(scan.Unencrypted)
+	slices.SortFunc(scan.Unreadable, func(a, b ObjectError) int { return strings.Compare(a.Key, b.Key) })
+	return scan, nil
+}
+
+// sampleKeys picks up to n keys under prefix uniformly at random, holding no
+// more than n keys in memory however large the bucket is. It also returns the
+// number of objects seen.
+func (c *Client) sampleKeys(ctx context.Context, bucket, prefix string, n int) ([]string, int, error) {
+	var sample []string
+	seen := 0
+	err := c.WalkObjects(ctx, bucket, prefix, func(o types.Object) error {
+		seen++
+		if len(sample) < n {
+			sample = append(sample, aws.ToString(o.Key))
+		} else if i := rand.IntN(seen); i < n {
+			sample[i] = aws.ToString(o.Key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	slices.Sort(sample)
+	return sample, seen, nil
+}
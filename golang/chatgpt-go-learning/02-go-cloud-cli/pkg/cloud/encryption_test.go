@@ -0,0 +1,46 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSampleKeys(t *testing.T) {
+	objects := map[string]fakeObject{"other.txt": {Size: 1}}
+	for i := range 20 {
+		objects[fmt.Sprintf("logs/%02d.log", i)] = fakeObject{Size: 1}
+	}
+	_, c := newFakeS3(t, "docs", objects)
+
+	tests := []struct {
+		n         int
+		wantCount int
+	}{
+		{5, 5},
+		{20, 20},
+		{50, 20},
+	}
+	for _, tt := range tests {
+		keys, seen, err := c.sampleKeys(context.Background(), "docs", "logs/", tt.n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen != 20 {
+			t.Errorf("sample of %d saw %d objects, want 20", tt.n, seen)
+		}
+		if len(keys) != tt.wantCount || !slices.IsSorted(keys) || len(slices.Compact(slices.Clone(keys))) != len(keys) {
+			t.Errorf("sample of %d = %q, want %d sorted distinct keys", tt.n, keys, tt.wantCount)
+		}
+		for _, k := range keys {
+			if !strings.HasPrefix(k, "logs/") {
+				t.Errorf("sample of %d has %s, outside the prefix", tt.n, k)
+			}
+		}
+	}
+}
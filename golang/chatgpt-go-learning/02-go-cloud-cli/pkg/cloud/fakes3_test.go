@@ -0,0 +1,161 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"encoding/xml"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// fakeObject is an object of a fakeS3 bucket.
+type fakeObject struct {
+	Size int64
+	Tags map[string]string
+	// DenyTags makes reading the tags fail.
+	DenyTags bool
+}
+
+// fakeS3 serves ListObjectsV2 and GetObjectTagging for the objects of one
+// bucket, addressed path style. Tag requests take delay; they are counted in
+// tagRequests and the most in flight at once is kept in maxInFlight.
+type fakeS3 struct {
+	bucket  string
+	objects map[string]fakeObject
+	delay   time.Duration
+
+	mu          sync.Mutex
+	tagRequests int
+	inFlight    int
+	maxInFlight int
+}
+
+type listBucketResult struct {
+	XMLName        xml.Name `xml:"ListBucketResult"`
+	Name           string
+	Prefix         string
+	KeyCount       int
+	IsTruncated    bool
+	Contents       []listEntry
+	CommonPrefixes []commonPrefix
+}
+
+type listEntry struct {
+	Key          string
+	Size         int64
+	LastModified string
+}
+
+type commonPrefix struct {
+	Prefix string
+}
+
+type tagging struct {
+	XMLName xml.Name `xml:"Tagging"`
+	TagSet  []tag    `xml:"TagSet>Tag"`
+}
+
+type tag struct {
+	Key   string
+	Value string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/"+f.bucket)
+	key = strings.TrimPrefix(key, "/")
+	query := r.URL.Query()
+
+	switch {
+	case key == "" && query.Get("list-type") == "2":
+		f.list(w, query.Get("prefix"), query.Get("delimiter"))
+	case key != "" && query.Has("tagging") && r.Method == http.MethodGet:
+		f.tags(w, key)
+	default:
+		http.Error(w, "<Error><Code>NotImplemented</Code></Error>", http.StatusNotImplemented)
+	}
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, prefix, delimiter string) {
+	result := listBucketResult{Name: f.bucket, Prefix: prefix}
+	seen := map[string]bool{}
+	keys := make([]string, 0, len(f.objects))
+	for k := range f.objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		rest, ok := strings.CutPrefix(k, prefix)
+		if !ok {
+			continue
+		}
+		if i := strings.Index(rest, delimiter); delimiter != "" && i >= 0 {
+			p := prefix + rest[:i+len(delimiter)]
+			if !seen[p] {
+				seen[p] = true
+				result.CommonPrefixes = append(result.CommonPrefixes, commonPrefix{p})
+			}
+			continue
+		}
+		result.Contents = append(result.Contents, listEntry{k, f.objects[k].Size, "2025-01-01T00:00:00.000Z"})
+	}
+	result.KeyCount = len(result.Contents) + len(result.CommonPrefixes)
+	writeXML(w, result)
+}
+
+func (f *fakeS3) tags(w http.ResponseWriter, key string) {
+	f.mu.Lock()
+	f.tagRequests++
+	f.inFlight++
+	f.maxInFlight = max(f.maxInFlight, f.inFlight)
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.inFlight--
+		f.mu.Unlock()
+	}()
+	time.Sleep(f.delay)
+
+	o, ok := f.objects[key]
+	if !ok {
+		http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+		return
+	}
+	if o.DenyTags {
+		http.Error(w, "<Error><Code>AccessDenied</Code></Error>", http.StatusForbidden)
+		return
+	}
+	result := tagging{}
+	for _, k := range slices.Sorted(maps.Keys(o.Tags)) {
+		result.TagSet = append(result.TagSet, tag{k, o.Tags[k]})
+	}
+	writeXML(w, result)
+}
+
+func writeXML(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/xml")
+	data, _ := xml.Marshal(v)
+	w.Write(data)
+}
+
+// newFakeS3 serves objects from bucket and returns a Client pointed at it.
+func newFakeS3(t *testing.T, bucket string, objects map[string]fakeObject) (*fakeS3, *Client) {
+	t.Helper()
+	fake := &fakeS3{bucket: bucket, objects: objects}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	return fake, New(aws.Config{
+		Region:       "us-east-1",
+		Credentials:  testCredentials,
+		BaseEndpoint: aws.String(srv.URL),
+	})
+}
@@ -0,0 +1,264 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// maxDeleteKeys is the most keys DeleteObjects accepts in one request.
+const maxDeleteKeys = 1000
+
+// FindOptions configures FindObjects. An object matches when it passes every
+// predicate that is set.
+type FindOptions struct {
+	// Name is a glob pattern matched against the base name of the key.
+	Name string
+	// LargerThan matches objects larger than this many bytes.
+	LargerThan *int64
+	// OlderThan matches objects last modified longer ago than this.
+	OlderThan time.Duration
+	// StorageClass matches objects in this storage class, ignoring case.
+	StorageClass string
+	// Tags matches objects having all of these tags. It costs a request per
+	// object, so it is checked after every other predicate.
+	Tags map[string]string
+	// Concurrency is the number of prefixes listed, and of object tags
+	// fetched, at once.
+	Concurrency int
+}
+
+// FoundObject is an object matched by FindObjects.
+type FoundObject struct {
+	Bucket       string
+	Key          string
+	Size         int64
+	LastModified time.Time
+	StorageClass string
+	ETag         string
+}
+
+// objectPredicate is a cheap test that only needs the listing.
+type objectPredicate func(types.Object) bool
+
+func (opts *FindOptions) predicates() ([]objectPredicate, error) {
+	var predicates []objectPredicate
+
+	if opts.Name != "" {
+		if _, err := path.Match(opts.Name, ""); err != nil {
+			return nil, fmt.Errorf("invalid name pattern: %w", err)
+		}
+		predicates = append(predicates, func(o types.Object) bool {
+			ok, _ := path.Match(opts.Name, path.Base(aws.ToString(o.Key)))
+			return ok
+		})
+	}
+
+	if opts.LargerThan != nil {
+		size := *opts.LargerThan
+		predicates = append(predicates, func(o types.Object) bool {
+			return aws.ToInt64(o.Size) > size
+		})
+	}
+
+	if opts.OlderThan > 0 {
+		cutoff := time.Now().Add(-opts.OlderThan)
+		predicates = append(predicates, func(o types.Object) bool {
+			return o.LastModified != nil && o.LastModified.Before(cutoff)
+		})
+	}
+
+	if opts.StorageClass != "" {
+		class := strings.ToUpper(opts.StorageClass)
+		predicates = append(predicates, func(o types.Object) bool {
+			c := string(o.StorageClass)
+			if c == "" {
+				c = "STANDARD"
+			}
+			return c == class
+		})
+	}
+
+	return predicates, nil
+}
+
+// FindObjects lists the objects under prefix and returns those matching
+// opts, sorted by key.
+func (c *Client) FindObjects(ctx context.Context, bucket, prefix string, opts *FindOptions) ([]FoundObject, error) {
+	if opts == nil {
+		opts = &FindOptions{}
+	}
+	predicates, err := opts.predicates()
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := c.ListObjects(ctx, bucket, prefix, &ListObjectsOptions{Concurrency: opts.Concurrency})
+	if err != nil {
+		return nil, fmt.Errorf("listing objects: %w", err)
+	}
+
+	var candidates []types.Object
+objects:
+	for _, o := range objects {
+		for _, p := range predicates {
+			if !p(o) {
+				continue objects
+			}
+		}
+		candidates = append(candidates, o)
+	}
+	if len(opts.Tags) > 0 {
+		candidates, err = c.filterByTags(ctx, bucket, candidates, opts.Tags, opts.Concurrency)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var matches []FoundObject
+	for _, o := range candidates {
+		matches = append(matches, FoundObject{
+			Bucket:       bucket,
+			Key:          aws.ToString(o.Key),
+			Size:         aws.ToInt64(o.Size),
+			LastModified: aws.ToTime(o.LastModified),
+			StorageClass: string(o.StorageClass),
+			ETag:         strings.Trim(aws.ToString(o.ETag), `"`),
+		})
+	}
+	return matches, nil
+}
+
+// filterByTags keeps the objects having all of tags, in order. The tags are
+// fetched on a pool of concurrency workers, and the first lookup that fails
+// stops the others.
+func (c *Client) filterByTags(ctx context.Context, bucket string, objects []types.Object, tags map[string]string, concurrency int) ([]types.Object, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	keep := make([]bool, len(objects))
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for range max(concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				key := aws.ToString(objects[i].Key)
+				ok, err := c.hasTags(ctx, bucket, key, tags)
+				if err != nil {
+					cancel(fmt.Errorf("getting tags of %s: %w", key, err))
+					continue
+				}
+				keep[i] = ok
+			}
+		}()
+	}
+send:
+	for i := range objects {
+		select {
+		case queue <- i:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(queue)
+	wg.Wait()
+	if err := context.Cause(ctx); err != nil {
+		return nil, err
+	}
+
+	var kept []types.Object
+	for i, o := range objects {
+		if keep[i] {
+			kept = append(kept, o)
+		}
+	}
+	return kept, nil
+}
+
+func (c *Client) hasTags(ctx context.Context, bucket, key string, want map[string]string) (bool, error) {
+	have, err := c.ObjectTags(ctx, bucket, key)
+	if err != nil {
+		return false, err
+	}
+	for k, v := range want {
+		if got, ok := have[k]; !ok || got != v {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ObjectError records an object an operation failed on.
+type ObjectError struct {
+	Key     string
+	Message string
+}
+
+func (e ObjectError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Key, e.Message)
+}
+
+// ObjectsError is returned alongside the number of objects that succeeded
+// when some objects failed.
+type ObjectsError struct {
+	Failed []ObjectError
+	Total  int
+}
+
+func (e *ObjectsError) Error() string {
+	msgs := make([]string, len(e.Failed))
+	for i, f := range e.Failed {
+		msgs[i] = f.Error()
+	}
+	return fmt.Sprintf("%d of %d objects failed: %s", len(e.Failed), e.Total, strings.Join(msgs, "; "))
+}
+
+// DeleteObjects deletes keys from bucket in batches of 1000, the most a
+// single request accepts, and returns the number of objects deleted. Keys S3
+// refused to delete are reported in an *ObjectsError; any other error stops
+// the deletion.
+func (c *Client) DeleteObjects(ctx context.Context, bucket string, keys []string) (int, error) {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	var failed []ObjectError
+	for start := 0; start < len(keys); start += maxDeleteKeys {
+		end := min(start+maxDeleteKeys, len(keys))
+		var ids []types.ObjectIdentifier
+		for _, k := range keys[start:end] {
+			ids = append(ids, types.ObjectIdentifier{Key: aws.String(k)})
+		}
+
+		result, err := client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: &bucket,
+			Delete: &types.Delete{Objects: ids, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			return deleted, err
+		}
+		for _, e := range result.Errors {
+			failed = append(failed, ObjectError{Key: aws.ToString(e.Key), Message: aws.ToString(e.Message)})
+		}
+		deleted += len(ids) - len(result.Errors)
+	}
+
+	if len(failed) > 0 {
+		return deleted, &ObjectsError{Failed: failed, Total: len(keys)}
+	}
+	return deleted, nil
+}
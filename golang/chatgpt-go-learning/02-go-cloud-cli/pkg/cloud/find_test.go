@@ -0,0 +1,94 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFindObjectsByTags(t *testing.T) {
+	objects := map[string]fakeObject{}
+	var want []string
+	for i := range 24 {
+		key := fmt.Sprintf("logs/%02d.log", i)
+		tags := map[string]string{"team": "web"}
+		if i%3 == 0 {
+			tags["keep"] = "true"
+			want = append(want, key)
+		}
+		objects[key] = fakeObject{Size: int64(i), Tags: tags}
+	}
+	objects["logs/00.log"] = fakeObject{Size: 2048, Tags: map[string]string{"keep": "true"}}
+
+	tests := []struct {
+		concurrency int
+	}{
+		{0},
+		{1},
+		{4},
+		{8},
+	}
+	for _, tt := range tests {
+		fake, c := newFakeS3(t, "docs", objects)
+		fake.delay = 10 * time.Millisecond
+
+		found, err := c.FindObjects(context.Background(), "docs", "logs/", &FindOptions{
+			Tags:        map[string]string{"keep": "true"},
+			Concurrency: tt.concurrency,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var keys []string
+		for _, o := range found {
+			keys = append(keys, o.Key)
+		}
+		if !slices.Equal(keys, want) {
+			t.Errorf("concurrency %d found %q, want %q", tt.concurrency, keys, want)
+		}
+		if limit := max(tt.concurrency, 1); fake.maxInFlight > limit {
+			t.Errorf("concurrency %d had %d tag requests in flight", tt.concurrency, fake.maxInFlight)
+		} else if tt.concurrency > 1 && fake.maxInFlight < 2 {
+			t.Errorf("concurrency %d fetched tags one at a time", tt.concurrency)
+		}
+	}
+
+	// Cheap predicates are checked first, so only the large object's tags
+	// are fetched.
+	fake, c := newFakeS3(t, "docs", objects)
+	larger := int64(1024)
+	found, err := c.FindObjects(context.Background(), "docs", "logs/", &FindOptions{
+		LargerThan:  &larger,
+		Tags:        map[string]string{"keep": "true"},
+		Concurrency: 4,
+	})
+	if err != nil || len(found) != 1 || found[0].Key != "logs/00.log" {
+		t.Errorf("found %v with error %v, want logs/00.log", found, err)
+	}
+	if fake.tagRequests != 1 {
+		t.Errorf("fetched the tags of %d objects, want only the large one", fake.tagRequests)
+	}
+}
+
+func TestFindObjectsTagError(t *testing.T) {
+	objects := map[string]fakeObject{}
+	for i := range 50 {
+		objects[fmt.Sprintf("%02d", i)] = fakeObject{Tags: map[string]string{"keep": "true"}}
+	}
+	objects["17"] = fakeObject{DenyTags: true}
+	_, c := newFakeS3(t, "docs", objects)
+
+	_, err := c.FindObjects(context.Background(), "docs", "", &FindOptions{
+		Tags:        map[string]string{"keep": "true"},
+		Concurrency: 4,
+	})
+	if err == nil || !strings.Contains(err.Error(), "getting tags of 17") {
+		t.Errorf("got error %v, want one for 17", err)
+	}
+}
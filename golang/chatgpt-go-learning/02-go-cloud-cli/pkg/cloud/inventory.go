@@ -0,0 +1,286 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// Inventory is an inventory configuration of a bucket, with its destination
+// as an s3:// URI.
+type Inventory struct {
+	ID                 string
+	Enabled            bool
+	Schedule           string
+	Format             string
+	Destination        string
+	DestinationAccount string `json:",omitempty"`
+	Prefix             string `json:",omitempty"`
+	Versions           string
+	Fields             []string
+}
+
+// PutInventoryOptions configures PutInventory.
+type PutInventoryOptions struct {
+	// Destination is where the reports are written, as s3://bucket/prefix.
+	// It is required.
+	Destination string
+	// DestinationAccount is the account that owns the destination bucket, if
+	// it's another one. The region of such a bucket isn't checked.
+	DestinationAccount string
+	// Schedule is daily or weekly. Defaults to daily.
+	Schedule string
+	// Format is csv, orc or parquet. Defaults to csv.
+	Format string
+	// Fields are the fields listed besides the bucket and key, any of
+	// InventoryFieldNames.
+	Fields []string
+	// Prefix limits the reports to objects under it.
+	Prefix string
+	// Versions is current or all. Defaults to current.
+	Versions string
+	// Disabled creates the configuration without writing reports.
+	Disabled bool
+}
+
+// InventoryFieldNames returns the optional fields an inventory report can
+// list.
+func InventoryFieldNames() []string {
+	var names []string
+	for _, f := range types.InventoryOptionalField("").Values() {
+		names = append(names, string(f))
+	}
+	return names
+}
+
+// inventoryFields matches field names case-insensitively against the ones S3
+// knows.
+func inventoryFields(names []string) ([]types.InventoryOptionalField, error) {
+	var fields []types.InventoryOptionalField
+	for _, name := range names {
+		found := false
+		for _, f := range types.InventoryOptionalField("").Values() {
+			if strings.EqualFold(name, string(f)) {
+				fields = append(fields, f)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+	}
+	return fields, nil
+}
+
+func newInventory(c types.InventoryConfiguration) Inventory {
+	inventory := Inventory{
+		ID:       aws.ToString(c.Id),
+		Enabled:  aws.ToBool(c.IsEnabled),
+		Versions: string(c.IncludedObjectVersions),
+	}
+	if c.Schedule != nil {
+		inventory.Schedule = string(c.Schedule.Frequency)
+	}
+	if c.Filter != nil {
+		inventory.Prefix = aws.ToString(c.Filter.Prefix)
+	}
+	if c.Destination != nil && c.Destination.S3BucketDestination != nil {
+		d := c.Destination.S3BucketDestination
+		bucket := aws.ToString(d.Bucket)
+		if parsed, err := arn.Parse(bucket); err == nil {
+			bucket = parsed.Resource
+		}
+		inventory.Destination = "s3://" + bucket + "/" + aws.ToString(d.Prefix)
+		inventory.DestinationAccount = aws.ToString(d.AccountId)
+		inventory.Format = string(d.Format)
+	}
+	for _, f := range c.OptionalFields {
+		inventory.Fields = append(inventory.Fields, string(f))
+	}
+	return inventory
+}
+
+// Validate catches the options PutInventory would reject, without making
+// any request.
+func (opts *PutInventoryOptions) Validate() error {
+	_, err := opts.configuration("", "aws")
+	return err
+}
+
+// configuration returns the inventory configuration id the options describe.
+// The destination bucket is named by an ARN of partition.
+func (opts *PutInventoryOptions) configuration(id, partition string) (*types.InventoryConfiguration, error) {
+	destBucket, destPrefix, err := ParseS3URI(opts.Destination)
+	if err != nil {
+		return nil, fmt.Errorf("invalid destination: %w", err)
+	}
+	destPrefix = strings.TrimSuffix(destPrefix, "/")
+
+	frequency := types.InventoryFrequencyDaily
+	if opts.Schedule != "" {
+		frequency = ""
+		for _, f := range frequency.Values() {
+			if strings.EqualFold(opts.Schedule, string(f)) {
+				frequency = f
+			}
+		}
+		if frequency == "" {
+			return nil, fmt.Errorf("invalid schedule %q, expected daily or weekly", opts.Schedule)
+		}
+	}
+	format := types.InventoryFormatCsv
+	if opts.Format != "" {
+		format = ""
+		for _, f := range format.Values() {
+			if strings.EqualFold(opts.Format, string(f)) {
+				format = f
+			}
+		}
+		if format == "" {
+			return nil, fmt.Errorf("invalid format %q, expected csv, orc or parquet", opts.Format)
+		}
+	}
+	versions := types.InventoryIncludedObjectVersionsCurrent
+	if strings.EqualFold(opts.Versions, "all") {
+		versions = types.InventoryIncludedObjectVersionsAll
+	} else if opts.Versions != "" && !strings.EqualFold(opts.Versions, "current") {
+		return nil, fmt.Errorf("invalid versions %q, expected current or all", opts.Versions)
+	}
+	fields, err := inventoryFields(opts.Fields)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fields: %w", err)
+	}
+
+	destination := &types.InventoryS3BucketDestination{
+		Bucket: aws.String("arn:" + partition + ":s3:::" + destBucket),
+		Format: format,
+	}
+	if destPrefix != "" {
+		destination.Prefix = aws.String(destPrefix)
+	}
+	if opts.DestinationAccount != "" {
+		destination.AccountId = aws.String(opts.DestinationAccount)
+	}
+	config := &types.InventoryConfiguration{
+		Id:                     aws.String(id),
+		IsEnabled:              aws.Bool(!opts.Disabled),
+		Schedule:               &types.InventorySchedule{Frequency: frequency},
+		IncludedObjectVersions: versions,
+		OptionalFields:         fields,
+		Destination:            &types.InventoryDestination{S3BucketDestination: destination},
+	}
+	if opts.Prefix != "" {
+		config.Filter = &types.InventoryFilter{Prefix: aws.String(opts.Prefix)}
+	}
+	return config, nil
+}
+
+// Inventories returns the inventory configurations of a bucket.
+func (c *Client) Inventories(ctx context.Context, bucket string) ([]Inventory, error) {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	inventories := []Inventory{}
+	var token *string
+	for {
+		result, err := client.ListBucketInventoryConfigurations(ctx, &s3.ListBucketInventoryConfigurationsInput{
+			Bucket:            &bucket,
+			ContinuationToken: token,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range result.InventoryConfigurationList {
+			inventories = append(inventories, newInventory(c))
+		}
+		if !aws.ToBool(result.IsTruncated) {
+			return inventories, nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// Inventory returns the inventory configuration id of a bucket.
+func (c *Client) Inventory(ctx context.Context, bucket, id string) (*Inventory, error) {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+	result, err := client.GetBucketInventoryConfiguration(ctx, &s3.GetBucketInventoryConfigurationInput{
+		Bucket: &bucket,
+		Id:     &id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.InventoryConfiguration == nil {
+		return nil, errors.New("empty inventory configuration")
+	}
+	inventory := newInventory(*result.InventoryConfiguration)
+	return &inventory, nil
+}
+
+// PutInventory creates or replaces the inventory configuration id of a bucket
+// and returns it. Reports go to <destination>/<bucket>/<id>/, in a bucket of
+// the same region, which must let s3.amazonaws.com write to it; that isn't
+// checked here.
+func (c *Client) PutInventory(ctx context.Context, bucket, id string, opts *PutInventoryOptions) (*Inventory, error) {
+	if opts == nil {
+		opts = &PutInventoryOptions{}
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	// A bucket of another account can't be located from here.
+	if opts.DestinationAccount == "" {
+		destBucket, _, _ := ParseS3URI(opts.Destination)
+		if err := c.CheckSameRegion(ctx, bucket, destBucket); err != nil {
+			return nil, err
+		}
+	}
+
+	config, err := opts.configuration(id, Partition(client.Options().Region))
+	if err != nil {
+		return nil, err
+	}
+	_, err = client.PutBucketInventoryConfiguration(ctx, &s3.PutBucketInventoryConfigurationInput{
+		Bucket:                 &bucket,
+		Id:                     &id,
+		InventoryConfiguration: config,
+	})
+	if err != nil {
+		return nil, err
+	}
+	inventory := newInventory(*config)
+	return &inventory, nil
+}
+
+// DeleteInventory deletes the inventory configuration id of a bucket.
+func (c *Client) DeleteInventory(ctx context.Context, bucket, id string) error {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	_, err = client.DeleteBucketInventoryConfiguration(ctx, &s3.DeleteBucketInventoryConfigurationInput{
+		Bucket: &bucket,
+		Id:     &id,
+	})
+	return err
+}
@@ -0,0 +1,141 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// partitionDateSources maps the names of the dates log keys can be
+// partitioned by to their S3 value.
+var partitionDateSources = map[string]types.PartitionDateSource{
+	"event-time":    types.PartitionDateSourceEventTime,
+	"delivery-time": types.PartitionDateSourceDeliveryTime,
+}
+
+// Logging is the server access logging of a bucket.
+type Logging struct {
+	Bucket       string
+	Enabled      bool
+	TargetBucket string `json:",omitempty"`
+	TargetPrefix string `json:",omitempty"`
+	// Partitioned is event-time or delivery-time when log keys are
+	// partitioned by date.
+	Partitioned string `json:",omitempty"`
+}
+
+// EnableLoggingOptions configures EnableLogging.
+type EnableLoggingOptions struct {
+	// TargetPrefix is the prefix of the log objects.
+	TargetPrefix string
+	// Partitioned partitions log keys by date, as returned by
+	// PartitionDateSource. Keys are simple prefixes when it is empty.
+	Partitioned types.PartitionDateSource
+}
+
+// PartitionDateSource returns the date log keys are partitioned by for
+// event-time or delivery-time.
+func PartitionDateSource(name string) (types.PartitionDateSource, error) {
+	source, ok := partitionDateSources[name]
+	if !ok {
+		return "", fmt.Errorf("invalid partitioning %q, expected event-time or delivery-time", name)
+	}
+	return source, nil
+}
+
+// CheckSameRegion returns an error if buckets a and b live in different
+// regions. S3 only delivers access logs and inventory reports to a bucket in
+// the region of the bucket they are about.
+func (c *Client) CheckSameRegion(ctx context.Context, a, b string) error {
+	clientA, err := c.BucketClient(ctx, a)
+	if err != nil {
+		return fmt.Errorf("unable to locate bucket %s: %w", a, err)
+	}
+	clientB, err := c.BucketClient(ctx, b)
+	if err != nil {
+		return fmt.Errorf("unable to locate bucket %s: %w", b, err)
+	}
+	if regionA, regionB := clientA.Options().Region, clientB.Options().Region; regionA != regionB {
+		return fmt.Errorf("%s is in %s but %s is in %s", a, regionA, b, regionB)
+	}
+	return nil
+}
+
+// Logging returns the server access logging of a bucket.
+func (c *Client) Logging(ctx context.Context, bucket string) (*Logging, error) {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.GetBucketLogging(ctx, &s3.GetBucketLoggingInput{Bucket: &bucket})
+	if err != nil {
+		return nil, err
+	}
+
+	logging := &Logging{Bucket: bucket}
+	if enabled := result.LoggingEnabled; enabled != nil {
+		logging.Enabled = true
+		logging.TargetBucket = aws.ToString(enabled.TargetBucket)
+		logging.TargetPrefix = aws.ToString(enabled.TargetPrefix)
+		if format := enabled.TargetObjectKeyFormat; format != nil && format.PartitionedPrefix != nil {
+			for name, source := range partitionDateSources {
+				if source == format.PartitionedPrefix.PartitionDateSource {
+					logging.Partitioned = name
+				}
+			}
+		}
+	}
+	return logging, nil
+}
+
+// EnableLogging makes S3 write the access logs of bucket to targetBucket,
+// which must be in the same region. The target bucket must also let
+// logging.s3.amazonaws.com write to it, which isn't checked here.
+func (c *Client) EnableLogging(ctx context.Context, bucket, targetBucket string, opts *EnableLoggingOptions) error {
+	if opts == nil {
+		opts = &EnableLoggingOptions{}
+	}
+	format := &types.TargetObjectKeyFormat{SimplePrefix: &types.SimplePrefix{}}
+	if opts.Partitioned != "" {
+		format = &types.TargetObjectKeyFormat{PartitionedPrefix: &types.PartitionedPrefix{PartitionDateSource: opts.Partitioned}}
+	}
+	if err := c.CheckSameRegion(ctx, bucket, targetBucket); err != nil {
+		return err
+	}
+
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	_, err = client.PutBucketLogging(ctx, &s3.PutBucketLoggingInput{
+		Bucket: &bucket,
+		BucketLoggingStatus: &types.BucketLoggingStatus{
+			LoggingEnabled: &types.LoggingEnabled{
+				TargetBucket:          &targetBucket,
+				TargetPrefix:          &opts.TargetPrefix,
+				TargetObjectKeyFormat: format,
+			},
+		},
+	})
+	return err
+}
+
+// DisableLogging stops the server access logging of a bucket.
+func (c *Client) DisableLogging(ctx context.Context, bucket string) error {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	_, err = client.PutBucketLogging(ctx, &s3.PutBucketLoggingInput{
+		Bucket:              &bucket,
+		BucketLoggingStatus: &types.BucketLoggingStatus{},
+	})
+	return err
+}
@@ -0,0 +1,139 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// MaxCopySize is the largest object CopyObject can copy in one request.
+const MaxCopySize = 5 << 30
+
+// ObjectMetadata is the metadata of an object that can be edited: the system
+// headers S3 serves the object with and the user metadata.
+type ObjectMetadata struct {
+	ContentType        string            `json:",omitempty"`
+	CacheControl       string            `json:",omitempty"`
+	ContentDisposition string            `json:",omitempty"`
+	ContentEncoding    string            `json:",omitempty"`
+	ContentLanguage    string            `json:",omitempty"`
+	Metadata           map[string]string `json:",omitempty"`
+}
+
+// SetObjectMetadataOptions configures SetObjectMetadata.
+type SetObjectMetadataOptions struct {
+	// Remove lists user metadata keys to drop.
+	Remove []string
+}
+
+// override sets *dst to src unless src is empty.
+func override(dst *string, src string) {
+	if src != "" {
+		*dst = src
+	}
+}
+
+// nonEmpty returns a pointer to s, or nil if s is empty.
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// SetObjectMetadata rewrites the metadata of an object by copying it onto
+// itself. Fields left empty in md keep their current value and user metadata
+// is merged with the existing one. The storage class, encryption, tags and
+// checksum algorithm of the object are preserved, and the copy only happens
+// if the object didn't change since its metadata was read. It returns the
+// resulting metadata.
+func (c *Client) SetObjectMetadata(ctx context.Context, bucket, key string, md ObjectMetadata, opts *SetObjectMetadataOptions) (*ObjectMetadata, error) {
+	if opts == nil {
+		opts = &SetObjectMetadataOptions{}
+	}
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:       &bucket,
+		Key:          &key,
+		ChecksumMode: types.ChecksumModeEnabled,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if aws.ToInt64(head.ContentLength) > MaxCopySize {
+		return nil, fmt.Errorf("%s is larger than 5 GiB, which can't be copied in place", key)
+	}
+
+	merged := &ObjectMetadata{
+		ContentType:        aws.ToString(head.ContentType),
+		CacheControl:       aws.ToString(head.CacheControl),
+		ContentDisposition: aws.ToString(head.ContentDisposition),
+		ContentEncoding:    aws.ToString(head.ContentEncoding),
+		ContentLanguage:    aws.ToString(head.ContentLanguage),
+		Metadata:           maps.Clone(head.Metadata),
+	}
+	override(&merged.ContentType, md.ContentType)
+	override(&merged.CacheControl, md.CacheControl)
+	override(&merged.ContentDisposition, md.ContentDisposition)
+	override(&merged.ContentEncoding, md.ContentEncoding)
+	override(&merged.ContentLanguage, md.ContentLanguage)
+	if merged.Metadata == nil {
+		merged.Metadata = map[string]string{}
+	}
+	maps.Copy(merged.Metadata, md.Metadata)
+	for _, k := range opts.Remove {
+		delete(merged.Metadata, k)
+	}
+
+	input := &s3.CopyObjectInput{
+		Bucket:                  &bucket,
+		Key:                     &key,
+		CopySource:              aws.String(url.PathEscape(bucket + "/" + key)),
+		CopySourceIfMatch:       head.ETag,
+		MetadataDirective:       types.MetadataDirectiveReplace,
+		TaggingDirective:        types.TaggingDirectiveCopy,
+		ContentType:             nonEmpty(merged.ContentType),
+		CacheControl:            nonEmpty(merged.CacheControl),
+		ContentDisposition:      nonEmpty(merged.ContentDisposition),
+		ContentEncoding:         nonEmpty(merged.ContentEncoding),
+		ContentLanguage:         nonEmpty(merged.ContentLanguage),
+		Metadata:                merged.Metadata,
+		Expires:                 head.Expires,
+		StorageClass:            types.StorageClass(head.StorageClass),
+		WebsiteRedirectLocation: head.WebsiteRedirectLocation,
+	}
+	if IsKMS(head.ServerSideEncryption) {
+		input.ServerSideEncryption = head.ServerSideEncryption
+		input.SSEKMSKeyId = head.SSEKMSKeyId
+		input.BucketKeyEnabled = head.BucketKeyEnabled
+	}
+	switch {
+	case head.ChecksumSHA256 != nil:
+		input.ChecksumAlgorithm = types.ChecksumAlgorithmSha256
+	case head.ChecksumSHA1 != nil:
+		input.ChecksumAlgorithm = types.ChecksumAlgorithmSha1
+	case head.ChecksumCRC32C != nil:
+		input.ChecksumAlgorithm = types.ChecksumAlgorithmCrc32c
+	case head.ChecksumCRC32 != nil:
+		input.ChecksumAlgorithm = types.ChecksumAlgorithmCrc32
+	case head.ChecksumCRC64NVME != nil:
+		input.ChecksumAlgorithm = types.ChecksumAlgorithmCrc64nvme
+	}
+
+	if _, err := client.CopyObject(ctx, input); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
@@ -0,0 +1,273 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// Notification is a queue, topic or Lambda notification with its key filter
+// flattened into a prefix and a suffix.
+type Notification struct {
+	ID string
+	// Target is "queue", "topic" or "lambda". AddNotification takes it from
+	// the ARN.
+	Target string
+	Arn    string
+	Events []string
+	Prefix string `json:",omitempty"`
+	Suffix string `json:",omitempty"`
+}
+
+// BucketNotifications is the notification configuration of a bucket.
+type BucketNotifications struct {
+	Bucket        string
+	EventBridge   bool
+	Notifications []Notification
+}
+
+// notificationTarget returns "queue", "topic" or "lambda" for a target ARN.
+func notificationTarget(targetArn string) (string, error) {
+	parsed, err := arn.Parse(targetArn)
+	if err != nil {
+		return "", err
+	}
+	switch parsed.Service {
+	case "sqs":
+		return "queue", nil
+	case "sns":
+		return "topic", nil
+	case "lambda":
+		return "lambda", nil
+	default:
+		return "", fmt.Errorf("%s is not an SQS queue, SNS topic or Lambda function", targetArn)
+	}
+}
+
+func flattenFilter(filter *types.NotificationConfigurationFilter) (string, string) {
+	var prefix, suffix string
+	if filter == nil || filter.Key == nil {
+		return prefix, suffix
+	}
+	for _, r := range filter.Key.FilterRules {
+		switch strings.ToLower(string(r.Name)) {
+		case "prefix":
+			prefix = aws.ToString(r.Value)
+		case "suffix":
+			suffix = aws.ToString(r.Value)
+		}
+	}
+	return prefix, suffix
+}
+
+func buildFilter(prefix, suffix string) *types.NotificationConfigurationFilter {
+	var rules []types.FilterRule
+	if prefix != "" {
+		rules = append(rules, types.FilterRule{Name: types.FilterRuleNamePrefix, Value: aws.String(prefix)})
+	}
+	if suffix != "" {
+		rules = append(rules, types.FilterRule{Name: types.FilterRuleNameSuffix, Value: aws.String(suffix)})
+	}
+	if rules == nil {
+		return nil
+	}
+	return &types.NotificationConfigurationFilter{Key: &types.S3KeyFilter{FilterRules: rules}}
+}
+
+func eventStrings(events []types.Event) []string {
+	s := make([]string, 0, len(events))
+	for _, e := range events {
+		s = append(s, string(e))
+	}
+	return s
+}
+
+func s3Events(events []string) []types.Event {
+	e := make([]types.Event, 0, len(events))
+	for _, s := range events {
+		e = append(e, types.Event(s))
+	}
+	return e
+}
+
+func notificationRules(config *types.NotificationConfiguration) []Notification {
+	var rules []Notification
+	for _, c := range config.QueueConfigurations {
+		prefix, suffix := flattenFilter(c.Filter)
+		rules = append(rules, Notification{aws.ToString(c.Id), "queue", aws.ToString(c.QueueArn), eventStrings(c.Events), prefix, suffix})
+	}
+	for _, c := range config.TopicConfigurations {
+		prefix, suffix := flattenFilter(c.Filter)
+		rules = append(rules, Notification{aws.ToString(c.Id), "topic", aws.ToString(c.TopicArn), eventStrings(c.Events), prefix, suffix})
+	}
+	for _, c := range config.LambdaFunctionConfigurations {
+		prefix, suffix := flattenFilter(c.Filter)
+		rules = append(rules, Notification{aws.ToString(c.Id), "lambda", aws.ToString(c.LambdaFunctionArn), eventStrings(c.Events), prefix, suffix})
+	}
+	return rules
+}
+
+// notificationConfiguration rebuilds a configuration from rules, keeping the
+// EventBridge setting of the configuration it was read from.
+func notificationConfiguration(rules []Notification, eventBridge *types.EventBridgeConfiguration) *types.NotificationConfiguration {
+	config := &types.NotificationConfiguration{EventBridgeConfiguration: eventBridge}
+	for _, r := range rules {
+		id := nonEmpty(r.ID)
+		filter := buildFilter(r.Prefix, r.Suffix)
+		switch r.Target {
+		case "queue":
+			config.QueueConfigurations = append(config.QueueConfigurations, types.QueueConfiguration{
+				Id: id, QueueArn: aws.String(r.Arn), Events: s3Events(r.Events), Filter: filter,
+			})
+		case "topic":
+			config.TopicConfigurations = append(config.TopicConfigurations, types.TopicConfiguration{
+				Id: id, TopicArn: aws.String(r.Arn), Events: s3Events(r.Events), Filter: filter,
+			})
+		case "lambda":
+			config.LambdaFunctionConfigurations = append(config.LambdaFunctionConfigurations, types.LambdaFunctionConfiguration{
+				Id: id, LambdaFunctionArn: aws.String(r.Arn), Events: s3Events(r.Events), Filter: filter,
+			})
+		}
+	}
+	return config
+}
+
+// eventsOverlap reports whether two event types can match the same event,
+// e.g. s3:ObjectCreated:* and s3:ObjectCreated:Put.
+func eventsOverlap(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(a, "*"); ok && strings.HasPrefix(b, prefix) {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(b, "*"); ok && strings.HasPrefix(a, prefix) {
+		return true
+	}
+	return false
+}
+
+// rulesOverlap reports whether S3 would reject a and b as overlapping: they
+// share an event type and a key could match both their prefixes and both
+// their suffixes.
+func rulesOverlap(a, b Notification) bool {
+	shareEvent := slices.ContainsFunc(a.Events, func(ea string) bool {
+		return slices.ContainsFunc(b.Events, func(eb string) bool { return eventsOverlap(ea, eb) })
+	})
+	prefixes := strings.HasPrefix(a.Prefix, b.Prefix) || strings.HasPrefix(b.Prefix, a.Prefix)
+	suffixes := strings.HasSuffix(a.Suffix, b.Suffix) || strings.HasSuffix(b.Suffix, a.Suffix)
+	return shareEvent && prefixes && suffixes
+}
+
+// notifications returns the notifications of a bucket and its EventBridge
+// setting, which has to be written back along with them.
+func (c *Client) notifications(ctx context.Context, bucket string) ([]Notification, *types.EventBridgeConfiguration, error) {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := client.GetBucketNotificationConfiguration(ctx, &s3.GetBucketNotificationConfigurationInput{
+		Bucket: &bucket,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rules := notificationRules(&types.NotificationConfiguration{
+		QueueConfigurations:          result.QueueConfigurations,
+		TopicConfigurations:          result.TopicConfigurations,
+		LambdaFunctionConfigurations: result.LambdaFunctionConfigurations,
+	})
+	return rules, result.EventBridgeConfiguration, nil
+}
+
+func (c *Client) putNotifications(ctx context.Context, bucket string, rules []Notification, eventBridge *types.EventBridgeConfiguration) error {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	_, err = client.PutBucketNotificationConfiguration(ctx, &s3.PutBucketNotificationConfigurationInput{
+		Bucket:                    &bucket,
+		NotificationConfiguration: notificationConfiguration(rules, eventBridge),
+	})
+	return err
+}
+
+// Notifications returns the event notifications of a bucket.
+func (c *Client) Notifications(ctx context.Context, bucket string) (*BucketNotifications, error) {
+	rules, eventBridge, err := c.notifications(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+	return &BucketNotifications{Bucket: bucket, EventBridge: eventBridge != nil, Notifications: rules}, nil
+}
+
+// AddNotification adds n to the notifications of a bucket, keeping the
+// existing ones, and returns the resulting notifications. S3 rejects
+// notifications whose event types, prefix and suffix all overlap with
+// another one; such overlaps are reported before anything is changed.
+func (c *Client) AddNotification(ctx context.Context, bucket string, n Notification) ([]Notification, error) {
+	target, err := notificationTarget(n.Arn)
+	if err != nil {
+		return nil, err
+	}
+	n.Target = target
+	if len(n.Events) == 0 {
+		return nil, errors.New("a notification needs at least one event type")
+	}
+	for _, e := range n.Events {
+		if !strings.HasPrefix(e, "s3:") {
+			return nil, fmt.Errorf("invalid event type %q, expected something like s3:ObjectCreated:*", e)
+		}
+	}
+
+	rules, eventBridge, err := c.notifications(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range rules {
+		if n.ID != "" && existing.ID == n.ID {
+			return nil, fmt.Errorf("%s already has a notification with id %s", bucket, n.ID)
+		}
+		if rulesOverlap(n, existing) {
+			return nil, fmt.Errorf("the notification overlaps with %q (%s, prefix %q, suffix %q); S3 would reject it",
+				existing.ID, strings.Join(existing.Events, ","), existing.Prefix, existing.Suffix)
+		}
+	}
+	rules = append(rules, n)
+
+	if err := c.putNotifications(ctx, bucket, rules, eventBridge); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// RemoveNotification removes the notification with id from a bucket and
+// returns the remaining notifications.
+func (c *Client) RemoveNotification(ctx context.Context, bucket, id string) ([]Notification, error) {
+	existing, eventBridge, err := c.notifications(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := slices.DeleteFunc(slices.Clone(existing), func(r Notification) bool { return r.ID == id })
+	if len(rules) == len(existing) {
+		return nil, fmt.Errorf("%s has no notification with id %s", bucket, id)
+	}
+
+	if err := c.putNotifications(ctx, bucket, rules, eventBridge); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import "testing"
+
+func TestEventsOverlap(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"s3:ObjectCreated:Put", "s3:ObjectCreated:Put", true},
+		{"s3:ObjectCreated:*", "s3:ObjectCreated:Put", true},
+		{"s3:ObjectCreated:Copy", "s3:ObjectCreated:*", true},
+		{"s3:ObjectCreated:*", "s3:ObjectCreated:*", true},
+		{"s3:ObjectRemoved:*", "s3:ObjectRemoved:DeleteMarkerCreated", true},
+		{"s3:ObjectCreated:Put", "s3:ObjectCreated:Post", false},
+		{"s3:ObjectCreated:*", "s3:ObjectRemoved:Delete", false},
+		{"s3:ObjectCreated:*", "s3:ObjectRemoved:*", false},
+		{"s3:ObjectRestore:*", "s3:ObjectRemoved:*", false},
+		{"s3:ObjectRestore:Post", "s3:ObjectRestore:Completed", false},
+	}
+	for _, tt := range tests {
+		if got := eventsOverlap(tt.a, tt.b); got != tt.want {
+			t.Errorf("eventsOverlap(%q, %q) = %t, want %t", tt.a, tt.b, got, tt.want)
+		}
+		if got := eventsOverlap(tt.b, tt.a); got != tt.want {
+			t.Errorf("eventsOverlap(%q, %q) = %t, want %t", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestRulesOverlap(t *testing.T) {
+	created := []string{"s3:ObjectCreated:*"}
+	put := []string{"s3:ObjectCreated:Put"}
+	removed := []string{"s3:ObjectRemoved:*"}
+
+	tests := []struct {
+		name string
+		a, b Notification
+		want bool
+	}{
+		{"no filters", Notification{Events: created}, Notification{Events: put}, true},
+		{"different events", Notification{Events: created}, Notification{Events: removed}, false},
+		{"one shared event", Notification{Events: []string{"s3:ObjectRemoved:*", "s3:ObjectCreated:Copy"}}, Notification{Events: created}, true},
+		{"same prefix", Notification{Events: created, Prefix: "images/"}, Notification{Events: created, Prefix: "images/"}, true},
+		{"nested prefixes", Notification{Events: created, Prefix: "images/"}, Notification{Events: created, Prefix: "images/thumbs/"}, true},
+		{"prefix against none", Notification{Events: created, Prefix: "images/"}, Notification{Events: created}, true},
+		{"disjoint prefixes", Notification{Events: created, Prefix: "images/"}, Notification{Events: created, Prefix: "logs/"}, false},
+		{"sibling prefixes", Notification{Events: created, Prefix: "images/a"}, Notification{Events: created, Prefix: "images/b"}, false},
+		{"nested suffixes", Notification{Events: created, Suffix: ".jpg"}, Notification{Events: created, Suffix: "thumb.jpg"}, true},
+		{"suffix against none", Notification{Events: created, Suffix: ".jpg"}, Notification{Events: created}, true},
+		{"disjoint suffixes", Notification{Events: created, Suffix: ".jpg"}, Notification{Events: created, Suffix: ".png"}, false},
+		{"disjoint suffixes, same prefix", Notification{Events: created, Prefix: "images/", Suffix: ".jpg"}, Notification{Events: put, Prefix: "images/", Suffix: ".png"}, false},
+		{"prefix on one, suffix on the other", Notification{Events: created, Prefix: "images/"}, Notification{Events: put, Suffix: ".jpg"}, true},
+		{"disjoint prefixes, same suffix", Notification{Events: created, Prefix: "a/", Suffix: ".jpg"}, Notification{Events: created, Prefix: "b/", Suffix: ".jpg"}, false},
+	}
+	for _, tt := range tests {
+		if got := rulesOverlap(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: rulesOverlap(a, b) = %t, want %t", tt.name, got, tt.want)
+		}
+		if got := rulesOverlap(tt.b, tt.a); got != tt.want {
+			t.Errorf("%s: rulesOverlap(b, a) = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// ListObjectsOptions configures ListObjects.
+type ListObjectsOptions struct {
+	// Concurrency is the number of prefixes listed at once. Defaults to 1.
+	Concurrency int
+}
+
+// ParseS3URI splits s3://bucket/prefix into its bucket and prefix.
+func ParseS3URI(uri string) (bucket, prefix string, err error) {
+	rest, ok := strings.CutPrefix(uri, "s3://")
+	if !ok {
+		return "", "", fmt.Errorf("%q is not an s3:// URI", uri)
+	}
+	bucket, prefix, _ = strings.Cut(rest, "/")
+	if bucket == "" {
+		return "", "", fmt.Errorf("%q has no bucket", uri)
+	}
+	return bucket, prefix, nil
+}
+
+// ListObjects returns every object under prefix, sorted by key. The first
+// level of "directories" below prefix is listed concurrently, which is much
+// faster than one long paginated listing for buckets with a wide key space.
+func (c *Client) ListObjects(ctx context.Context, bucket, prefix string, opts *ListObjectsOptions) ([]types.Object, error) {
+	if opts == nil {
+		opts = &ListObjectsOptions{}
+	}
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []types.Object
+	var shards []string
+
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket:    &bucket,
+		Prefix:    &prefix,
+		Delimiter: aws.String("/"),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, page.Contents...)
+		for _, p := range page.CommonPrefixes {
+			shards = append(shards, aws.ToString(p.Prefix))
+		}
+	}
+
+	concurrency := max(opts.Concurrency, 1)
+	results := make([][]types.Object, len(shards))
+	errs := make([]error, len(shards))
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, shard := range shards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i], errs[i] = listShard(ctx, client, bucket, shard)
+		}()
+	}
+	wg.Wait()
+
+	for i := range shards {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("listing %s: %w", shards[i], errs[i])
+		}
+		objects = append(objects, results[i]...)
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return aws.ToString(objects[i].Key) < aws.ToString(objects[j].Key)
+	})
+	return objects, nil
+}
+
+// HeadObject returns the metadata of an object.
+func (c *Client) HeadObject(ctx context.Context, bucket, key string) (*s3.HeadObjectOutput, error) {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+	return client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: &bucket, Key: &key})
+}
+
+// HasObjects reports whether there is any object under prefix.
+func (c *Client) HasObjects(ctx context.Context, bucket, prefix string) (bool, error) {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return false, err
+	}
+	result, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+		Bucket:  &bucket,
+		Prefix:  &prefix,
+		MaxKeys: aws.Int32(1),
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(result.Contents) > 0, nil
+}
+
+// WalkObjects calls fn with every object under prefix, in key order, a page at
+// a time, so memory use doesn't depend on the number of objects. It stops at
+// the first error, including one returned by fn.
+func (c *Client) WalkObjects(ctx context.Context, bucket, prefix string, fn func(types.Object) error) error {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket: &bucket,
+		Prefix: &prefix,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, o := range page.Contents {
+			if err := fn(o); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func listShard(ctx context.Context, client *s3.Client, bucket, prefix string) ([]types.Object, error) {
+	var objects []types.Object
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket: &bucket,
+		Prefix: &prefix,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, page.Contents...)
+	}
+	return objects, nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestWalkObjects(t *testing.T) {
+	_, c := newFakeS3(t, "docs", map[string]fakeObject{
+		"b/2.txt": {Size: 2},
+		"a.txt":   {Size: 1},
+		"b/1.txt": {Size: 1},
+		"c/3.txt": {Size: 3},
+	})
+
+	var keys []string
+	err := c.WalkObjects(context.Background(), "docs", "b/", func(o types.Object) error {
+		keys = append(keys, aws.ToString(o.Key))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"b/1.txt", "b/2.txt"}; !slices.Equal(keys, want) {
+		t.Errorf("walked %q, want %q", keys, want)
+	}
+
+	stop := errors.New("stop")
+	keys = nil
+	err = c.WalkObjects(context.Background(), "docs", "", func(o types.Object) error {
+		keys = append(keys, aws.ToString(o.Key))
+		return stop
+	})
+	if !errors.Is(err, stop) || len(keys) != 1 {
+		t.Errorf("walked %q with error %v, want to stop after the first object", keys, err)
+	}
+}
+
+func TestHasObjects(t *testing.T) {
+	_, c := newFakeS3(t, "docs", map[string]fakeObject{
+		"a/1.txt": {Size: 1},
+		"a/2.txt": {Size: 2},
+	})
+
+	tests := []struct {
+		prefix string
+		want   bool
+	}{
+		{"", true},
+		{"a/", true},
+		{"a/2", true},
+		{"b/", false},
+	}
+	for _, tt := range tests {
+		got, err := c.HasObjects(context.Background(), "docs", tt.prefix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("HasObjects(%q) = %t, want %t", tt.prefix, got, tt.want)
+		}
+	}
+}
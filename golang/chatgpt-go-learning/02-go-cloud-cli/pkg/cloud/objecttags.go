@@ -0,0 +1,104 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// MaxObjectTags is the most tags S3 allows on an object.
+const MaxObjectTags = 10
+
+// TagObjectOptions configures TagObject.
+type TagObjectOptions struct {
+	// Replace drops the existing tags that aren't given instead of keeping
+	// them.
+	Replace bool
+}
+
+// ObjectTags returns the tags of an object.
+func (c *Client) ObjectTags(ctx context.Context, bucket, key string) (map[string]string, error) {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.GetObjectTagging(ctx, &s3.GetObjectTaggingInput{Bucket: &bucket, Key: &key})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := map[string]string{}
+	for _, t := range result.TagSet {
+		tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+	return tags, nil
+}
+
+// PutObjectTags replaces the tags of an object. Removing the last tag deletes
+// the tag set.
+func (c *Client) PutObjectTags(ctx context.Context, bucket, key string, tags map[string]string) error {
+	if len(tags) > MaxObjectTags {
+		return fmt.Errorf("an object can have at most %d tags, got %d", MaxObjectTags, len(tags))
+	}
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	if len(tags) == 0 {
+		_, err := client.DeleteObjectTagging(ctx, &s3.DeleteObjectTaggingInput{Bucket: &bucket, Key: &key})
+		return err
+	}
+
+	var tagSet []types.Tag
+	for _, k := range slices.Sorted(maps.Keys(tags)) {
+		tagSet = append(tagSet, types.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+	_, err = client.PutObjectTagging(ctx, &s3.PutObjectTaggingInput{
+		Bucket:  &bucket,
+		Key:     &key,
+		Tagging: &types.Tagging{TagSet: tagSet},
+	})
+	return err
+}
+
+// TagObject sets tags on an object, keeping its other tags unless
+// opts.Replace is set, and returns the resulting tags.
+func (c *Client) TagObject(ctx context.Context, bucket, key string, tags map[string]string, opts *TagObjectOptions) (map[string]string, error) {
+	if opts == nil {
+		opts = &TagObjectOptions{}
+	}
+
+	merged := maps.Clone(tags)
+	if !opts.Replace {
+		existing, err := c.ObjectTags(ctx, bucket, key)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(existing, tags)
+		merged = existing
+	}
+
+	return merged, c.PutObjectTags(ctx, bucket, key, merged)
+}
+
+// UntagObject removes tags from an object and returns the remaining tags.
+func (c *Client) UntagObject(ctx context.Context, bucket, key string, keys []string) (map[string]string, error) {
+	tags, err := c.ObjectTags(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range keys {
+		delete(tags, k)
+	}
+	return tags, c.PutObjectTags(ctx, bucket, key, tags)
+}
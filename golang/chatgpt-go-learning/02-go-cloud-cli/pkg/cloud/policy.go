@@ -0,0 +1,80 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+)
+
+// Partition returns the ARN partition of a region.
+func Partition(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return "aws-cn"
+	case strings.HasPrefix(region, "us-gov-"):
+		return "aws-us-gov"
+	}
+	return "aws"
+}
+
+// BucketPolicy returns the policy of a bucket and its statements. A bucket
+// without a policy gets an empty one to add statements to.
+func (c *Client) BucketPolicy(ctx context.Context, bucket string) (map[string]any, []any, error) {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := client.GetBucketPolicy(ctx, &s3.GetBucketPolicyInput{Bucket: &bucket})
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchBucketPolicy" {
+		return map[string]any{"Version": "2012-10-17"}, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var policy map[string]any
+	if err := json.Unmarshal([]byte(aws.ToString(result.Policy)), &policy); err != nil {
+		return nil, nil, fmt.Errorf("invalid bucket policy: %w", err)
+	}
+	// A policy with a single statement may hold it without an array.
+	statements, ok := policy["Statement"].([]any)
+	if !ok && policy["Statement"] != nil {
+		statements = []any{policy["Statement"]}
+	}
+	return policy, statements, nil
+}
+
+// PutBucketPolicy replaces the policy of a bucket.
+func (c *Client) PutBucketPolicy(ctx context.Context, bucket string, policy map[string]any) error {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	_, err = client.PutBucketPolicy(ctx, &s3.PutBucketPolicyInput{Bucket: &bucket, Policy: aws.String(string(data))})
+	return err
+}
+
+// hasStatement reports whether one of statements has the Sid sid.
+func hasStatement(statements []any, sid string) bool {
+	for _, s := range statements {
+		if m, ok := s.(map[string]any); ok && m["Sid"] == sid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// emptyPayloadHash is the SHA256 of an empty request body, as required by SigV4.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// RegionRow tags a single result row with the region it came from.
+type RegionRow[T any] struct {
+	Region string
+	Item   T
+}
+
+// RegionError records a region whose call failed.
+type RegionError struct {
+	Region string
+	Err    error
+}
+
+func (e RegionError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Region, e.Err)
+}
+
+func (e RegionError) Unwrap() error {
+	return e.Err
+}
+
+// RegionsError is returned alongside the results of the regions that
+// succeeded when some regions failed.
+type RegionsError struct {
+	Failed []RegionError
+	Total  int
+}
+
+func (e *RegionsError) Error() string {
+	msgs := make([]string, len(e.Failed))
+	for i, f := range e.Failed {
+		msgs[i] = f.Error()
+	}
+	return fmt.Sprintf("%d of %d regions failed: %s", len(e.Failed), e.Total, strings.Join(msgs, "; "))
+}
+
+type describeRegionsResponse struct {
+	Regions []struct {
+		Name string `xml:"regionName"`
+	} `xml:"regionInfo>item"`
+}
+
+// ec2Endpoint returns the EC2 endpoint of region in its partition, or the
+// endpoint override, as emulators serve every service on one endpoint.
+func (c *Client) ec2Endpoint(region string) string {
+	if c.customEndpoint() {
+		return strings.TrimSuffix(aws.ToString(c.cfg.BaseEndpoint), "/")
+	}
+	suffix := "amazonaws.com"
+	if strings.HasPrefix(region, "cn-") {
+		suffix = "amazonaws.com.cn"
+	}
+	return fmt.Sprintf("https://ec2.%s.%s", region, suffix)
+}
+
+// DiscoverRegions asks EC2 for the regions enabled in the account. The call is
+// signed by hand so that we don't have to pull in the whole EC2 client for a
+// single read-only query.
+func (c *Client) DiscoverRegions(ctx context.Context) ([]string, error) {
+	region := c.cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	creds, err := c.cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving credentials: %w", err)
+	}
+
+	url := c.ec2Endpoint(region) + "/?Action=DescribeRegions&Version=2016-11-15"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := v4.NewSigner().SignHTTP(ctx, creds, req, emptyPayloadHash, "ec2", region, time.Now()); err != nil {
+		return nil, fmt.Errorf("signing DescribeRegions: %w", err)
+	}
+
+	var client aws.HTTPClient = http.DefaultClient
+	if c.cfg.HTTPClient != nil {
+		client = c.cfg.HTTPClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("describing regions: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("describing regions: %s", resp.Status)
+	}
+
+	var out describeRegionsResponse
+	if err := xml.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("decoding DescribeRegions response: %w", err)
+	}
+
+	regions := make([]string, 0, len(out.Regions))
+	for _, r := range out.Regions {
+		regions = append(regions, r.Name)
+	}
+	sort.Strings(regions)
+	return regions, nil
+}
+
+// FanOutRegions runs fn once per region, at most limit at a time, with a copy
+// of cfg set to that region, and tags every returned item with its region. A
+// failing region does not stop the others: the rows of the regions that
+// succeeded are returned with a *RegionsError.
+func FanOutRegions[T any](ctx context.Context, cfg aws.Config, regions []string, limit int, fn func(context.Context, aws.Config) ([]T, error)) ([]RegionRow[T], error) {
+	if limit < 1 {
+		limit = 1
+	}
+
+	results := make([][]T, len(regions))
+	errs := make([]error, len(regions))
+
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, region := range regions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			regionCfg := cfg.Copy()
+			regionCfg.Region = region
+			results[i], errs[i] = fn(ctx, regionCfg)
+		}()
+	}
+	wg.Wait()
+
+	var rows []RegionRow[T]
+	var failed []RegionError
+	for i, region := range regions {
+		if errs[i] != nil {
+			failed = append(failed, RegionError{region, errs[i]})
+			continue
+		}
+		for _, item := range results[i] {
+			rows = append(rows, RegionRow[T]{region, item})
+		}
+	}
+	if len(failed) > 0 {
+		return rows, &RegionsError{failed, len(regions)}
+	}
+	return rows, nil
+}
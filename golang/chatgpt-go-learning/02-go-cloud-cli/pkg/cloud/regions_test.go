@@ -0,0 +1,62 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+var testCredentials = aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+	return aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET"}, nil
+})
+
+func TestEC2Endpoint(t *testing.T) {
+	tests := map[string]string{
+		"eu-west-1":     "https://ec2.eu-west-1.amazonaws.com",
+		"cn-north-1":    "https://ec2.cn-north-1.amazonaws.com.cn",
+		"us-gov-west-1": "https://ec2.us-gov-west-1.amazonaws.com",
+	}
+	for region, want := range tests {
+		if got := New(aws.Config{Region: region}).ec2Endpoint(region); got != want {
+			t.Errorf("ec2Endpoint(%q) = %q, want %q", region, got, want)
+		}
+	}
+}
+
+func TestDiscoverRegionsUsesEndpointOverride(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if action := r.URL.Query().Get("Action"); action != "DescribeRegions" {
+			t.Errorf("Action = %q, want DescribeRegions", action)
+		}
+		if auth := r.Header.Get("Authorization"); !strings.Contains(auth, "/eu-west-1/ec2/aws4_request") {
+			t.Errorf("request not signed for ec2 in eu-west-1: %q", auth)
+		}
+		fmt.Fprint(w, `<DescribeRegionsResponse><regionInfo>
+<item><regionName>us-east-1</regionName></item>
+<item><regionName>eu-west-1</regionName></item>
+</regionInfo></DescribeRegionsResponse>`)
+	}))
+	defer srv.Close()
+
+	client := New(aws.Config{
+		Region:       "eu-west-1",
+		Credentials:  testCredentials,
+		BaseEndpoint: aws.String(srv.URL + "/"),
+	})
+	regions, err := client.DiscoverRegions(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"eu-west-1", "us-east-1"}; !slices.Equal(regions, want) {
+		t.Errorf("DiscoverRegions() = %q, want %q", regions, want)
+	}
+}
@@ -0,0 +1,261 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// ReplicationOptions configures PlanReplication.
+type ReplicationOptions struct {
+	// Role is the ARN of the IAM role S3 assumes to replicate objects. It is
+	// required.
+	Role string
+	// RuleID is the ID of the rule. Defaults to replicate-to-<destination>.
+	RuleID string
+	// Prefix limits replication to keys with this prefix.
+	Prefix string
+	// StorageClass is the storage class of the replicas. Defaults to the
+	// storage class of the source object.
+	StorageClass string
+}
+
+// ReplicationPlan is a replication rule ready to be applied, along with the
+// changes applying it makes besides adding the rule.
+type ReplicationPlan struct {
+	Source            string
+	Destination       string
+	SourceRegion      string
+	DestinationRegion string
+	RuleID            string
+	// Unversioned are the buckets versioning gets enabled on. Versioning
+	// can't be turned off again, only suspended.
+	Unversioned []string
+	// ReplacedRole is the role the existing rules replicate with, if it isn't
+	// the role of the plan. Every rule switches to the new role.
+	ReplacedRole string
+	// PrefixOnly is the number of existing rules of the first replication
+	// schema, which only have a Prefix. S3 doesn't accept them along with
+	// rules that have a Filter, so they are converted to filters, still
+	// replicating delete markers.
+	PrefixOnly int
+
+	config *types.ReplicationConfiguration
+}
+
+// ReplicationSample is the replication status of one sampled object.
+type ReplicationSample struct {
+	Key    string
+	Status string
+}
+
+// versioned reports whether versioning is enabled on a bucket.
+func (c *Client) versioned(ctx context.Context, bucket string) (bool, error) {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return false, err
+	}
+	result, err := client.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{Bucket: &bucket})
+	if err != nil {
+		return false, err
+	}
+	return result.Status == types.BucketVersioningStatusEnabled, nil
+}
+
+// currentReplication returns the replication configuration of a bucket, or
+// nil if it has none.
+func currentReplication(ctx context.Context, client *s3.Client, bucket string) (*types.ReplicationConfiguration, error) {
+	result, err := client.GetBucketReplication(ctx, &s3.GetBucketReplicationInput{Bucket: &bucket})
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "ReplicationConfigurationNotFoundError" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result.ReplicationConfiguration, nil
+}
+
+// filterRule converts a rule of the first replication schema, which only has
+// a Prefix, to one with a Filter. Delete markers stay replicated, as they are
+// under the first schema.
+func filterRule(r types.ReplicationRule) types.ReplicationRule {
+	r.Filter = &types.ReplicationRuleFilter{Prefix: aws.String(aws.ToString(r.Prefix))}
+	r.Prefix = nil
+	r.DeleteMarkerReplication = &types.DeleteMarkerReplication{Status: types.DeleteMarkerReplicationStatusEnabled}
+	return r
+}
+
+// PlanReplication checks that source and destination exist and works out
+// the replication configuration that replicates source into destination,
+// keeping the existing rules. Nothing is changed until the plan is applied
+// with ApplyReplication.
+func (c *Client) PlanReplication(ctx context.Context, source, destination string, opts *ReplicationOptions) (*ReplicationPlan, error) {
+	if opts == nil {
+		opts = &ReplicationOptions{}
+	}
+	if parsed, err := arn.Parse(opts.Role); err != nil || parsed.Service != "iam" || !strings.HasPrefix(parsed.Resource, "role/") {
+		return nil, fmt.Errorf("invalid role %q, expected arn:aws:iam::<account>:role/<name>", opts.Role)
+	}
+	if source == destination {
+		return nil, errors.New("source and destination must be different buckets")
+	}
+
+	sourceClient, err := c.BucketClient(ctx, source)
+	if err != nil {
+		return nil, fmt.Errorf("locating source bucket %s: %w", source, err)
+	}
+	destClient, err := c.BucketClient(ctx, destination)
+	if err != nil {
+		return nil, fmt.Errorf("locating destination bucket %s: %w", destination, err)
+	}
+
+	plan := &ReplicationPlan{
+		Source:            source,
+		Destination:       destination,
+		SourceRegion:      sourceClient.Options().Region,
+		DestinationRegion: destClient.Options().Region,
+		RuleID:            opts.RuleID,
+	}
+	if plan.RuleID == "" {
+		plan.RuleID = "replicate-to-" + destination
+	}
+	for _, bucket := range []string{source, destination} {
+		ok, err := c.versioned(ctx, bucket)
+		if err != nil {
+			return nil, fmt.Errorf("getting versioning of %s: %w", bucket, err)
+		}
+		if !ok {
+			plan.Unversioned = append(plan.Unversioned, bucket)
+		}
+	}
+
+	config, err := currentReplication(ctx, sourceClient, source)
+	if err != nil {
+		return nil, fmt.Errorf("getting replication configuration: %w", err)
+	}
+	if config == nil {
+		config = &types.ReplicationConfiguration{}
+	}
+	if existing := aws.ToString(config.Role); existing != "" && existing != opts.Role {
+		plan.ReplacedRole = existing
+	}
+	config.Role = aws.String(opts.Role)
+
+	var priority int32
+	var rules, prefixOnly []types.ReplicationRule
+	for _, r := range config.Rules {
+		if aws.ToString(r.ID) == plan.RuleID {
+			continue
+		}
+		if r.Filter == nil {
+			prefixOnly = append(prefixOnly, r)
+			continue
+		}
+		priority = max(priority, aws.ToInt32(r.Priority))
+		rules = append(rules, r)
+	}
+	plan.PrefixOnly = len(prefixOnly)
+	for _, r := range prefixOnly {
+		priority++
+		r = filterRule(r)
+		r.Priority = aws.Int32(priority)
+		rules = append(rules, r)
+	}
+
+	dest := &types.Destination{Bucket: aws.String(fmt.Sprintf("arn:%s:s3:::%s", Partition(plan.DestinationRegion), destination))}
+	if opts.StorageClass != "" {
+		dest.StorageClass = types.StorageClass(strings.ToUpper(opts.StorageClass))
+	}
+	config.Rules = append(rules, types.ReplicationRule{
+		ID:                      aws.String(plan.RuleID),
+		Status:                  types.ReplicationRuleStatusEnabled,
+		Priority:                aws.Int32(priority + 1),
+		Filter:                  &types.ReplicationRuleFilter{Prefix: aws.String(opts.Prefix)},
+		DeleteMarkerReplication: &types.DeleteMarkerReplication{Status: types.DeleteMarkerReplicationStatusDisabled},
+		Destination:             dest,
+	})
+	plan.config = config
+	return plan, nil
+}
+
+// ApplyReplication enables versioning where the plan needs it and puts the
+// replication configuration of the plan. Only objects written afterwards are
+// replicated.
+func (c *Client) ApplyReplication(ctx context.Context, plan *ReplicationPlan) error {
+	if plan.config == nil {
+		return errors.New("the replication plan was not made by PlanReplication")
+	}
+	for _, bucket := range plan.Unversioned {
+		client, err := c.BucketClient(ctx, bucket)
+		if err != nil {
+			return err
+		}
+		_, err = client.PutBucketVersioning(ctx, &s3.PutBucketVersioningInput{
+			Bucket:                  &bucket,
+			VersioningConfiguration: &types.VersioningConfiguration{Status: types.BucketVersioningStatusEnabled},
+		})
+		if err != nil {
+			return fmt.Errorf("enabling versioning on %s: %w", bucket, err)
+		}
+	}
+
+	client, err := c.BucketClient(ctx, plan.Source)
+	if err != nil {
+		return err
+	}
+	_, err = client.PutBucketReplication(ctx, &s3.PutBucketReplicationInput{
+		Bucket:                   &plan.Source,
+		ReplicationConfiguration: plan.config,
+	})
+	if err != nil {
+		return fmt.Errorf("putting replication configuration: %w", err)
+	}
+	return nil
+}
+
+// ReplicationStatus reports the replication status of the first n objects
+// under prefix. Objects written before replication was set up have none and
+// show as NOT REPLICATED.
+func (c *Client) ReplicationStatus(ctx context.Context, bucket, prefix string, n int) ([]ReplicationSample, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+		Bucket:  &bucket,
+		Prefix:  &prefix,
+		MaxKeys: aws.Int32(int32(n)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var samples []ReplicationSample
+	for _, o := range result.Contents {
+		head, err := client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: &bucket, Key: o.Key})
+		if err != nil {
+			return nil, err
+		}
+		status := string(head.ReplicationStatus)
+		if status == "" {
+			status = "NOT REPLICATED"
+		}
+		samples = append(samples, ReplicationSample{aws.ToString(o.Key), status})
+	}
+	return samples, nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// Restore states of an object.
+const (
+	RestoreAvailable  = "available"
+	RestoreArchived   = "archived"
+	RestoreInProgress = "in-progress"
+	RestoreRestored   = "restored"
+)
+
+// ArchivedClasses are the storage classes whose objects must be restored
+// before they can be read.
+var ArchivedClasses = []types.StorageClass{types.StorageClassGlacier, types.StorageClassDeepArchive}
+
+// restoreHeader matches the x-amz-restore header, for example
+// ongoing-request="false", expiry-date="Fri, 21 Dec 2012 00:00:00 GMT".
+var restoreHeader = regexp.MustCompile(`ongoing-request="(true|false)"(?:,\s*expiry-date="([^"]+)")?`)
+
+// RestoreState is the storage class and restore state of an object. Objects
+// in other storage classes than ArchivedClasses are available right away.
+type RestoreState struct {
+	StorageClass string
+	Size         int64
+	State        string
+	// Expiry is when the restored copy is deleted, if it is known.
+	Expiry *time.Time
+}
+
+// RestoreObjectOptions configures RestoreObject.
+type RestoreObjectOptions struct {
+	// Days is how long the copy is kept. Defaults to 1.
+	Days int
+	// Tier is the retrieval tier, as returned by RestoreTier. Defaults to
+	// Standard.
+	Tier types.Tier
+}
+
+// RestoreTier returns the retrieval tier for Expedited, Standard or Bulk,
+// ignoring case.
+func RestoreTier(name string) (types.Tier, error) {
+	for _, t := range types.Tier("").Values() {
+		if strings.EqualFold(name, string(t)) {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("invalid tier %q, expected Expedited, Standard or Bulk", name)
+}
+
+// parseRestoreHeader returns the state and expiry described by the
+// x-amz-restore header of an archived object.
+func parseRestoreHeader(header string) (string, *time.Time) {
+	m := restoreHeader.FindStringSubmatch(header)
+	if m == nil {
+		return RestoreArchived, nil
+	}
+	if m[1] == "true" {
+		return RestoreInProgress, nil
+	}
+	if expiry, err := http.ParseTime(m[2]); err == nil {
+		return RestoreRestored, &expiry
+	}
+	return RestoreRestored, nil
+}
+
+// RestoreStateOf returns the restore state described by the HeadObject
+// result of an object.
+func RestoreStateOf(head *s3.HeadObjectOutput) *RestoreState {
+	state := &RestoreState{
+		StorageClass: string(head.StorageClass),
+		Size:         aws.ToInt64(head.ContentLength),
+		State:        RestoreAvailable,
+	}
+	if state.StorageClass == "" {
+		state.StorageClass = string(types.StorageClassStandard)
+	}
+	if slices.Contains(ArchivedClasses, head.StorageClass) {
+		state.State, state.Expiry = parseRestoreHeader(aws.ToString(head.Restore))
+	}
+	return state
+}
+
+// ObjectRestoreState returns the storage class and restore state of an
+// object.
+func (c *Client) ObjectRestoreState(ctx context.Context, bucket, key string) (*RestoreState, error) {
+	head, err := c.HeadObject(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	return RestoreStateOf(head), nil
+}
+
+// RestoreObject requests a temporary copy of an archived object. Requesting
+// an object that is already being restored isn't an error, and requesting one
+// that is restored extends its copy to opts.Days from now.
+func (c *Client) RestoreObject(ctx context.Context, bucket, key string, opts *RestoreObjectOptions) error {
+	if opts == nil {
+		opts = &RestoreObjectOptions{}
+	}
+	tier := opts.Tier
+	if tier == "" {
+		tier = types.TierStandard
+	}
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.RestoreObject(ctx, &s3.RestoreObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+		RestoreRequest: &types.RestoreRequest{
+			Days:                 aws.Int32(int32(max(opts.Days, 1))),
+			GlacierJobParameters: &types.GlacierJobParameters{Tier: tier},
+		},
+	})
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "RestoreAlreadyInProgress" {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,87 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// StorageClassStats aggregates the objects of a bucket in one storage class.
+type StorageClassStats struct {
+	Objects int64
+	Bytes   int64
+	// AgedBytes maps an entry of BucketStatsOptions.Ages to the bytes of
+	// objects that are at least that many days old and at least
+	// BucketStatsOptions.MinAgedSize big.
+	AgedBytes map[int]int64 `json:",omitempty"`
+}
+
+// BucketStats holds per storage class statistics for a bucket.
+type BucketStats struct {
+	Bucket  string
+	Region  string
+	Classes map[string]*StorageClassStats
+}
+
+// BucketStatsOptions configures BucketStats.
+type BucketStatsOptions struct {
+	// Ages are the object ages, in days, that AgedBytes are counted for.
+	Ages []int
+	// MinAgedSize is the size of the smallest object counted in AgedBytes.
+	MinAgedSize int64
+}
+
+// BucketStats lists every object in bucket and aggregates them by storage
+// class.
+func (c *Client) BucketStats(ctx context.Context, bucket string, opts *BucketStatsOptions) (*BucketStats, error) {
+	if opts == nil {
+		opts = &BucketStatsOptions{}
+	}
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &BucketStats{
+		Bucket:  bucket,
+		Region:  client.Options().Region,
+		Classes: map[string]*StorageClassStats{},
+	}
+	now := time.Now()
+
+	err = c.WalkObjects(ctx, bucket, "", func(object types.Object) error {
+		class := string(object.StorageClass)
+		if class == "" {
+			class = "STANDARD"
+		}
+		s, ok := stats.Classes[class]
+		if !ok {
+			s = &StorageClassStats{AgedBytes: map[int]int64{}}
+			stats.Classes[class] = s
+		}
+
+		size := aws.ToInt64(object.Size)
+		s.Objects++
+		s.Bytes += size
+
+		if size < opts.MinAgedSize || object.LastModified == nil {
+			return nil
+		}
+		age := int(now.Sub(*object.LastModified).Hours() / 24)
+		for _, days := range opts.Ages {
+			if age >= days {
+				s.AgedBytes[days] += size
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
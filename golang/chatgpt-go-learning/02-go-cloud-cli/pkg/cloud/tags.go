@@ -0,0 +1,110 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// MaxBucketTags is the most tags S3 allows on a bucket.
+const MaxBucketTags = 50
+
+// TagBucketOptions configures TagBucket.
+type TagBucketOptions struct {
+	// Replace drops the existing tags that aren't given instead of keeping
+	// them.
+	Replace bool
+}
+
+// BucketTags returns the tags of a bucket. S3 reports a bucket without tags as
+// an error; this returns an empty map instead.
+func (c *Client) BucketTags(ctx context.Context, bucket string) (map[string]string, error) {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := client.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{Bucket: &bucket})
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchTagSet" {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tags := map[string]string{}
+	for _, t := range result.TagSet {
+		tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+	return tags, nil
+}
+
+// PutBucketTags replaces the tags of a bucket. S3 rejects an empty tag set,
+// so removing the last tag deletes the tagging configuration instead.
+func (c *Client) PutBucketTags(ctx context.Context, bucket string, tags map[string]string) error {
+	if len(tags) > MaxBucketTags {
+		return fmt.Errorf("a bucket can have at most %d tags, got %d", MaxBucketTags, len(tags))
+	}
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	if len(tags) == 0 {
+		_, err := client.DeleteBucketTagging(ctx, &s3.DeleteBucketTaggingInput{Bucket: &bucket})
+		return err
+	}
+
+	var tagSet []types.Tag
+	for _, k := range slices.Sorted(maps.Keys(tags)) {
+		tagSet = append(tagSet, types.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+	_, err = client.PutBucketTagging(ctx, &s3.PutBucketTaggingInput{
+		Bucket:  &bucket,
+		Tagging: &types.Tagging{TagSet: tagSet},
+	})
+	return err
+}
+
+// TagBucket sets tags on a bucket, keeping its other tags unless
+// opts.Replace is set, and returns the resulting tags.
+func (c *Client) TagBucket(ctx context.Context, bucket string, tags map[string]string, opts *TagBucketOptions) (map[string]string, error) {
+	if opts == nil {
+		opts = &TagBucketOptions{}
+	}
+
+	merged := maps.Clone(tags)
+	if !opts.Replace {
+		existing, err := c.BucketTags(ctx, bucket)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(existing, tags)
+		merged = existing
+	}
+
+	return merged, c.PutBucketTags(ctx, bucket, merged)
+}
+
+// UntagBucket removes tags from a bucket and returns the remaining tags.
+func (c *Client) UntagBucket(ctx context.Context, bucket string, keys []string) (map[string]string, error) {
+	tags, err := c.BucketTags(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range keys {
+		delete(tags, k)
+	}
+	return tags, c.PutBucketTags(ctx, bucket, tags)
+}
@@ -0,0 +1,525 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const (
+	// MinPartSize is the smallest part S3 accepts, except for the last one.
+	MinPartSize = 5 << 20
+	// DefaultPartSize is the part size of uploads that don't set one.
+	DefaultPartSize = 64 << 20
+)
+
+// Transfer is a single file moving between the local disk and S3.
+type Transfer struct {
+	Bucket string
+	Key    string
+	Path   string
+	Size   int64
+}
+
+// Progress is told how many bytes of a transfer have moved.
+type Progress interface {
+	// Add counts n more bytes.
+	Add(n int64)
+	// Set moves the count back or forward to n, when a body is rewound to
+	// sign or retry a request.
+	Set(n int64)
+}
+
+// ObjectAttributes are what an upload sets on an object besides its data.
+type ObjectAttributes struct {
+	ObjectMetadata
+	Tags         map[string]string
+	StorageClass types.StorageClass
+}
+
+// UploadOptions configures UploadFile.
+type UploadOptions struct {
+	// ChecksumAlgorithm is CRC32C or SHA256. Defaults to CRC32C.
+	ChecksumAlgorithm types.ChecksumAlgorithm
+	// PartSize is the size of the parts files larger than it are uploaded
+	// in. Defaults to DefaultPartSize.
+	PartSize int64
+	// Encryption is how the object is encrypted. May be nil.
+	Encryption *ObjectEncryption
+	// Attributes are set on the object. May be nil.
+	Attributes *ObjectAttributes
+	// Progress counts the bytes sent. May be nil.
+	Progress Progress
+}
+
+// DownloadOptions configures DownloadFile.
+type DownloadOptions struct {
+	// IfMatch fails the download if the ETag of the object is no longer
+	// this one, e.g. because it changed since it was listed.
+	IfMatch string
+	// Encryption holds the customer key of an SSE-C object. May be nil.
+	Encryption *ObjectEncryption
+	// Progress counts the bytes received. May be nil.
+	Progress Progress
+}
+
+// PlanDownloadOptions configures PlanDownload.
+type PlanDownloadOptions struct {
+	// Concurrency is the number of prefixes listed at once. Defaults to 1.
+	Concurrency int
+	// Encryption holds the customer key needed to look up a single SSE-C
+	// object. May be nil.
+	Encryption *ObjectEncryption
+}
+
+// MultipartError is returned when a multipart upload fails. The upload is
+// aborted, so that its parts aren't billed, even when the context is done;
+// AbortErr is set if that failed too.
+type MultipartError struct {
+	Key      string
+	UploadID string
+	Err      error
+	AbortErr error
+}
+
+func (e *MultipartError) Error() string {
+	if e.AbortErr != nil {
+		return fmt.Sprintf("%v; unable to abort the multipart upload %s of %s, its parts are billed until it is: %v", e.Err, e.UploadID, e.Key, e.AbortErr)
+	}
+	return e.Err.Error()
+}
+
+func (e *MultipartError) Unwrap() error {
+	return e.Err
+}
+
+// noProgress is the Progress of transfers nobody watches.
+type noProgress struct{}
+
+func (noProgress) Add(int64) {}
+func (noProgress) Set(int64) {}
+
+// progressReader counts what is read from a file, or from a part of it that
+// starts at base.
+type progressReader struct {
+	r        io.ReadSeeker
+	progress Progress
+	base     int64
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.progress.Add(int64(n))
+	return n, err
+}
+
+func (r *progressReader) Seek(offset int64, whence int) (int64, error) {
+	pos, err := r.r.Seek(offset, whence)
+	if err == nil {
+		r.progress.Set(r.base + pos)
+	}
+	return pos, err
+}
+
+// progressWriter counts what is written, so that a Progress can sit in a
+// MultiWriter.
+type progressWriter struct {
+	progress Progress
+}
+
+func (w progressWriter) Write(p []byte) (int, error) {
+	w.progress.Add(int64(len(p)))
+	return len(p), nil
+}
+
+// PlanUpload maps a local file or directory to the objects it uploads to.
+// A file uploaded to a prefix ending in "/" keeps its name.
+func PlanUpload(src, bucket, prefix string) ([]Transfer, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		key := prefix
+		if key == "" || strings.HasSuffix(key, "/") {
+			key += filepath.Base(src)
+		}
+		return []Transfer{{bucket, key, src, info.Size()}}, nil
+	}
+
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	var transfers []Transfer
+	err = filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		transfers = append(transfers, Transfer{bucket, prefix + filepath.ToSlash(rel), p, info.Size()})
+		return nil
+	})
+	return transfers, err
+}
+
+// LocalPath returns the path of rel, a slash separated key relative to a
+// prefix, inside dir. Keys are chosen by whoever can write to the bucket, so
+// keys that would land outside dir, such as ../.bashrc or /etc/passwd, are
+// rejected.
+func LocalPath(dir, rel string) (string, error) {
+	local := filepath.FromSlash(rel)
+	if !filepath.IsLocal(local) {
+		return "", fmt.Errorf("key %q would be written outside %s", rel, dir)
+	}
+	return filepath.Join(dir, local), nil
+}
+
+// PlanDownload maps an object, or every object under a prefix ending in "/",
+// to the local files it downloads to. It fails if a key would be written
+// outside dest.
+func (c *Client) PlanDownload(ctx context.Context, bucket, prefix, dest string, opts *PlanDownloadOptions) ([]Transfer, error) {
+	if opts == nil {
+		opts = &PlanDownloadOptions{}
+	}
+
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		client, err := c.BucketClient(ctx, bucket)
+		if err != nil {
+			return nil, err
+		}
+		headInput := &s3.HeadObjectInput{Bucket: &bucket, Key: &prefix}
+		opts.Encryption.applyHead(headInput)
+		head, err := client.HeadObject(ctx, headInput)
+		if err != nil {
+			return nil, err
+		}
+		target := dest
+		if info, err := os.Stat(dest); err == nil && info.IsDir() {
+			target, err = LocalPath(dest, path.Base(prefix))
+			if err != nil {
+				return nil, err
+			}
+		}
+		return []Transfer{{bucket, prefix, target, aws.ToInt64(head.ContentLength)}}, nil
+	}
+
+	objects, err := c.ListObjects(ctx, bucket, prefix, &ListObjectsOptions{Concurrency: opts.Concurrency})
+	if err != nil {
+		return nil, err
+	}
+	var transfers []Transfer
+	for _, o := range objects {
+		key := aws.ToString(o.Key)
+		if strings.HasSuffix(key, "/") {
+			continue
+		}
+		target, err := LocalPath(dest, strings.TrimPrefix(key, prefix))
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, Transfer{bucket, key, target, aws.ToInt64(o.Size)})
+	}
+	return transfers, nil
+}
+
+// tagging encodes the tags the way uploads take them.
+func (a *ObjectAttributes) tagging() *string {
+	if len(a.Tags) == 0 {
+		return nil
+	}
+	tags := url.Values{}
+	for k, v := range a.Tags {
+		tags.Set(k, v)
+	}
+	return aws.String(tags.Encode())
+}
+
+func (a *ObjectAttributes) applyPut(input *s3.PutObjectInput) {
+	if a == nil {
+		return
+	}
+	input.ContentType = nonEmpty(a.ContentType)
+	input.CacheControl = nonEmpty(a.CacheControl)
+	input.ContentDisposition = nonEmpty(a.ContentDisposition)
+	input.ContentEncoding = nonEmpty(a.ContentEncoding)
+	input.ContentLanguage = nonEmpty(a.ContentLanguage)
+	input.Metadata = a.Metadata
+	input.Tagging = a.tagging()
+	input.StorageClass = a.StorageClass
+}
+
+func (a *ObjectAttributes) applyCreateMultipart(input *s3.CreateMultipartUploadInput) {
+	if a == nil {
+		return
+	}
+	input.ContentType = nonEmpty(a.ContentType)
+	input.CacheControl = nonEmpty(a.CacheControl)
+	input.ContentDisposition = nonEmpty(a.ContentDisposition)
+	input.ContentEncoding = nonEmpty(a.ContentEncoding)
+	input.ContentLanguage = nonEmpty(a.ContentLanguage)
+	input.Metadata = a.Metadata
+	input.Tagging = a.tagging()
+	input.StorageClass = a.StorageClass
+}
+
+// UploadFile uploads a file with a checksum that S3 verifies on receipt,
+// rejecting the upload if the data was corrupted on the way. Files larger
+// than the part size are uploaded in parts.
+func (c *Client) UploadFile(ctx context.Context, t Transfer, opts *UploadOptions) error {
+	o := UploadOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.ChecksumAlgorithm == "" {
+		o.ChecksumAlgorithm = types.ChecksumAlgorithmCrc32c
+	}
+	if o.PartSize == 0 {
+		o.PartSize = DefaultPartSize
+	}
+	if o.PartSize < MinPartSize {
+		return fmt.Errorf("part size %d is below the minimum of 5 MiB", o.PartSize)
+	}
+	if o.Progress == nil {
+		o.Progress = noProgress{}
+	}
+
+	client, err := c.BucketClient(ctx, t.Bucket)
+	if err != nil {
+		return err
+	}
+	if t.Size > o.PartSize {
+		return uploadMultipart(ctx, client, t, &o)
+	}
+
+	checksum, err := FileChecksum(t.Path, o.ChecksumAlgorithm)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(t.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	input := &s3.PutObjectInput{
+		Bucket:            &t.Bucket,
+		Key:               &t.Key,
+		Body:              &progressReader{f, o.Progress, 0},
+		ContentLength:     aws.Int64(t.Size),
+		ChecksumAlgorithm: o.ChecksumAlgorithm,
+	}
+	if o.ChecksumAlgorithm == types.ChecksumAlgorithmSha256 {
+		input.ChecksumSHA256 = &checksum
+	} else {
+		input.ChecksumCRC32C = &checksum
+	}
+	o.Encryption.applyPut(input)
+	o.Attributes.applyPut(input)
+
+	result, err := client.PutObject(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	if _, stored, ok := StoredChecksum(result.ChecksumCRC32C, result.ChecksumSHA256); ok && stored != checksum {
+		return fmt.Errorf("checksum mismatch: sent %s, stored %s", checksum, stored)
+	}
+	return nil
+}
+
+// uploadMultipart uploads a file in parts, each with its own checksum. CRC32C
+// checksums of the parts combine into the checksum of the whole file, which
+// S3 verifies when the upload completes; SHA256 ones can only be combined
+// into a checksum of checksums. An upload that fails or is interrupted is
+// aborted, so that its parts aren't left behind to be billed.
+func uploadMultipart(ctx context.Context, client *s3.Client, t Transfer, o *UploadOptions) error {
+	// S3 allows at most 10000 parts.
+	partSize := max(o.PartSize, (t.Size+9999)/10000)
+	checksumType := types.ChecksumTypeComposite
+	if o.ChecksumAlgorithm == types.ChecksumAlgorithmCrc32c {
+		checksumType = types.ChecksumTypeFullObject
+	}
+
+	f, err := os.Open(t.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	createInput := &s3.CreateMultipartUploadInput{
+		Bucket:            &t.Bucket,
+		Key:               &t.Key,
+		ChecksumAlgorithm: o.ChecksumAlgorithm,
+		ChecksumType:      checksumType,
+	}
+	o.Encryption.applyCreateMultipart(createInput)
+	o.Attributes.applyCreateMultipart(createInput)
+	created, err := client.CreateMultipartUpload(ctx, createInput)
+	if err != nil {
+		return err
+	}
+
+	err = uploadParts(ctx, client, t, f, created.UploadId, partSize, checksumType, o)
+	if err == nil {
+		return nil
+	}
+
+	// The upload must be aborted even when ctx was cancelled.
+	abortCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+	_, abortErr := client.AbortMultipartUpload(abortCtx, &s3.AbortMultipartUploadInput{
+		Bucket:   &t.Bucket,
+		Key:      &t.Key,
+		UploadId: created.UploadId,
+	})
+	return &MultipartError{Key: t.Key, UploadID: aws.ToString(created.UploadId), Err: err, AbortErr: abortErr}
+}
+
+// uploadParts uploads the parts of a multipart upload one after the other
+// and completes it.
+func uploadParts(ctx context.Context, client *s3.Client, t Transfer, f *os.File, uploadID *string, partSize int64, checksumType types.ChecksumType, o *UploadOptions) error {
+	full := newChecksumHash(o.ChecksumAlgorithm)
+	var parts []types.CompletedPart
+	for number, offset := int32(1), int64(0); offset < t.Size; number, offset = number+1, offset+partSize {
+		size := min(partSize, t.Size-offset)
+		section := io.NewSectionReader(f, offset, size)
+
+		h := newChecksumHash(o.ChecksumAlgorithm)
+		if _, err := io.Copy(io.MultiWriter(h, full), section); err != nil {
+			return err
+		}
+		if _, err := section.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		partChecksum := encodeChecksum(h)
+
+		input := &s3.UploadPartInput{
+			Bucket:            &t.Bucket,
+			Key:               &t.Key,
+			UploadId:          uploadID,
+			PartNumber:        aws.Int32(number),
+			Body:              &progressReader{section, o.Progress, offset},
+			ContentLength:     aws.Int64(size),
+			ChecksumAlgorithm: o.ChecksumAlgorithm,
+		}
+		part := types.CompletedPart{PartNumber: aws.Int32(number)}
+		if o.ChecksumAlgorithm == types.ChecksumAlgorithmSha256 {
+			input.ChecksumSHA256, part.ChecksumSHA256 = &partChecksum, &partChecksum
+		} else {
+			input.ChecksumCRC32C, part.ChecksumCRC32C = &partChecksum, &partChecksum
+		}
+		o.Encryption.applyUploadPart(input)
+
+		result, err := client.UploadPart(ctx, input)
+		if err != nil {
+			return fmt.Errorf("part %d: %w", number, err)
+		}
+		part.ETag = result.ETag
+		parts = append(parts, part)
+	}
+
+	input := &s3.CompleteMultipartUploadInput{
+		Bucket:          &t.Bucket,
+		Key:             &t.Key,
+		UploadId:        uploadID,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+		ChecksumType:    checksumType,
+		MpuObjectSize:   aws.Int64(t.Size),
+	}
+	checksum := ""
+	if checksumType == types.ChecksumTypeFullObject {
+		checksum = encodeChecksum(full)
+		input.ChecksumCRC32C = &checksum
+	}
+	o.Encryption.applyComplete(input)
+
+	result, err := client.CompleteMultipartUpload(ctx, input)
+	if err != nil {
+		return err
+	}
+	if _, stored, ok := StoredChecksum(result.ChecksumCRC32C, nil); ok && checksum != "" && stored != checksum {
+		return fmt.Errorf("checksum mismatch: sent %s, stored %s", checksum, stored)
+	}
+	return nil
+}
+
+// DownloadFile downloads an object into a temporary file, verifies it against
+// the checksum S3 stored for the object and only then moves it into place.
+// It reports whether the object could be verified: objects uploaded without
+// a full object checksum are downloaded as they are.
+func (c *Client) DownloadFile(ctx context.Context, t Transfer, opts *DownloadOptions) (bool, error) {
+	o := DownloadOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Progress == nil {
+		o.Progress = noProgress{}
+	}
+
+	client, err := c.BucketClient(ctx, t.Bucket)
+	if err != nil {
+		return false, err
+	}
+	input := &s3.GetObjectInput{
+		Bucket:       &t.Bucket,
+		Key:          &t.Key,
+		ChecksumMode: types.ChecksumModeEnabled,
+		IfMatch:      nonEmpty(o.IfMatch),
+	}
+	o.Encryption.applyGet(input)
+	result, err := client.GetObject(ctx, input)
+	if err != nil {
+		return false, err
+	}
+	defer result.Body.Close()
+
+	if err := os.MkdirAll(filepath.Dir(t.Path), 0o755); err != nil {
+		return false, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(t.Path), ".download-*")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name())
+
+	algorithm, stored, verifiable := StoredChecksum(result.ChecksumCRC32C, result.ChecksumSHA256)
+	h := newChecksumHash(algorithm)
+
+	if _, err := io.Copy(io.MultiWriter(tmp, h, progressWriter{o.Progress}), result.Body); err != nil {
+		tmp.Close()
+		return false, err
+	}
+	if err := tmp.Close(); err != nil {
+		return false, err
+	}
+
+	if verifiable {
+		if got := encodeChecksum(h); got != stored {
+			return false, fmt.Errorf("checksum mismatch: expected %s %s, got %s", algorithm, stored, got)
+		}
+	}
+	return verifiable, os.Rename(tmp.Name(), t.Path)
+}
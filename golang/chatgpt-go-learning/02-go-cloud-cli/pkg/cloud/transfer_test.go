@@ -0,0 +1,75 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalPath(t *testing.T) {
+	dir := filepath.Join("tmp", "dest")
+	tests := []struct {
+		rel  string
+		want string
+	}{
+		{"a.txt", filepath.Join(dir, "a.txt")},
+		{"sub/b.txt", filepath.Join(dir, "sub", "b.txt")},
+		{"sub/../c.txt", filepath.Join(dir, "c.txt")},
+		{"../.bashrc", ""},
+		{"sub/../../.bashrc", ""},
+		{"/etc/passwd", ""},
+		{"..", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got, err := LocalPath(dir, tt.rel)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("LocalPath(%q) = %q, want an error", tt.rel, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("LocalPath(%q) = %q, %v, want %q", tt.rel, got, err, tt.want)
+		}
+	}
+}
+
+func TestPlanDownloadRejectsKeysOutsideDest(t *testing.T) {
+	_, client := newFakeS3(t, "docs", map[string]fakeObject{
+		"site/index.html":         {Size: 1},
+		"site/../../.bashrc":      {Size: 2},
+		"site/assets/../logo.png": {Size: 3},
+	})
+	dest := t.TempDir()
+
+	_, err := client.PlanDownload(context.Background(), "docs", "site/", dest, nil)
+	if err == nil || !strings.Contains(err.Error(), ".bashrc") {
+		t.Fatalf("PlanDownload() error = %v, want the key escaping %s rejected", err, dest)
+	}
+}
+
+func TestPlanDownloadKeepsKeysInsideDest(t *testing.T) {
+	_, client := newFakeS3(t, "docs", map[string]fakeObject{
+		"site/index.html":         {Size: 1},
+		"site/assets/../logo.png": {Size: 3},
+	})
+	dest := t.TempDir()
+
+	transfers, err := client.PlanDownload(context.Background(), "docs", "site/", dest, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tr := range transfers {
+		if !strings.HasPrefix(tr.Path, dest+string(filepath.Separator)) {
+			t.Errorf("%s downloads to %s, outside %s", tr.Key, tr.Path, dest)
+		}
+	}
+	if len(transfers) != 2 {
+		t.Errorf("got %d transfers, want 2", len(transfers))
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// VerifyOptions configures Verify.
+type VerifyOptions struct {
+	// Concurrency is the number of objects checked at once. Defaults to 1.
+	Concurrency int
+}
+
+// VerifyEntry is a single finding of an integrity report.
+type VerifyEntry struct {
+	Key       string
+	Path      string `json:",omitempty"`
+	Algorithm string `json:",omitempty"`
+	Local     string `json:",omitempty"`
+	Stored    string `json:",omitempty"`
+	Reason    string `json:",omitempty"`
+}
+
+// VerifyReport is the result of Verify.
+type VerifyReport struct {
+	Bucket       string
+	Prefix       string
+	Directory    string
+	CheckedAt    time.Time
+	Checked      int
+	Matched      int
+	Mismatched   []VerifyEntry
+	Missing      []VerifyEntry
+	Extra        []VerifyEntry
+	Unverifiable []VerifyEntry
+}
+
+// Failed reports whether a file didn't match its object, or had none, or an
+// object had no file. Unverifiable objects don't fail a report.
+func (r *VerifyReport) Failed() bool {
+	return len(r.Mismatched)+len(r.Missing)+len(r.Extra) > 0
+}
+
+// Verify compares the checksums S3 stored for the objects under prefix, taken
+// as a folder, with the files in dir. Objects uploaded without a flexible
+// checksum are compared by MD5 when their ETag allows it, and reported as
+// unverifiable otherwise.
+func (c *Client) Verify(ctx context.Context, bucket, prefix, dir string, opts *VerifyOptions) (*VerifyReport, error) {
+	if opts == nil {
+		opts = &VerifyOptions{}
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	local, err := PlanUpload(dir, bucket, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", dir, err)
+	}
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := c.ListObjects(ctx, bucket, prefix, &ListObjectsOptions{Concurrency: opts.Concurrency})
+	if err != nil {
+		return nil, fmt.Errorf("listing objects: %w", err)
+	}
+
+	report := &VerifyReport{
+		Bucket:    bucket,
+		Prefix:    prefix,
+		Directory: dir,
+		CheckedAt: time.Now().UTC(),
+	}
+
+	remote := map[string]types.Object{}
+	for _, o := range objects {
+		if key := aws.ToString(o.Key); !strings.HasSuffix(key, "/") {
+			remote[key] = o
+		}
+	}
+
+	var pairs []Transfer
+	for _, t := range local {
+		if _, ok := remote[t.Key]; !ok {
+			report.Missing = append(report.Missing, VerifyEntry{Key: t.Key, Path: t.Path})
+			continue
+		}
+		delete(remote, t.Key)
+		pairs = append(pairs, t)
+	}
+	for key := range remote {
+		report.Extra = append(report.Extra, VerifyEntry{Key: key})
+	}
+
+	var mu sync.Mutex
+	sem := make(chan struct{}, max(opts.Concurrency, 1))
+	var wg sync.WaitGroup
+	for _, t := range pairs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			entry, matched := verifyObject(ctx, client, t)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checked++
+			switch {
+			case matched:
+				report.Matched++
+			case entry.Reason == "":
+				report.Mismatched = append(report.Mismatched, entry)
+			default:
+				report.Unverifiable = append(report.Unverifiable, entry)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, entries := range [][]VerifyEntry{report.Mismatched, report.Missing, report.Extra, report.Unverifiable} {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	}
+	return report, nil
+}
+
+// verifyObject compares one object with its local file. An entry with a
+// Reason could not be verified; one without is a mismatch.
+func verifyObject(ctx context.Context, client *s3.Client, t Transfer) (VerifyEntry, bool) {
+	entry := VerifyEntry{Key: t.Key, Path: t.Path}
+
+	head, err := client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:       &t.Bucket,
+		Key:          &t.Key,
+		ChecksumMode: types.ChecksumModeEnabled,
+	})
+	if err != nil {
+		entry.Reason = err.Error()
+		return entry, false
+	}
+
+	if size := aws.ToInt64(head.ContentLength); size != t.Size {
+		entry.Algorithm = "SIZE"
+		entry.Local = fmt.Sprint(t.Size)
+		entry.Stored = fmt.Sprint(size)
+		return entry, false
+	}
+
+	algorithm, stored, ok := StoredChecksum(head.ChecksumCRC32C, head.ChecksumSHA256)
+	if !ok {
+		if head.ServerSideEncryption == types.ServerSideEncryptionAwsKms {
+			entry.Reason = "no full object checksum stored and the ETag of a KMS encrypted object is not its MD5"
+			return entry, false
+		}
+		return verifyByETag(t, strings.Trim(aws.ToString(head.ETag), `"`))
+	}
+
+	local, err := FileChecksum(t.Path, algorithm)
+	if err != nil {
+		entry.Reason = err.Error()
+		return entry, false
+	}
+	entry.Algorithm, entry.Local, entry.Stored = string(algorithm), local, stored
+	return entry, local == stored
+}
+
+// verifyByETag falls back to the ETag, which is the MD5 of the object for
+// single part uploads without KMS encryption.
+func verifyByETag(t Transfer, etag string) (VerifyEntry, bool) {
+	entry := VerifyEntry{Key: t.Key, Path: t.Path}
+	if len(etag) != 32 {
+		entry.Reason = "no full object checksum stored"
+		return entry, false
+	}
+
+	local, err := FileMD5(t.Path)
+	if err != nil {
+		entry.Reason = err.Error()
+		return entry, false
+	}
+
+	entry.Algorithm, entry.Local, entry.Stored = "MD5", local, etag
+	return entry, entry.Local == etag
+}
@@ -0,0 +1,200 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+*/
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// legacyWebsiteRegions use a dash instead of a dot between "s3-website" and
+// the region in their website endpoints.
+var legacyWebsiteRegions = map[string]bool{
+	"us-east-1":      true,
+	"us-west-1":      true,
+	"us-west-2":      true,
+	"ap-southeast-1": true,
+	"ap-southeast-2": true,
+	"ap-northeast-1": true,
+	"eu-west-1":      true,
+	"sa-east-1":      true,
+	"us-gov-west-1":  true,
+}
+
+// publicReadSid identifies the statement that makes a website bucket public.
+const publicReadSid = "PublicReadGetObject"
+
+// Website is the website configuration of a bucket.
+type Website struct {
+	Bucket        string
+	Enabled       bool
+	IndexDocument string `json:",omitempty"`
+	ErrorDocument string `json:",omitempty"`
+	Endpoint      string `json:",omitempty"`
+}
+
+// EnableWebsiteOptions configures EnableWebsite.
+type EnableWebsiteOptions struct {
+	// IndexDocument is served for requests to a directory. Defaults to
+	// index.html.
+	IndexDocument string
+	// ErrorDocument is served when an error occurs.
+	ErrorDocument string
+}
+
+// websiteEndpoint returns the URL a bucket's website is served from.
+func websiteEndpoint(bucket, region string) string {
+	separator := "."
+	if legacyWebsiteRegions[region] {
+		separator = "-"
+	}
+	return fmt.Sprintf("http://%s.s3-website%s%s.amazonaws.com", bucket, separator, region)
+}
+
+// Website returns the website configuration of a bucket.
+func (c *Client) Website(ctx context.Context, bucket string) (*Website, error) {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	website := &Website{Bucket: bucket}
+	result, err := client.GetBucketWebsite(ctx, &s3.GetBucketWebsiteInput{Bucket: &bucket})
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchWebsiteConfiguration" {
+		return website, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	website.Enabled = true
+	website.Endpoint = websiteEndpoint(bucket, client.Options().Region)
+	if result.IndexDocument != nil {
+		website.IndexDocument = aws.ToString(result.IndexDocument.Suffix)
+	}
+	if result.ErrorDocument != nil {
+		website.ErrorDocument = aws.ToString(result.ErrorDocument.Key)
+	}
+	return website, nil
+}
+
+// EnableWebsite configures a bucket to host a static website and returns the
+// resulting configuration. The objects aren't made readable; see
+// AllowPublicRead.
+func (c *Client) EnableWebsite(ctx context.Context, bucket string, opts *EnableWebsiteOptions) (*Website, error) {
+	if opts == nil {
+		opts = &EnableWebsiteOptions{}
+	}
+	index := opts.IndexDocument
+	if index == "" {
+		index = "index.html"
+	}
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &types.WebsiteConfiguration{
+		IndexDocument: &types.IndexDocument{Suffix: &index},
+	}
+	if opts.ErrorDocument != "" {
+		config.ErrorDocument = &types.ErrorDocument{Key: &opts.ErrorDocument}
+	}
+	_, err = client.PutBucketWebsite(ctx, &s3.PutBucketWebsiteInput{
+		Bucket:               &bucket,
+		WebsiteConfiguration: config,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Website{
+		Bucket:        bucket,
+		Enabled:       true,
+		IndexDocument: index,
+		ErrorDocument: opts.ErrorDocument,
+		Endpoint:      websiteEndpoint(bucket, client.Options().Region),
+	}, nil
+}
+
+// DisableWebsite removes the website configuration of a bucket. A public read
+// policy is left in place.
+func (c *Client) DisableWebsite(ctx context.Context, bucket string) error {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	_, err = client.DeleteBucketWebsite(ctx, &s3.DeleteBucketWebsiteInput{Bucket: &bucket})
+	return err
+}
+
+// AllowPublicRead lets anyone read every object in a bucket. It relaxes the
+// public access block of the bucket, without which S3 rejects the policy, and
+// adds a public read statement to the statements already in its policy.
+func (c *Client) AllowPublicRead(ctx context.Context, bucket string) error {
+	if err := c.allowPublicPolicy(ctx, bucket); err != nil {
+		return fmt.Errorf("updating the public access block: %w", err)
+	}
+
+	policy, statements, err := c.BucketPolicy(ctx, bucket)
+	if err != nil {
+		return fmt.Errorf("reading the bucket policy: %w", err)
+	}
+	if hasStatement(statements, publicReadSid) {
+		return nil
+	}
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	policy["Statement"] = append(statements, map[string]any{
+		"Sid":       publicReadSid,
+		"Effect":    "Allow",
+		"Principal": "*",
+		"Action":    "s3:GetObject",
+		"Resource":  "arn:" + Partition(client.Options().Region) + ":s3:::" + bucket + "/*",
+	})
+	if err := c.PutBucketPolicy(ctx, bucket, policy); err != nil {
+		return fmt.Errorf("applying the public read policy: %w", err)
+	}
+	return nil
+}
+
+// allowPublicPolicy turns off the two settings of the public access block of
+// a bucket that reject public bucket policies, and keeps the ones that block
+// public ACLs as they are.
+func (c *Client) allowPublicPolicy(ctx context.Context, bucket string) error {
+	client, err := c.BucketClient(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	result, err := client.GetPublicAccessBlock(ctx, &s3.GetPublicAccessBlockInput{Bucket: &bucket})
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchPublicAccessBlockConfiguration" {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	block := result.PublicAccessBlockConfiguration
+	if !aws.ToBool(block.BlockPublicPolicy) && !aws.ToBool(block.RestrictPublicBuckets) {
+		return nil
+	}
+	block.BlockPublicPolicy = aws.Bool(false)
+	block.RestrictPublicBuckets = aws.Bool(false)
+	_, err = client.PutPublicAccessBlock(ctx, &s3.PutPublicAccessBlockInput{
+		Bucket:                         &bucket,
+		PublicAccessBlockConfiguration: block,
+	})
+	return err
+}